@@ -20,6 +20,7 @@ type Aggregate struct {
 	arg      string
 	alias    string
 	distinct bool
+	filter   []Predicate
 }
 
 func (a Aggregate) selectedAlias() string {
@@ -37,12 +38,22 @@ func (a Aggregate) fieldName() string {
 // As specifies the alias
 func (a Aggregate) As(alias string) Selectable {
 	return Aggregate{
-		fn:    a.fn,
-		arg:   a.arg,
-		alias: alias,
+		fn:       a.fn,
+		arg:      a.arg,
+		alias:    alias,
+		distinct: a.distinct,
+		filter:   a.filter,
 	}
 }
 
+// Filter 只对满足条件的行进行聚合，多个 Predicate 之间使用 AND 连接
+// 在 PostgreSQL 和 SQLite 中会使用 FILTER (WHERE ...)，
+// MySQL 不支持 FILTER，会被转换为 fn(CASE WHEN ... THEN col END)
+func (a Aggregate) Filter(ps ...Predicate) Aggregate {
+	a.filter = ps
+	return a
+}
+
 // Avg represents AVG
 func Avg(c string) Aggregate {
 	return Aggregate{
@@ -75,6 +86,14 @@ func Count(c string) Aggregate {
 	}
 }
 
+// CountAll represents COUNT(*)
+func CountAll() Aggregate {
+	return Aggregate{
+		fn:  "COUNT",
+		arg: "*",
+	}
+}
+
 // Sum represents SUM
 func Sum(c string) Aggregate {
 	return Aggregate{
@@ -83,6 +102,19 @@ func Sum(c string) Aggregate {
 	}
 }
 
+// Median 计算中位数
+// 在 PostgreSQL 上会被翻译为 PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY col)。
+// MySQL 没有中位数函数，会使用 GROUP_CONCAT 排序之后取中间的值，这只是一个近似值：
+// 数据个数是偶数的时候得到的是中间两个值中较小的一个，而不是它们的平均值；
+// 并且结果是字符串，还会受到 group_concat_max_len 的限制，数据量很大的时候可能会被截断。
+// MySQL 上的 Median 不支持 Filter，其余方言不支持 Median；Median 也不能通过 Over 作为窗口函数使用
+func Median(c string) Aggregate {
+	return Aggregate{
+		fn:  "MEDIAN",
+		arg: c,
+	}
+}
+
 // CountDistinct represents COUNT(DISTINCT XXX)
 func CountDistinct(col string) Aggregate {
 	a := Count(col)
@@ -158,3 +190,116 @@ func (a Aggregate) GTEQ(val interface{}) Predicate {
 func (Aggregate) expr() (string, error) {
 	return "", nil
 }
+
+// GroupConcatExpr represents GROUP_CONCAT
+// 在 PostgreSQL 中会使用 STRING_AGG
+type GroupConcatExpr struct {
+	arg       string
+	alias     string
+	distinct  bool
+	orderBy   []OrderBy
+	separator string
+}
+
+// GroupConcat represents GROUP_CONCAT(col)，默认的分隔符是 ","
+func GroupConcat(col string) GroupConcatExpr {
+	return GroupConcatExpr{
+		arg:       col,
+		separator: ",",
+	}
+}
+
+// Distinct 去重
+func (g GroupConcatExpr) Distinct() GroupConcatExpr {
+	g.distinct = true
+	return g
+}
+
+// OrderBy 指定拼接的顺序
+func (g GroupConcatExpr) OrderBy(orderBys ...OrderBy) GroupConcatExpr {
+	g.orderBy = orderBys
+	return g
+}
+
+// Separator 指定分隔符
+func (g GroupConcatExpr) Separator(sep string) GroupConcatExpr {
+	g.separator = sep
+	return g
+}
+
+// As specifies the alias
+func (g GroupConcatExpr) As(alias string) Selectable {
+	g.alias = alias
+	return g
+}
+
+func (g GroupConcatExpr) selectedAlias() string {
+	return g.alias
+}
+
+func (GroupConcatExpr) selectedTable() TableReference {
+	return nil
+}
+
+func (g GroupConcatExpr) fieldName() string {
+	return g.arg
+}
+
+func (GroupConcatExpr) expr() (string, error) {
+	return "", nil
+}
+
+// BoolAggregate represents BOOL_OR and BOOL_AND
+// 在 PostgreSQL 之外的方言里，会使用 MAX 和 MIN 来代替
+type BoolAggregate struct {
+	fn    string
+	arg   Expr
+	alias string
+}
+
+// BoolOr represents BOOL_OR(expr)，只要有一行的 expr 为真，结果就为真
+// expr 可以是一个 Predicate，也可以是一个布尔类型的列
+func BoolOr(expr Expr) BoolAggregate {
+	return BoolAggregate{
+		fn:  "BOOL_OR",
+		arg: expr,
+	}
+}
+
+// BoolAnd represents BOOL_AND(expr)，只有所有行的 expr 都为真，结果才为真
+// expr 可以是一个 Predicate，也可以是一个布尔类型的列
+func BoolAnd(expr Expr) BoolAggregate {
+	return BoolAggregate{
+		fn:  "BOOL_AND",
+		arg: expr,
+	}
+}
+
+// As specifies the alias
+func (b BoolAggregate) As(alias string) Selectable {
+	b.alias = alias
+	return b
+}
+
+// AsPredicate 将 BoolAggregate 作为一个 Predicate，一般用于 HAVING
+func (b BoolAggregate) AsPredicate() Predicate {
+	return Predicate{
+		left: b,
+	}
+}
+
+func (b BoolAggregate) selectedAlias() string {
+	return b.alias
+}
+
+func (BoolAggregate) selectedTable() TableReference {
+	return nil
+}
+
+func (BoolAggregate) fieldName() string {
+	return ""
+}
+
+func (BoolAggregate) expr() (string, error) {
+	return "", nil
+}
@@ -18,7 +18,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAggregate(t *testing.T) {
@@ -59,6 +62,11 @@ func TestAggregate(t *testing.T) {
 			builder: NewSelector[TestModel](db).Select(AvgDistinct("FirstName")),
 			wantSql: "SELECT AVG(DISTINCT `first_name`) FROM `test_model`;",
 		},
+		{
+			name:    "count distinct alias",
+			builder: NewSelector[TestModel](db).Select(CountDistinct("FirstName").As("cnt")),
+			wantSql: "SELECT COUNT(DISTINCT `first_name`) AS `cnt` FROM `test_model`;",
+		},
 		{
 			name:    "SUM distinct",
 			builder: NewSelector[TestModel](db).Select(SumDistinct("FirstName")),
@@ -80,6 +88,288 @@ func TestAggregate(t *testing.T) {
 	}
 }
 
+func TestAggregate_Filter(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name: "postgres pivot",
+			builder: NewSelector[TestModel](pg).Select(C("Age"),
+				CountAll().Filter(C("FirstName").EQ("a")).As("a_cnt"),
+				CountAll().Filter(C("FirstName").EQ("b")).As("b_cnt")).
+				Where(C("Id").GT(10)).GroupBy("Age").Having(C("Age").LT(60)),
+			wantSql: `SELECT "age",COUNT(*) FILTER (WHERE "first_name"=$1) AS "a_cnt",` +
+				`COUNT(*) FILTER (WHERE "first_name"=$2) AS "b_cnt" FROM "test_model" WHERE "id">$3 GROUP BY "age" HAVING "age"<$4;`,
+			wantArgs: []interface{}{"a", "b", 10, 60},
+		},
+		{
+			name: "postgres multiple predicates",
+			builder: NewSelector[TestModel](pg).Select(
+				Sum("Age").Filter(C("FirstName").EQ("a"), C("Id").GT(1)).As("age_sum")),
+			wantSql:  `SELECT SUM("age") FILTER (WHERE ("first_name"=$1) AND ("id">$2)) AS "age_sum" FROM "test_model";`,
+			wantArgs: []interface{}{"a", 1},
+		},
+		{
+			name: "sqlite pivot",
+			builder: NewSelector[TestModel](memoryDB()).Select(
+				CountAll().Filter(C("FirstName").EQ("a")).As("a_cnt"),
+				CountDistinct("Age").Filter(C("FirstName").EQ("b")).As("b_cnt")),
+			wantSql:  "SELECT COUNT(*) FILTER (WHERE `first_name`=?) AS `a_cnt`,COUNT(DISTINCT `age`) FILTER (WHERE `first_name`=?) AS `b_cnt` FROM `test_model`;",
+			wantArgs: []interface{}{"a", "b"},
+		},
+		{
+			name: "mysql pivot",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Age"),
+				CountAll().Filter(C("FirstName").EQ("a")).As("a_cnt"),
+				CountAll().Filter(C("FirstName").EQ("b")).As("b_cnt")).
+				Where(C("Id").GT(10)).GroupBy("Age"),
+			wantSql: "SELECT `age`,COUNT(CASE WHEN `first_name`=? THEN 1 END) AS `a_cnt`," +
+				"COUNT(CASE WHEN `first_name`=? THEN 1 END) AS `b_cnt` FROM `test_model` WHERE `id`>? GROUP BY `age`;",
+			wantArgs: []interface{}{"a", "b", 10},
+		},
+		{
+			name: "mysql distinct",
+			builder: NewSelector[TestModel](mysqlDB).Select(
+				CountDistinct("Age").Filter(C("FirstName").EQ("a")).As("a_cnt")),
+			wantSql:  "SELECT COUNT(DISTINCT CASE WHEN `first_name`=? THEN `age` END) AS `a_cnt` FROM `test_model`;",
+			wantArgs: []interface{}{"a"},
+		},
+		{
+			name:    "count all",
+			builder: NewSelector[TestModel](mysqlDB).Select(CountAll()),
+			wantSql: "SELECT COUNT(*) FROM `test_model`;",
+		},
+		{
+			name:    "invalid filter column",
+			builder: NewSelector[TestModel](pg).Select(CountAll().Filter(C("Invalid").EQ("a"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestMedian(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:    "postgres",
+			builder: NewSelector[TestModel](pg).Select(C("FirstName"), Median("Age").As("median_age")).GroupBy("FirstName"),
+			wantSql: `SELECT "first_name",PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY "age") AS "median_age" FROM "test_model" GROUP BY "first_name";`,
+		},
+		{
+			name: "postgres filter and having",
+			builder: NewSelector[TestModel](pg).Select(Median("Age").Filter(C("Id").GT(10)).As("median_age")).
+				GroupBy("FirstName").Having(Median("Age").GT(18)),
+			wantSql: `SELECT PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY "age") FILTER (WHERE "id">$1) AS "median_age" ` +
+				`FROM "test_model" GROUP BY "first_name" HAVING PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY "age")>$2;`,
+			wantArgs: []interface{}{10, 18},
+		},
+		{
+			name:    "mysql",
+			builder: NewSelector[TestModel](mysqlDB).Select(Median("Age").As("median_age")),
+			wantSql: "SELECT SUBSTRING_INDEX(SUBSTRING_INDEX(GROUP_CONCAT(`age` ORDER BY `age` SEPARATOR ','),',',CEIL(COUNT(`age`)/2)),',',-1) " +
+				"AS `median_age` FROM `test_model`;",
+		},
+		{
+			name:    "mysql filter",
+			builder: NewSelector[TestModel](mysqlDB).Select(Median("Age").Filter(C("Id").GT(10))),
+			wantErr: errs.NewUnsupportedFeatureError("MySQL", "MEDIAN FILTER"),
+		},
+		{
+			name: "distinct",
+			builder: func() QueryBuilder {
+				m := Median("Age")
+				m.distinct = true
+				return NewSelector[TestModel](pg).Select(m)
+			}(),
+			wantErr: errs.NewUnsupportedFeatureError("PostgreSQL", "MEDIAN DISTINCT"),
+		},
+		{
+			name:    "over",
+			builder: NewSelector[TestModel](pg).Select(Median("Age").Over(Partition("FirstName"))),
+			wantErr: errs.NewUnsupportedFeatureError("PostgreSQL", "MEDIAN OVER"),
+		},
+		{
+			name:    "sqlite",
+			builder: NewSelector[TestModel](memoryDB()).Select(Median("Age")),
+			wantErr: errs.NewUnsupportedFeatureError("SQLite", "MEDIAN"),
+		},
+		{
+			name:    "invalid column",
+			builder: NewSelector[TestModel](pg).Select(Median("Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestGroupConcat(t *testing.T) {
+	db := memoryDB()
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:    "simple",
+			builder: NewSelector[TestModel](db).Select(GroupConcat("FirstName")),
+			wantSql: "SELECT GROUP_CONCAT(`first_name` SEPARATOR ',') FROM `test_model`;",
+		},
+		{
+			name: "combined",
+			builder: NewSelector[TestModel](db).Select(C("Age"),
+				GroupConcat("FirstName").Distinct().OrderBy(ASC("FirstName")).Separator("; ").As("names")).GroupBy("Age"),
+			wantSql: "SELECT `age`,GROUP_CONCAT(DISTINCT `first_name` ORDER BY `first_name` ASC SEPARATOR '; ') AS `names` FROM `test_model` GROUP BY `age`;",
+		},
+		{
+			name: "multiple order by",
+			builder: NewSelector[TestModel](db).Select(
+				GroupConcat("FirstName").OrderBy(DESC("Age"), ASC("Id", "FirstName")).Separator("'")),
+			wantSql: "SELECT GROUP_CONCAT(`first_name` ORDER BY `age` DESC,`id` ASC,`first_name` ASC SEPARATOR '''') FROM `test_model`;",
+		},
+		{
+			name:    "invalid column",
+			builder: NewSelector[TestModel](db).Select(GroupConcat("Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid order by",
+			builder: NewSelector[TestModel](db).Select(GroupConcat("FirstName").OrderBy(ASC("Invalid"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "postgres simple",
+			builder: NewSelector[TestModel](pg).Select(GroupConcat("FirstName")),
+			wantSql: `SELECT STRING_AGG("first_name",',') FROM "test_model";`,
+		},
+		{
+			name: "postgres combined",
+			builder: NewSelector[TestModel](pg).Select(C("Age"),
+				GroupConcat("FirstName").Distinct().OrderBy(ASC("FirstName")).Separator("; ").As("names")).GroupBy("Age"),
+			wantSql: `SELECT "age",STRING_AGG(DISTINCT "first_name",'; ' ORDER BY "first_name" ASC) AS "names" FROM "test_model" GROUP BY "age";`,
+		},
+		{
+			name:    "postgres distinct order by other column",
+			builder: NewSelector[TestModel](pg).Select(GroupConcat("FirstName").Distinct().OrderBy(ASC("Age"))),
+			wantErr: errs.NewDistinctOrderByError("Age"),
+		},
+		{
+			name:    "postgres order by other column",
+			builder: NewSelector[TestModel](pg).Select(GroupConcat("FirstName").OrderBy(ASC("Age"))),
+			wantSql: `SELECT STRING_AGG("first_name",',' ORDER BY "age" ASC) FROM "test_model";`,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestBoolAggregate(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "bool or",
+			builder:  NewSelector[TestModel](mysqlDB).Select(BoolOr(C("Age").GT(18))),
+			wantSql:  "SELECT MAX(`age`>?) FROM `test_model`;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "bool and with alias",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("FirstName"),
+				BoolAnd(C("Age").GT(18)).As("all_adult")).GroupBy("FirstName"),
+			wantSql:  "SELECT `first_name`,MIN(`age`>?) AS `all_adult` FROM `test_model` GROUP BY `first_name`;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "having",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("FirstName")).GroupBy("FirstName").
+				Having(BoolOr(C("Age").LT(18)).AsPredicate()),
+			wantSql:  "SELECT `first_name` FROM `test_model` GROUP BY `first_name` HAVING MAX(`age`<?);",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:    "invalid column",
+			builder: NewSelector[TestModel](mysqlDB).Select(BoolOr(C("Invalid").GT(18))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:     "postgres bool or",
+			builder:  NewSelector[TestModel](pg).Select(BoolOr(C("Age").GT(18))),
+			wantSql:  `SELECT BOOL_OR("age">$1) FROM "test_model";`,
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "postgres bool and with alias",
+			builder: NewSelector[TestModel](pg).Select(C("FirstName"),
+				BoolAnd(C("Age").GT(18)).As("all_adult")).GroupBy("FirstName"),
+			wantSql:  `SELECT "first_name",BOOL_AND("age">$1) AS "all_adult" FROM "test_model" GROUP BY "first_name";`,
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "postgres having",
+			builder: NewSelector[TestModel](pg).Select(C("FirstName")).Where(C("Id").GT(10)).GroupBy("FirstName").
+				Having(BoolAnd(C("Age").GTEQ(18)).AsPredicate()),
+			wantSql:  `SELECT "first_name" FROM "test_model" WHERE "id">$1 GROUP BY "first_name" HAVING BOOL_AND("age">=$2);`,
+			wantArgs: []interface{}{10, 18},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
 func ExampleAggregate_As() {
 	db := memoryDB()
 	query, _ := NewSelector[TestModel](db).Select(Avg("Age").As("avg_age")).Build()
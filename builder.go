@@ -17,7 +17,13 @@ package eorm
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gotomicro/eorm/internal/dialect"
 	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/gotomicro/eorm/internal/model"
 	"github.com/valyala/bytebufferpool"
@@ -48,11 +54,14 @@ type Querier[T any] struct {
 	core
 	session
 	qc *QueryContext
+	// err 是构造 Querier 的过程中出现的错误，会在执行的时候返回
+	err error
 }
 
 // RawQuery 创建一个 Querier 实例
 // 泛型参数 T 是目标类型。
 // 例如，如果查询 User 的数据，那么 T 就是 User
+// 如果 T 是 map[string]any，那么每一行都会按照列名转换为一个 map，值的类型由驱动决定
 func RawQuery[T any](sess session, sql string, args ...any) Querier[T] {
 	return Querier[T]{
 		core:    sess.getCore(),
@@ -67,6 +76,105 @@ func RawQuery[T any](sess session, sql string, args ...any) Querier[T] {
 	}
 }
 
+// RawQueryNamed 和 RawQuery 类似，但是使用 :name 形式的命名参数，
+// 例如 RawQueryNamed[User](db, "SELECT * FROM `user` WHERE `id`=:id AND `age`>:age", map[string]any{"id": 1, "age": 18})
+// 同一个命名参数可以出现多次。字符串、引号括起来的标识符、-- 和 /* */ 注释中的内容
+// 以及 PostgreSQL 的 :: 类型转换都不会被当作命名参数
+func RawQueryNamed[T any](sess session, sql string, params map[string]any) Querier[T] {
+	c := sess.getCore()
+	query, args, err := bindNamed(sql, params, c.dialect)
+	q := RawQuery[T](sess, query, args...)
+	q.err = err
+	return q
+}
+
+// bindNamed 将 :name 改写为方言的占位符，并且按照顺序构造参数
+// 对于带序号的占位符，同一个命名参数会复用同一个序号
+func bindNamed(query string, params map[string]any, d dialect.Dialect) (string, []any, error) {
+	numbered := dialect.NumberedBindVar(d)
+	backslash := dialect.FeaturesOf(d).BackslashEscapes
+	buf := make([]byte, 0, len(query))
+	args := make([]any, 0, len(params))
+	indexes := make(map[string]int, len(params))
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if end := skipNonCode(query, i, backslash); end > i {
+			buf = append(buf, query[i:end]...)
+			i = end - 1
+			continue
+		}
+		if c != ':' {
+			buf = append(buf, c)
+			continue
+		}
+		// PostgreSQL 的 ::type
+		if i+1 < len(query) && query[i+1] == ':' {
+			buf = append(buf, "::"...)
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(query) && isNameByte(query[j]) {
+			j++
+		}
+		if j == i+1 {
+			buf = append(buf, c)
+			continue
+		}
+		name := query[i+1 : j]
+		i = j - 1
+		if idx, ok := indexes[name]; ok && numbered {
+			buf = append(buf, d.Placeholder(idx)...)
+			continue
+		}
+		val, ok := params[name]
+		if !ok {
+			return "", nil, errs.NewMissingNamedParamError(name)
+		}
+		args = append(args, val)
+		indexes[name] = len(args)
+		buf = append(buf, d.Placeholder(len(args))...)
+	}
+	return string(buf), args, nil
+}
+
+// skipNonCode 判断 query[i] 是否是字符串、引号标识符或者注释的开始，
+// 是的话返回它结束之后的下标，否则返回 i。
+// 没有闭合的部分会一直延续到 query 的末尾
+func skipNonCode(query string, i int, backslash bool) int {
+	switch c := query[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		for j := i + 1; j < len(query); j++ {
+			switch query[j] {
+			case '\\':
+				// 反引号标识符里面没有转义
+				if backslash && c != '`' {
+					j++
+				}
+			case c:
+				// 连续两个引号会被当作两段相邻的内容处理，结果是一样的
+				return j + 1
+			}
+		}
+		return len(query)
+	case c == '-' && strings.HasPrefix(query[i:], "--"):
+		if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+			return i + j + 1
+		}
+		return len(query)
+	case c == '/' && strings.HasPrefix(query[i:], "/*"):
+		if j := strings.Index(query[i+2:], "*/"); j >= 0 {
+			return i + 2 + j + 2
+		}
+		return len(query)
+	}
+	return i
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
 func newQuerier[T any](sess session, q *Query, meta *model.TableMeta, typ string) Querier[T] {
 	return Querier[T]{
 		core:    sess.getCore(),
@@ -81,6 +189,9 @@ func newQuerier[T any](sess session, q *Query, meta *model.TableMeta, typ string
 
 // Exec 执行 SQL
 func (q Querier[T]) Exec(ctx context.Context) Result {
+	if q.err != nil {
+		return Result{err: q.err}
+	}
 	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
 		res, err := q.session.execContext(ctx, qc.q.SQL, qc.q.Args...)
 		return &QueryResult{Result: res, Err: err}
@@ -102,6 +213,9 @@ func (q Querier[T]) Exec(ctx context.Context) Result {
 // 注意在不同的数据库里面，排序可能会不同
 // 在没有查找到数据的情况下，会返回 ErrNoRows
 func (q Querier[T]) Get(ctx context.Context) (*T, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
 	res := get[T](ctx, q.session, q.core, q.qc)
 	if res.Err != nil {
 		return nil, res.Err
@@ -117,12 +231,76 @@ type builder struct {
 	meta    *model.TableMeta
 	args    []interface{}
 	aliases map[string]struct{}
+	// qualifyColumns 为 true 的时候，没有指定表的列会加上表名，
+	// 用于消除 PostgreSQL 的 ON CONFLICT DO UPDATE 中列和 EXCLUDED 之间的歧义
+	qualifyColumns bool
+}
+
+// grow 预先扩容 buffer 和 args，减少构造过程中的重复分配
+// bufSize 和 argCnt 都只是预估值
+func (b *builder) grow(bufSize int, argCnt int) {
+	if bufSize > cap(b.buffer.B)-len(b.buffer.B) {
+		buf := make([]byte, len(b.buffer.B), len(b.buffer.B)+bufSize)
+		copy(buf, b.buffer.B)
+		b.buffer.B = buf
+	}
+	if argCnt > cap(b.args)-len(b.args) {
+		args := make([]any, len(b.args), len(b.args)+argCnt)
+		copy(args, b.args)
+		b.args = args
+	}
+}
+
+// estimatePredicates 预估 predicates 构造出来的 SQL 长度和参数个数
+func estimatePredicates(predicates []Predicate) (int, int) {
+	size, cnt := 0, 0
+	for _, p := range predicates {
+		s, c := estimateExpr(p)
+		// AND 以及两边的括号
+		size, cnt = size+s+7, cnt+c
+	}
+	return size, cnt
+}
+
+func estimateExpr(expr Expr) (int, int) {
+	switch e := expr.(type) {
+	case Predicate:
+		return estimateExpr(binaryExpr(e))
+	case MathExpr:
+		return estimateExpr(binaryExpr(e))
+	case binaryExpr:
+		ls, lc := estimateExpr(e.left)
+		rs, rc := estimateExpr(e.right)
+		return ls + rs + len(e.op.text) + 2, lc + rc
+	case valueExpr:
+		return 1, 1
+	case values:
+		return len(e.data) * 2, len(e.data)
+	case RawExpr:
+		return len(e.raw), len(e.args)
+	case nil:
+		return 0, 0
+	default:
+		// 列名之类的，按照一个较为常见的长度来预估
+		return 16, 0
+	}
+}
+
+// checkArgs 检查参数个数是否超过了方言的限制
+func (b *builder) checkArgs() error {
+	if limit := b.dialect.MaxParams(); limit > 0 && len(b.args) > limit {
+		return errs.NewTooManyParametersError(b.dialect.Name(), len(b.args), limit)
+	}
+	return nil
+}
+
+// quoteTable 写入加上了前缀的表名
+func (b *builder) quoteTable(name string) {
+	b.quote(b.tablePrefix + name)
 }
 
 func (b *builder) quote(val string) {
-	_ = b.buffer.WriteByte(b.dialect.Quote)
-	_, _ = b.buffer.WriteString(val)
-	_ = b.buffer.WriteByte(b.dialect.Quote)
+	_, _ = b.buffer.WriteString(b.dialect.Quote(val))
 }
 
 func (b *builder) space() {
@@ -137,6 +315,29 @@ func (b *builder) writeByte(c byte) {
 	_ = b.buffer.WriteByte(c)
 }
 
+// buildReturning 构造 RETURNING 部分，cols 为空的时候表示 RETURNING *
+func (b *builder) buildReturning(cols []string) error {
+	if !b.dialect.SupportsReturning() {
+		return errs.NewUnsupportedFeatureError(b.dialect.Name(), "RETURNING")
+	}
+	_, _ = b.buffer.WriteString(" RETURNING ")
+	if len(cols) == 0 {
+		_ = b.buffer.WriteByte('*')
+		return nil
+	}
+	for i, c := range cols {
+		cMeta, err := fieldMeta(b.meta, c)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			_ = b.buffer.WriteByte(',')
+		}
+		b.quote(cMeta.ColumnName)
+	}
+	return nil
+}
+
 func (b *builder) end() {
 	_ = b.buffer.WriteByte(';')
 }
@@ -150,8 +351,13 @@ func (b *builder) parameter(arg interface{}) {
 		// TODO 4 may be not a good number
 		b.args = make([]interface{}, 0, 4)
 	}
-	_ = b.buffer.WriteByte('?')
 	b.args = append(b.args, arg)
+	b.placeholder()
+}
+
+// placeholder 写入最后一个参数对应的占位符
+func (b *builder) placeholder() {
+	_, _ = b.buffer.WriteString(b.dialect.Placeholder(len(b.args)))
 }
 
 func (b *builder) buildExpr(expr Expr) error {
@@ -160,6 +366,9 @@ func (b *builder) buildExpr(expr Expr) error {
 	}
 	switch e := expr.(type) {
 	case RawExpr:
+		if err := b.checkRawFields(e); err != nil {
+			return err
+		}
 		b.buildRawExpr(e)
 	case Column:
 		if err := b.buildColumn(e.table, e.name); err != nil {
@@ -169,12 +378,47 @@ func (b *builder) buildExpr(expr Expr) error {
 		if err := b.buildHavingAggregate(e); err != nil {
 			return err
 		}
+	case GroupConcatExpr:
+		if err := b.buildGroupConcat(e); err != nil {
+			return err
+		}
+	case WindowFunc:
+		if err := b.buildWindowFunc(e); err != nil {
+			return err
+		}
+	case BoolAggregate:
+		if err := b.buildBoolAggregate(e); err != nil {
+			return err
+		}
+	case DateTruncExpr:
+		if err := b.buildDateTrunc(e); err != nil {
+			return err
+		}
+	case FuncExpr:
+		if err := b.buildFuncExpr(e); err != nil {
+			return err
+		}
 	case valueExpr:
 		b.parameter(e.val)
+	case rangeExpr:
+		b.parameter(e.start)
+		_, _ = b.buffer.WriteString(" AND ")
+		b.parameter(e.end)
+	case ParamExpr:
+		b.parameter(e.val)
+		// 只有支持 ::type 写法的方言才会加上类型，例如 PostgreSQL，其余方言直接忽略
+		if e.typ != "" && b.features().TypeCast {
+			_, _ = b.buffer.WriteString("::")
+			_, _ = b.buffer.WriteString(e.typ)
+		}
 	case MathExpr:
 		if err := b.buildBinaryExpr(binaryExpr(e)); err != nil {
 			return err
 		}
+	case ExcludedExpr:
+		return b.buildExcluded(e)
+	case MatchExpr:
+		return b.buildMatch(e)
 	case binaryExpr:
 		if err := b.buildBinaryExpr(e); err != nil {
 			return err
@@ -200,11 +444,7 @@ func (b *builder) buildExpr(expr Expr) error {
 }
 
 func (b *builder) buildPredicates(predicates []Predicate) error {
-	p := predicates[0]
-	for i := 1; i < len(predicates); i++ {
-		p = p.And(predicates[i])
-	}
-	return b.buildExpr(p)
+	return b.buildExpr(and(predicates))
 }
 
 func (b *builder) buildColumn(table TableReference, name string) error {
@@ -215,6 +455,9 @@ func (b *builder) buildColumn(table TableReference, name string) error {
 	if alias != "" {
 		b.quote(alias)
 		_ = b.buffer.WriteByte('.')
+	} else if table == nil && b.qualifyColumns {
+		b.quoteTable(b.meta.TableName)
+		_ = b.buffer.WriteByte('.')
 	}
 	colName, err := b.colName(table, name)
 	if err != nil {
@@ -224,6 +467,106 @@ func (b *builder) buildColumn(table TableReference, name string) error {
 	return nil
 }
 
+// nullInt64Type 是 sql.NullInt64，使用它的时间列会写入时间戳
+var nullInt64Type = reflect.TypeOf(sql.NullInt64{})
+
+// timestampValue 将 now 转化为 typ 对应的值，用于自动填充时间的列
+// 整数类型的列会写入秒级的时间戳，其余的写入 time.Time
+func timestampValue(typ reflect.Type, now time.Time) any {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nullInt64Type {
+		return now.Unix()
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return now.Unix()
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return uint64(now.Unix())
+	default:
+		return now
+	}
+}
+
+// columnValue 返回写入 cm 时使用的参数
+// 有自定义转换器的列使用转换器，JSON 列会被序列化为 JSON 字符串，nil 依旧写入 NULL
+func columnValue(cm *model.ColumnMeta, val any) (any, error) {
+	if cm.Converter != nil {
+		res, err := cm.Converter.ToDB(val)
+		if err != nil {
+			return nil, errs.NewConverterError(cm.ColumnName, err)
+		}
+		return res, nil
+	}
+	if !cm.IsJSON {
+		return val, nil
+	}
+	if isNilValue(val) {
+		return nil, nil
+	}
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return nil, errs.NewInvalidJSONError(cm.ColumnName, err)
+	}
+	return string(bs), nil
+}
+
+// isNilValue 判断 val 是否是 nil，包括 nil 的指针、map 和切片
+func isNilValue(val any) bool {
+	if val == nil {
+		return true
+	}
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// isZeroValue 判断 val 是否是零值，nil 也被认为是零值
+func isZeroValue(val any) bool {
+	return val == nil || reflect.ValueOf(val).IsZero()
+}
+
+// isNullPredicate 构造 qualifier.column IS NULL，qualifier 为空的时候不限定列
+func (b *builder) isNullPredicate(qualifier string, column string) Predicate {
+	col := b.dialect.Quote(column)
+	if qualifier != "" {
+		col = b.dialect.Quote(qualifier) + "." + col
+	}
+	return Raw(col + " IS NULL").AsPredicate()
+}
+
+// writableColumn 返回可以写入的列，数据库生成的列会返回错误
+func (b *builder) writableColumn(field string) (*model.ColumnMeta, error) {
+	cMeta, err := fieldMeta(b.meta, field)
+	if err != nil {
+		return nil, err
+	}
+	if cMeta.IsGenerated {
+		return nil, errs.NewGeneratedColumnError(field)
+	}
+	return cMeta, nil
+}
+
+// fieldMeta 查找字段对应的列，field 也可以是组合结构体中字段的完整路径，例如 BaseEntity.CreateTime
+// 找不到完整路径对应的字段的时候，返回的错误会带上模型和完整的路径，方便定位多层组合的模型中的问题
+func fieldMeta(meta *model.TableMeta, field string) (*model.ColumnMeta, error) {
+	if cMeta, ok := meta.FieldMap[field]; ok {
+		return cMeta, nil
+	}
+	if !strings.Contains(field, ".") {
+		return nil, errs.NewInvalidFieldError(field)
+	}
+	if cMeta, ok := meta.FieldByPath(field); ok {
+		return cMeta, nil
+	}
+	return nil, errs.NewInvalidFieldPathError(meta.Typ.Elem().Name(), field)
+}
+
 func (b *builder) colName(table TableReference, field string) (string, error) {
 	switch tab := table.(type) {
 	case nil:
@@ -231,9 +574,9 @@ func (b *builder) colName(table TableReference, field string) (string, error) {
 		if _, ok := b.aliases[field]; ok {
 			return field, nil
 		}
-		fdMeta, ok := b.meta.FieldMap[field]
-		if !ok {
-			return "", errs.NewInvalidFieldError(field)
+		fdMeta, err := fieldMeta(b.meta, field)
+		if err != nil {
+			return "", err
 		}
 		return fdMeta.ColumnName, nil
 	case Table:
@@ -241,9 +584,9 @@ func (b *builder) colName(table TableReference, field string) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		fdMeta, ok := m.FieldMap[field]
-		if !ok {
-			return "", errs.NewInvalidFieldError(field)
+		fdMeta, err := fieldMeta(m, field)
+		if err != nil {
+			return "", err
 		}
 		return fdMeta.ColumnName, nil
 	case Subquery:
@@ -254,49 +597,507 @@ func (b *builder) colName(table TableReference, field string) (string, error) {
 					return field, nil
 				}
 				if c.fieldName() == field {
+					// 没有指定表的列属于子查询自身的表
+					if c.selectedTable() == nil {
+						return b.colName(tab.entity, field)
+					}
 					return b.colName(c.selectedTable(), field)
 				}
 			}
 			return "", errs.NewInvalidFieldError(field)
 		}
 		return b.colName(tab.entity, field)
+	case CTE:
+		// 公共表表达式的列按照目标类型 T 的元数据来解析
+		fdMeta, err := fieldMeta(b.meta, field)
+		if err != nil {
+			return "", err
+		}
+		return fdMeta.ColumnName, nil
+	case JSONTableRef:
+		for _, c := range tab.columns {
+			if c.name == field {
+				return field, nil
+			}
+		}
+		return "", errs.NewInvalidFieldError(field)
 	default:
 		return "", errs.NewErrUnsupportedExpressionType(tab)
 	}
 }
 
 func (b *builder) buildHavingAggregate(aggregate Aggregate) error {
-	_, _ = b.buffer.WriteString(aggregate.fn)
+	if aggregate.selectedAlias() != "" {
+		_, _ = b.buffer.WriteString(aggregate.fn)
+		_ = b.buffer.WriteByte('(')
+		if aggregate.distinct {
+			_, _ = b.buffer.WriteString("DISTINCT ")
+		}
+		b.quote(aggregate.selectedAlias())
+		return nil
+	}
+	return b.buildAggregate(aggregate)
+}
 
+// buildAggregate 构造 fn([DISTINCT] col)，如果有 Filter 的话，一并构造过滤条件
+func (b *builder) buildAggregate(aggregate Aggregate) error {
+	if aggregate.fn == "MEDIAN" {
+		return b.buildMedian(aggregate)
+	}
+	_, _ = b.buffer.WriteString(aggregate.fn)
 	_ = b.buffer.WriteByte('(')
 	if aggregate.distinct {
 		_, _ = b.buffer.WriteString("DISTINCT ")
 	}
-	if aggregate.selectedAlias() != "" {
-		b.quote(aggregate.selectedAlias())
-		return nil
+	// MySQL 之类的方言不支持 FILTER，只能把条件放到 CASE WHEN 里面
+	caseWhen := len(aggregate.filter) > 0 && !b.features().AggregateFilter
+	if caseWhen {
+		_, _ = b.buffer.WriteString("CASE WHEN ")
+		if err := b.buildPredicates(aggregate.filter); err != nil {
+			return err
+		}
+		_, _ = b.buffer.WriteString(" THEN ")
+	}
+	if aggregate.arg == "*" {
+		if caseWhen {
+			_ = b.buffer.WriteByte('1')
+		} else {
+			_ = b.buffer.WriteByte('*')
+		}
+	} else {
+		cMeta, err := fieldMeta(b.meta, aggregate.arg)
+		if err != nil {
+			return err
+		}
+		b.quote(cMeta.ColumnName)
+	}
+	if caseWhen {
+		_, _ = b.buffer.WriteString(" END")
+	}
+	_ = b.buffer.WriteByte(')')
+	if len(aggregate.filter) > 0 && !caseWhen {
+		_, _ = b.buffer.WriteString(" FILTER (WHERE ")
+		if err := b.buildPredicates(aggregate.filter); err != nil {
+			return err
+		}
+		_ = b.buffer.WriteByte(')')
+	}
+	return nil
+}
+
+// buildGroupConcat 构造 GROUP_CONCAT，在 PostgreSQL 中构造 STRING_AGG
+func (b *builder) buildGroupConcat(g GroupConcatExpr) error {
+	cMeta, err := fieldMeta(b.meta, g.arg)
+	if err != nil {
+		return err
+	}
+	isPG := b.features().StringAgg
+	if g.distinct && isPG {
+		for _, ob := range g.orderBy {
+			for _, f := range ob.fields {
+				if f != g.arg {
+					return errs.NewDistinctOrderByError(f)
+				}
+			}
+		}
+	}
+	if isPG {
+		_, _ = b.buffer.WriteString("STRING_AGG(")
+	} else {
+		_, _ = b.buffer.WriteString("GROUP_CONCAT(")
+	}
+	if g.distinct {
+		_, _ = b.buffer.WriteString("DISTINCT ")
+	}
+	b.quote(cMeta.ColumnName)
+	if isPG {
+		_ = b.buffer.WriteByte(',')
+		b.stringLiteral(g.separator)
+	}
+	if len(g.orderBy) > 0 {
+		_, _ = b.buffer.WriteString(" ORDER BY ")
+		if err := b.buildOrderByList(g.orderBy); err != nil {
+			return err
+		}
+	}
+	if !isPG {
+		_, _ = b.buffer.WriteString(" SEPARATOR ")
+		b.stringLiteral(g.separator)
+	}
+	_ = b.buffer.WriteByte(')')
+	return nil
+}
+
+// buildMedian 构造中位数，PostgreSQL 使用 PERCENTILE_CONT，MySQL 使用 GROUP_CONCAT 近似
+func (b *builder) buildMedian(aggregate Aggregate) error {
+	// 中位数本身就是一个值，任何方言都不支持 DISTINCT
+	if aggregate.distinct {
+		return errs.NewUnsupportedFeatureError(b.dialect.Name(), "MEDIAN DISTINCT")
 	}
-	cMeta, ok := b.meta.FieldMap[aggregate.arg]
+	cMeta, err := fieldMeta(b.meta, aggregate.arg)
+	if err != nil {
+		return err
+	}
+	switch b.features().Median {
+	case dialect.MedianPercentileCont:
+		_, _ = b.buffer.WriteString("PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY ")
+		b.quote(cMeta.ColumnName)
+		_ = b.buffer.WriteByte(')')
+		if len(aggregate.filter) > 0 {
+			_, _ = b.buffer.WriteString(" FILTER (WHERE ")
+			if err = b.buildPredicates(aggregate.filter); err != nil {
+				return err
+			}
+			_ = b.buffer.WriteByte(')')
+		}
+	case dialect.MedianGroupConcat:
+		if len(aggregate.filter) > 0 {
+			return errs.NewUnsupportedFeatureError(b.dialect.Name(), "MEDIAN FILTER")
+		}
+		// 排序之后取第 CEIL(COUNT(col)/2) 个值
+		_, _ = b.buffer.WriteString("SUBSTRING_INDEX(SUBSTRING_INDEX(GROUP_CONCAT(")
+		b.quote(cMeta.ColumnName)
+		_, _ = b.buffer.WriteString(" ORDER BY ")
+		b.quote(cMeta.ColumnName)
+		_, _ = b.buffer.WriteString(" SEPARATOR ','),',',CEIL(COUNT(")
+		b.quote(cMeta.ColumnName)
+		_, _ = b.buffer.WriteString(")/2)),',',-1)")
+	default:
+		return errs.NewUnsupportedFeatureError(b.dialect.Name(), "MEDIAN")
+	}
+	return nil
+}
+
+// buildBoolAggregate 构造 BOOL_OR 和 BOOL_AND
+// 只有 PostgreSQL 支持，其它方言里面布尔表达式的结果是 0 或者 1，所以使用 MAX 和 MIN 代替
+func (b *builder) buildBoolAggregate(a BoolAggregate) error {
+	if b.features().BoolAggregate {
+		_, _ = b.buffer.WriteString(a.fn)
+	} else if a.fn == "BOOL_OR" {
+		_, _ = b.buffer.WriteString("MAX")
+	} else {
+		_, _ = b.buffer.WriteString("MIN")
+	}
+	_ = b.buffer.WriteByte('(')
+	if err := b.buildExpr(a.arg); err != nil {
+		return err
+	}
+	_ = b.buffer.WriteByte(')')
+	return nil
+}
+
+// buildDateTrunc 构造 DateTrunc，精度和格式都是直接写入 SQL 的，
+// 这样 SELECT 和 GROUP BY 中的表达式才会被数据库认为是同一个
+func (b *builder) buildDateTrunc(d DateTruncExpr) error {
+	formats, ok := dateTruncFormats[d.unit]
 	if !ok {
-		return errs.NewInvalidFieldError(aggregate.arg)
+		return errs.NewUnsupportedDateTruncUnitError(d.unit)
 	}
+	cMeta, err := fieldMeta(b.meta, d.field)
+	if err != nil {
+		return err
+	}
+	switch b.features().DateTrunc {
+	case dialect.DateTruncFormat:
+		_, _ = b.buffer.WriteString("DATE_FORMAT(")
+		b.quote(cMeta.ColumnName)
+		_, _ = b.buffer.WriteString(",'")
+		_, _ = b.buffer.WriteString(formats[0])
+		_, _ = b.buffer.WriteString("')")
+	case dialect.DateTruncStrftime:
+		_, _ = b.buffer.WriteString("strftime('")
+		_, _ = b.buffer.WriteString(formats[1])
+		_, _ = b.buffer.WriteString("',")
+		b.quote(cMeta.ColumnName)
+		_ = b.buffer.WriteByte(')')
+	default:
+		_, _ = b.buffer.WriteString("DATE_TRUNC('")
+		_, _ = b.buffer.WriteString(d.unit)
+		_, _ = b.buffer.WriteString("',")
+		b.quote(cMeta.ColumnName)
+		_ = b.buffer.WriteByte(')')
+	}
+	return nil
+}
+
+// buildExcluded 构造 upsert 中插入的值，
+// 在 MySQL 中是 VALUES(col)，在 PostgreSQL 和 SQLite 中是 EXCLUDED.col
+func (b *builder) buildExcluded(e ExcludedExpr) error {
+	cMeta, err := fieldMeta(b.meta, e.field)
+	if err != nil {
+		return err
+	}
+	if b.features().OnDuplicateKey {
+		_, _ = b.buffer.WriteString("VALUES(")
+		b.quote(cMeta.ColumnName)
+		_ = b.buffer.WriteByte(')')
+		return nil
+	}
+	_, _ = b.buffer.WriteString("EXCLUDED.")
 	b.quote(cMeta.ColumnName)
+	return nil
+}
+
+// buildMatch 构造 MATCH (cols) AGAINST (? mode)
+func (b *builder) buildMatch(m MatchExpr) error {
+	if !b.features().FullTextMatch {
+		return errs.NewUnsupportedFeatureError(b.dialect.Name(), "MATCH AGAINST")
+	}
+	if len(m.fields) == 0 {
+		return errs.ErrMatchWithoutColumns
+	}
+	_, _ = b.buffer.WriteString("MATCH (")
+	for i, f := range m.fields {
+		cMeta, err := fieldMeta(b.meta, f)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			_ = b.buffer.WriteByte(',')
+		}
+		b.quote(cMeta.ColumnName)
+	}
+	_, _ = b.buffer.WriteString(") AGAINST (")
+	b.parameter(m.query)
+	if m.mode != "" {
+		_ = b.buffer.WriteByte(' ')
+		_, _ = b.buffer.WriteString(string(m.mode))
+	}
+	_ = b.buffer.WriteByte(')')
+	return nil
+}
+
+// buildFuncExpr 构造 fn(arg1,arg2,...)
+func (b *builder) buildFuncExpr(f FuncExpr) error {
+	fn, sep := f.fn, ","
+	features := b.features()
+	if name, ok := features.FuncNames[fn]; ok {
+		fn = name
+	} else if fn == "CONCAT" && features.ConcatOperator {
+		fn, sep = "", "||"
+	}
+	_, _ = b.buffer.WriteString(fn)
+	_ = b.buffer.WriteByte('(')
+	for i, arg := range f.args {
+		if i > 0 {
+			_, _ = b.buffer.WriteString(sep)
+		}
+		if err := b.buildExpr(arg); err != nil {
+			return err
+		}
+	}
 	_ = b.buffer.WriteByte(')')
 	return nil
 }
 
+// buildWindowFunc 构造窗口函数，分区和排序的字段都必须是模型的字段
+func (b *builder) buildWindowFunc(w WindowFunc) error {
+	// PERCENTILE_CONT 是有序集聚合函数，不能作为窗口函数使用
+	if w.fn == "MEDIAN" {
+		return errs.NewUnsupportedFeatureError(b.dialect.Name(), "MEDIAN OVER")
+	}
+	if w.distinct {
+		return errs.ErrWindowWithDistinct
+	}
+	if len(w.filter) > 0 {
+		// 带有过滤条件的聚合函数和普通的聚合函数构造方式一样
+		if err := b.buildAggregate(Aggregate{fn: w.fn, arg: w.arg, filter: w.filter}); err != nil {
+			return err
+		}
+	} else {
+		_, _ = b.buffer.WriteString(w.fn)
+		_ = b.buffer.WriteByte('(')
+		if w.fn == "NTILE" {
+			if w.buckets <= 0 {
+				return errs.ErrInvalidNtileBuckets
+			}
+			_, _ = b.buffer.WriteString(strconv.Itoa(w.buckets))
+		}
+		switch w.arg {
+		case "":
+		case "*":
+			_ = b.buffer.WriteByte('*')
+		default:
+			cMeta, err := fieldMeta(b.meta, w.arg)
+			if err != nil {
+				return err
+			}
+			b.quote(cMeta.ColumnName)
+		}
+		_ = b.buffer.WriteByte(')')
+	}
+	_, _ = b.buffer.WriteString(" OVER (")
+	if len(w.partition) > 0 {
+		_, _ = b.buffer.WriteString("PARTITION BY ")
+		for i, f := range w.partition {
+			if i > 0 {
+				_ = b.buffer.WriteByte(',')
+			}
+			cMeta, err := fieldMeta(b.meta, f)
+			if err != nil {
+				return err
+			}
+			b.quote(cMeta.ColumnName)
+		}
+	}
+	if len(w.orderBy) > 0 {
+		if len(w.partition) > 0 {
+			_ = b.buffer.WriteByte(' ')
+		}
+		_, _ = b.buffer.WriteString("ORDER BY ")
+		if err := b.buildOrderByList(w.orderBy); err != nil {
+			return err
+		}
+	}
+	_ = b.buffer.WriteByte(')')
+	return nil
+}
+
+// buildOrderByList 构造排序列表，每一个字段后面都会跟着排序的方向
+func (b *builder) buildOrderByList(orderBys []OrderBy) error {
+	for i, ob := range orderBys {
+		if ob.expr != nil {
+			if i > 0 {
+				_ = b.buffer.WriteByte(',')
+			}
+			if err := b.buildExpr(ob.expr); err != nil {
+				return err
+			}
+			_ = b.buffer.WriteByte(' ')
+			_, _ = b.buffer.WriteString(ob.order)
+			continue
+		}
+		for j, f := range ob.fields {
+			if i > 0 || j > 0 {
+				_ = b.buffer.WriteByte(',')
+			}
+			cMeta, err := fieldMeta(b.meta, f)
+			if err != nil {
+				return err
+			}
+			if len(ob.vals) > 0 {
+				b.buildOrderByField(cMeta.ColumnName, ob.vals)
+			} else {
+				b.quote(cMeta.ColumnName)
+			}
+			_ = b.buffer.WriteByte(' ')
+			_, _ = b.buffer.WriteString(ob.order)
+		}
+	}
+	return nil
+}
+
+// buildOrderByField 构造按照 vals 自定义顺序排序的表达式
+// MySQL 使用 FIELD 函数，其它方言使用等价的 CASE 表达式，
+// 不在 vals 中的值对应 0，和 FIELD 的语义保持一致
+func (b *builder) buildOrderByField(col string, vals []any) {
+	if b.features().FieldFunc {
+		_, _ = b.buffer.WriteString("FIELD(")
+		b.quote(col)
+		for _, val := range vals {
+			_ = b.buffer.WriteByte(',')
+			b.parameter(val)
+		}
+		_ = b.buffer.WriteByte(')')
+		return
+	}
+	_, _ = b.buffer.WriteString("CASE ")
+	b.quote(col)
+	for i, val := range vals {
+		_, _ = b.buffer.WriteString(" WHEN ")
+		b.parameter(val)
+		_, _ = b.buffer.WriteString(" THEN ")
+		_, _ = b.buffer.WriteString(strconv.Itoa(i + 1))
+	}
+	_, _ = b.buffer.WriteString(" ELSE 0 END")
+}
+
+// stringLiteral 写入一个用单引号括起来的字符串，其中的单引号会被转义
+func (b *builder) stringLiteral(val string) {
+	_ = b.buffer.WriteByte('\'')
+	_, _ = b.buffer.WriteString(strings.ReplaceAll(val, "'", "''"))
+	_ = b.buffer.WriteByte('\'')
+}
+
 func (b *builder) buildBinaryExpr(e binaryExpr) error {
+	if e.op == opILike && !b.features().ILike {
+		e = binaryExpr{left: newFuncExpr("LOWER", e.left), op: opLike, right: newFuncExpr("LOWER", e.right)}
+	}
+	if e.op == opRegexp || e.op == opNotRegexp {
+		op, err := b.regexpOp(e.op)
+		if err != nil {
+			return err
+		}
+		e.op = op
+	}
 	err := b.buildSubExpr(e.left)
 	if err != nil {
 		return err
 	}
 	_, _ = b.buffer.WriteString(e.op.text)
-	return b.buildSubExpr(e.right)
+	right, err := b.convertOperand(e.left, e.right)
+	if err != nil {
+		return err
+	}
+	return b.buildSubExpr(right)
+}
+
+// regexpOp 将正则匹配改写为方言支持的写法
+func (b *builder) regexpOp(o op) (op, error) {
+	features := b.features()
+	text := features.Regexp
+	if o == opNotRegexp {
+		text = features.NotRegexp
+	}
+	if text == "" {
+		return o, errs.NewUnsupportedFeatureError(b.dialect.Name(), o.symbol)
+	}
+	return op{symbol: o.symbol, text: text}, nil
+}
+
+// convertOperand 在列和字段类型的值比较的时候，使用列的自定义转换器转换该值
+func (b *builder) convertOperand(left, right Expr) (Expr, error) {
+	col, ok := left.(Column)
+	if !ok || col.table != nil || b.meta == nil {
+		return right, nil
+	}
+	v, ok := right.(valueExpr)
+	if !ok {
+		return right, nil
+	}
+	cm, ok := b.meta.FieldMap[col.name]
+	if !ok || cm.Converter == nil || reflect.TypeOf(v.val) != cm.Typ {
+		return right, nil
+	}
+	val, err := columnValue(cm, v.val)
+	if err != nil {
+		return nil, err
+	}
+	return valueExpr{val: val}, nil
+}
+
+// checkRawFields 校验 RawExpr 通过 Using 声明的字段
+func (b *builder) checkRawFields(e RawExpr) error {
+	for _, f := range e.fields {
+		if _, err := b.colName(nil, f); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (b *builder) buildRawExpr(e RawExpr) {
-	_, _ = b.buffer.WriteString(e.raw)
+	if !dialect.NumberedBindVar(b.dialect) {
+		_, _ = b.buffer.WriteString(e.raw)
+		b.args = append(b.args, e.args...)
+		return
+	}
+	// 需要把 ? 改写为带序号的占位符，单引号里面的内容保持原样
+	start := len(b.args)
 	b.args = append(b.args, e.args...)
+	_, _ = b.buffer.WriteString(rewritePlaceholders(e.raw, func(buf []byte, idx int) []byte {
+		return append(buf, b.dialect.Placeholder(start+idx)...)
+	}, '?'))
 }
 
 func (b *builder) buildSubExpr(subExpr Expr) error {
@@ -335,14 +1136,16 @@ func (b *builder) buildIns(is values) error {
 		}
 
 		b.args = append(b.args, inVal)
-		_ = b.buffer.WriteByte('?')
-
+		b.placeholder()
 	}
 	_ = b.buffer.WriteByte(')')
 	return nil
 }
 
 func (q Querier[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
 	res := getMulti[T](ctx, q.session, q.core, q.qc)
 	if res.Err != nil {
 		return nil, res.Err
@@ -366,7 +1169,15 @@ func (b *builder) buildSubquery(sub Subquery, useAlias bool) error {
 	}
 	_ = b.buffer.WriteByte('(')
 	// 拿掉最後 ';'
-	_, _ = b.buffer.WriteString(query.SQL[:len(query.SQL)-1])
+	subSQL := query.SQL[:len(query.SQL)-1]
+	if len(b.args) > 0 && dialect.NumberedBindVar(b.dialect) {
+		// 子查詢的占位符序號從 1 開始，需要加上外層已有的參數個數
+		offset := len(b.args)
+		subSQL = rewritePlaceholders(subSQL, func(buf []byte, idx int) []byte {
+			return append(buf, b.dialect.Placeholder(offset+idx)...)
+		}, b.dialect.Placeholder(1)[0])
+	}
+	_, _ = b.buffer.WriteString(subSQL)
 	// 因為有 build() ，所以理應 args 也需要跟 SQL 一起處理
 	if len(query.Args) > 0 {
 		b.addArgs(query.Args...)
@@ -384,3 +1195,39 @@ func (b *builder) addArgs(args ...any) {
 	}
 	b.args = append(b.args, args...)
 }
+
+// rewritePlaceholders 将 query 中单引号以外的占位符交给 fn 重写，
+// 对于 '?' 以外的占位符（例如 '$'）会一并吞掉后面的序号，fn 收到的 idx 从 1 开始：
+// 对于 '?' 是它出现的次序，对于其它占位符是它原本的序号
+func rewritePlaceholders(query string, fn func(buf []byte, idx int) []byte, bindVar byte) string {
+	buf := make([]byte, 0, len(query)+8)
+	inQuote := false
+	cnt := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if c == '\'' {
+			inQuote = !inQuote
+		}
+		if inQuote || c != bindVar {
+			buf = append(buf, c)
+			continue
+		}
+		if bindVar == '?' {
+			cnt++
+			buf = fn(buf, cnt)
+			continue
+		}
+		j := i + 1
+		for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+			j++
+		}
+		if j == i+1 {
+			buf = append(buf, c)
+			continue
+		}
+		idx, _ := strconv.Atoi(query[i+1 : j])
+		buf = fn(buf, idx)
+		i = j - 1
+	}
+	return string(buf)
+}
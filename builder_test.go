@@ -58,6 +58,144 @@ func (q Query) string() string {
 	return fmt.Sprintf("SQL: %s\nArgs: %#v\n", q.SQL, q.Args)
 }
 
+func TestRawQueryNamed(t *testing.T) {
+	db := memoryDB()
+	pg := postgresDB()
+	mockDB, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = mockDB.Close() }()
+	mysqlDB, err := openDB("mysql", mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name     string
+		q        Querier[TestModel]
+		wantSQL  string
+		wantArgs []any
+		wantErr  error
+	}{
+		{
+			name: "simple",
+			q: RawQueryNamed[TestModel](db, "SELECT * FROM `test_model` WHERE `id`=:id AND `age`>:age;",
+				map[string]any{"id": 1, "age": 18}),
+			wantSQL:  "SELECT * FROM `test_model` WHERE `id`=? AND `age`>?;",
+			wantArgs: []any{1, 18},
+		},
+		{
+			name: "repeated",
+			q: RawQueryNamed[TestModel](db, "SELECT * FROM `test_model` WHERE `age`>:age OR (`id`=:id AND `age`<:age);",
+				map[string]any{"id": 1, "age": 18}),
+			wantSQL:  "SELECT * FROM `test_model` WHERE `age`>? OR (`id`=? AND `age`<?);",
+			wantArgs: []any{18, 1, 18},
+		},
+		{
+			name: "string literal",
+			q: RawQueryNamed[TestModel](db, "SELECT * FROM `test_model` WHERE `first_name`=':name' AND `last_name`=:name;",
+				map[string]any{"name": "Tom"}),
+			wantSQL:  "SELECT * FROM `test_model` WHERE `first_name`=':name' AND `last_name`=?;",
+			wantArgs: []any{"Tom"},
+		},
+		{
+			name: "doubled single quote",
+			q: RawQueryNamed[TestModel](db, "SELECT * FROM `test_model` WHERE `first_name`='It''s :name' AND `last_name`=:name;",
+				map[string]any{"name": "Tom"}),
+			wantSQL:  "SELECT * FROM `test_model` WHERE `first_name`='It''s :name' AND `last_name`=?;",
+			wantArgs: []any{"Tom"},
+		},
+		{
+			name: "double quote",
+			q: RawQueryNamed[TestModel](db, "SELECT \":name\" FROM `test_model` WHERE `last_name`=:name;",
+				map[string]any{"name": "Tom"}),
+			wantSQL:  "SELECT \":name\" FROM `test_model` WHERE `last_name`=?;",
+			wantArgs: []any{"Tom"},
+		},
+		{
+			name: "backtick",
+			q: RawQueryNamed[TestModel](db, "SELECT `:name` FROM `test_model` WHERE `last_name`=:name;",
+				map[string]any{"name": "Tom"}),
+			wantSQL:  "SELECT `:name` FROM `test_model` WHERE `last_name`=?;",
+			wantArgs: []any{"Tom"},
+		},
+		{
+			name: "line comment",
+			q: RawQueryNamed[TestModel](db, "SELECT * FROM `test_model` -- WHERE `id`=:unknown\nWHERE `id`=:id;",
+				map[string]any{"id": 1}),
+			wantSQL:  "SELECT * FROM `test_model` -- WHERE `id`=:unknown\nWHERE `id`=?;",
+			wantArgs: []any{1},
+		},
+		{
+			name: "block comment",
+			q: RawQueryNamed[TestModel](db, "SELECT /* :unknown */ * FROM `test_model` WHERE `id`=:id;",
+				map[string]any{"id": 1}),
+			wantSQL:  "SELECT /* :unknown */ * FROM `test_model` WHERE `id`=?;",
+			wantArgs: []any{1},
+		},
+		{
+			name: "mysql backslash escape",
+			q: RawQueryNamed[TestModel](mysqlDB, "SELECT * FROM `test_model` WHERE `first_name`='It\\'s :name' AND `last_name`=:name;",
+				map[string]any{"name": "Tom"}),
+			wantSQL:  "SELECT * FROM `test_model` WHERE `first_name`='It\\'s :name' AND `last_name`=?;",
+			wantArgs: []any{"Tom"},
+		},
+		{
+			// PostgreSQL 默认不把 \ 当作转义字符
+			name: "postgres backslash",
+			q: RawQueryNamed[TestModel](pg, `SELECT * FROM "test_model" WHERE "first_name"='C:\' AND "last_name"=:name;`,
+				map[string]any{"name": "Tom"}),
+			wantSQL:  `SELECT * FROM "test_model" WHERE "first_name"='C:\' AND "last_name"=$1;`,
+			wantArgs: []any{"Tom"},
+		},
+		{
+			name:    "missing",
+			q:       RawQueryNamed[TestModel](db, "SELECT * FROM `test_model` WHERE `id`=:id;", map[string]any{}),
+			wantErr: errs.NewMissingNamedParamError("id"),
+		},
+		{
+			name: "postgres",
+			q: RawQueryNamed[TestModel](pg, `SELECT * FROM "test_model" WHERE "age">:age OR ("id"=:id::int AND "age"<:age);`,
+				map[string]any{"id": 1, "age": 18}),
+			wantSQL:  `SELECT * FROM "test_model" WHERE "age">$1 OR ("id"=$2::int AND "age"<$1);`,
+			wantArgs: []any{18, 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantErr, tc.q.err)
+			if tc.q.err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantSQL, tc.q.qc.q.SQL)
+			assert.Equal(t, tc.wantArgs, tc.q.qc.q.Args)
+		})
+	}
+}
+
+func TestRawQueryNamed_GetMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery("SELECT `id` FROM `test_model` WHERE `age`>? OR `id`=?;").
+		WithArgs(18, 18).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(18))
+	res, err := RawQueryNamed[TestModel](db, "SELECT `id` FROM `test_model` WHERE `age`>:n OR `id`=:n;",
+		map[string]any{"n": 18}).GetMulti(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestModel{{Id: 1}, {Id: 18}}, res)
+
+	_, err = RawQueryNamed[TestModel](db, "SELECT `id` FROM `test_model` WHERE `age`>:n;",
+		map[string]any{}).GetMulti(context.Background())
+	assert.Equal(t, errs.NewMissingNamedParamError("n"), err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestQuerier_Get(t *testing.T) {
 	t.Run("unsafe", func(t *testing.T) {
 		testQuerierGet(t, valuer.BasicTypeCreator{Creator: valuer.NewUnsafeValue})
@@ -14,6 +14,8 @@
 
 package eorm
 
+import "time"
+
 // Column represents column
 // it could have alias
 // in general, we use it in two ways
@@ -103,6 +105,36 @@ func (c Column) Like(val interface{}) Predicate {
 	}
 }
 
+// ILike -> ILIKE，忽略大小写的 LIKE
+// 只有 PostgreSQL 支持 ILIKE，其余方言会被翻译为 LOWER(col) LIKE LOWER(?)
+func (c Column) ILike(val interface{}) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opILike,
+		right: valueOf(val),
+	}
+}
+
+// Regexp -> REGEXP，正则匹配
+// 在 MySQL 中是 REGEXP ?，在 PostgreSQL 中是 ~ ?，其余方言会返回错误
+func (c Column) Regexp(pattern string) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opRegexp,
+		right: valueOf(pattern),
+	}
+}
+
+// NotRegexp -> NOT REGEXP
+// 在 MySQL 中是 NOT REGEXP ?，在 PostgreSQL 中是 !~ ?，其余方言会返回错误
+func (c Column) NotRegexp(pattern string) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opNotRegexp,
+		right: valueOf(pattern),
+	}
+}
+
 // NotLike -> NOT LIKE %XXX 、_x_ 、xx[xx-xx] 、xx[^xx-xx]
 func (c Column) NotLike(val interface{}) Predicate {
 	return Predicate{
@@ -112,6 +144,25 @@ func (c Column) NotLike(val interface{}) Predicate {
 	}
 }
 
+// InRange -> BETWEEN start AND end，包含两端的时间
+func (c Column) InRange(start, end time.Time) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opBetween,
+		right: rangeExpr{start: start, end: end},
+	}
+}
+
+// Before -> < t
+func (c Column) Before(t time.Time) Predicate {
+	return c.LT(t)
+}
+
+// After -> > t
+func (c Column) After(t time.Time) Predicate {
+	return c.GT(t)
+}
+
 // Add generate an additive expression
 func (c Column) Add(val interface{}) MathExpr {
 	return MathExpr{
@@ -130,6 +181,24 @@ func (c Column) Multi(val interface{}) MathExpr {
 	}
 }
 
+// Sub generate a subtraction expression
+func (c Column) Sub(val interface{}) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+// Div generate a division expression
+func (c Column) Div(val interface{}) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
 func (Column) assign() {
 	panic("implement me")
 }
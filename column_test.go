@@ -14,7 +14,77 @@
 
 package eorm
 
-import "fmt"
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gotomicro/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumn_TimeRange(t *testing.T) {
+	type Order struct {
+		Id         int64
+		CreateTime time.Time
+	}
+	db := memoryDB()
+	pg := postgresDB()
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []CommonTestCase{
+		{
+			name:     "in range",
+			builder:  NewSelector[Order](db).Select(C("Id")).Where(C("CreateTime").InRange(start, end)),
+			wantSql:  "SELECT `id` FROM `order` WHERE `create_time` BETWEEN ? AND ?;",
+			wantArgs: []interface{}{start, end},
+		},
+		{
+			name:     "before",
+			builder:  NewSelector[Order](db).Select(C("Id")).Where(C("CreateTime").Before(end)),
+			wantSql:  "SELECT `id` FROM `order` WHERE `create_time`<?;",
+			wantArgs: []interface{}{end},
+		},
+		{
+			name:     "after",
+			builder:  NewSelector[Order](db).Select(C("Id")).Where(C("CreateTime").After(start)),
+			wantSql:  "SELECT `id` FROM `order` WHERE `create_time`>?;",
+			wantArgs: []interface{}{start},
+		},
+		{
+			name: "in range and others",
+			builder: NewSelector[Order](db).Select(C("Id")).
+				Where(C("CreateTime").InRange(start, end), C("Id").GT(10)),
+			wantSql:  "SELECT `id` FROM `order` WHERE (`create_time` BETWEEN ? AND ?) AND (`id`>?);",
+			wantArgs: []interface{}{start, end, 10},
+		},
+		{
+			name: "postgres in range",
+			builder: NewSelector[Order](pg).Select(C("Id")).
+				Where(C("Id").GT(10), C("CreateTime").InRange(start, end)),
+			wantSql:  `SELECT "id" FROM "order" WHERE ("id">$1) AND ("create_time" BETWEEN $2 AND $3);`,
+			wantArgs: []interface{}{10, start, end},
+		},
+		{
+			name:    "invalid column",
+			builder: NewSelector[Order](db).Select(C("Id")).Where(C("Invalid").InRange(start, end)),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
 
 func ExampleC() {
 	db := memoryDB()
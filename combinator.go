@@ -0,0 +1,139 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"github.com/valyala/bytebufferpool"
+)
+
+// Combinator 用于构造 UNION、UNION ALL 这种集合操作
+// 每一个操作数都会被括号包起来，例如 (SELECT ...) UNION (SELECT ...)
+// 不过 SQLite 不支持带括号的操作数，所以在 SQLite 上不会加括号
+type Combinator[T any] struct {
+	builder
+	session
+	queries []QueryBuilder
+	// ops[i] 是 queries[i] 和 queries[i+1] 之间的操作
+	ops []string
+	// orderBy、limit 和 offset 作用于整个结果集，而不是某一个操作数
+	orderBy []OrderBy
+	limit   int
+	offset  int
+}
+
+// NewCombinator 创建一个 Combinator，q 是第一个操作数
+func NewCombinator[T any](sess session, q QueryBuilder) *Combinator[T] {
+	return &Combinator[T]{
+		builder: builder{
+			core:   sess.getCore(),
+			buffer: bytebufferpool.Get(),
+		},
+		session: sess,
+		queries: []QueryBuilder{q},
+	}
+}
+
+// Union 使用 UNION 连接 q，结果会去重
+func (c *Combinator[T]) Union(q QueryBuilder) *Combinator[T] {
+	return c.combine("UNION", q)
+}
+
+// UnionAll 使用 UNION ALL 连接 q，结果不会去重
+func (c *Combinator[T]) UnionAll(q QueryBuilder) *Combinator[T] {
+	return c.combine("UNION ALL", q)
+}
+
+func (c *Combinator[T]) combine(op string, q QueryBuilder) *Combinator[T] {
+	c.ops = append(c.ops, op)
+	c.queries = append(c.queries, q)
+	return c
+}
+
+// OrderBy 对整个结果集排序，排序的列按照 T 来解析
+func (c *Combinator[T]) OrderBy(orderBys ...OrderBy) *Combinator[T] {
+	c.orderBy = orderBys
+	return c
+}
+
+// Limit 限制整个结果集的行数
+func (c *Combinator[T]) Limit(limit int) *Combinator[T] {
+	c.limit = limit
+	return c
+}
+
+// Offset 跳过整个结果集的前 offset 行
+func (c *Combinator[T]) Offset(offset int) *Combinator[T] {
+	c.offset = offset
+	return c
+}
+
+// Build 构造集合操作的 SQL，各个操作数的参数按照顺序排列，
+// ORDER BY 和 LIMIT 会放在最后一个操作数的后面，它们的参数也排在所有操作数的参数之后
+func (c *Combinator[T]) Build() (*Query, error) {
+	defer bytebufferpool.Put(c.buffer)
+	var err error
+	c.meta, err = c.metaRegistry.Get(new(T))
+	if err != nil {
+		return nil, err
+	}
+	paren := c.features().CompoundParens
+	for i, q := range c.queries {
+		if i > 0 {
+			c.writeByte(' ')
+			c.writeString(c.ops[i-1])
+			c.writeByte(' ')
+		}
+		if err = c.buildOperand(q, paren); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.orderBy) > 0 {
+		c.writeString(" ORDER BY ")
+		if err = c.buildOrderByList(c.orderBy); err != nil {
+			return nil, err
+		}
+	}
+	if c.limit > 0 || c.offset > 0 {
+		limitOffset, args := c.dialect.LimitOffset(c.limit, c.offset)
+		c.buildRawExpr(Raw(limitOffset, args...))
+	}
+	if err = c.checkArgs(); err != nil {
+		return nil, err
+	}
+	c.end()
+	return &Query{SQL: c.buffer.String(), Args: c.args}, nil
+}
+
+// buildOperand 构造一个操作数，paren 为 false 的时候去掉 buildSubquery 加上的括号
+func (c *Combinator[T]) buildOperand(q QueryBuilder, paren bool) error {
+	start := c.buffer.Len()
+	if err := c.buildSubquery(Subquery{q: q}, false); err != nil {
+		return err
+	}
+	if !paren {
+		c.buffer.B = append(c.buffer.B[:start], c.buffer.B[start+1:len(c.buffer.B)-1]...)
+	}
+	return nil
+}
+
+// AsSubquery 将集合操作的结果作为派生表，例如 FROM ((SELECT ...) UNION (SELECT ...)) AS combined
+// 通过返回值的 C 引用的列会按照 T 来解析
+func (c *Combinator[T]) AsSubquery(alias string) Subquery {
+	return Subquery{
+		entity: TableOf(new(T)),
+		q:      c,
+		alias:  alias,
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombinator_Build(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name: "union",
+			builder: NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).
+				Union(NewSelector[TestModel](db).Select(C("Id")).Where(C("FirstName").EQ("Tom"))),
+			wantSql:  "(SELECT `id` FROM `test_model` WHERE `age`>?) UNION (SELECT `id` FROM `test_model` WHERE `first_name`=?);",
+			wantArgs: []interface{}{18, "Tom"},
+		},
+		{
+			name: "union all",
+			builder: NewCombinator[TestModel](db, NewSelector[TestModel](db).Select(C("Id"))).
+				UnionAll(NewSelector[TestModel](db).Select(C("Id"))).
+				Union(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").EQ(1))),
+			wantSql:  "(SELECT `id` FROM `test_model`) UNION ALL (SELECT `id` FROM `test_model`) UNION (SELECT `id` FROM `test_model` WHERE `age`=?);",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "sqlite",
+			builder: NewSelector[TestModel](memoryDB()).Select(C("Id")).Where(C("Age").GT(18)).
+				UnionAll(NewSelector[TestModel](memoryDB()).Select(C("Id"))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `age`>? UNION ALL SELECT `id` FROM `test_model`;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "postgres",
+			builder: NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").GT(18)).
+				Union(NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").LT(60))),
+			wantSql:  `(SELECT "id" FROM "test_model" WHERE "age">$1) UNION (SELECT "id" FROM "test_model" WHERE "age"<$2);`,
+			wantArgs: []interface{}{18, 60},
+		},
+		{
+			name: "derived table",
+			builder: func() QueryBuilder {
+				combined := NewSelector[TestModel](db).Select(C("Id"), C("Age")).Where(C("Age").GT(18)).
+					Union(NewSelector[TestModel](db).Select(C("Id"), C("Age")).Where(C("FirstName").EQ("Tom"))).
+					AsSubquery("combined")
+				return NewSelector[TestModel](db).Select(combined.C("Id")).From(combined).
+					Where(combined.C("Age").LT(60))
+			}(),
+			wantSql: "SELECT `combined`.`id` FROM ((SELECT `id`,`age` FROM `test_model` WHERE `age`>?) " +
+				"UNION (SELECT `id`,`age` FROM `test_model` WHERE `first_name`=?)) AS `combined` WHERE `combined`.`age`<?;",
+			wantArgs: []interface{}{18, "Tom", 60},
+		},
+		{
+			name: "postgres derived table",
+			builder: func() QueryBuilder {
+				combined := NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").GT(18)).
+					UnionAll(NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").LT(10))).
+					AsSubquery("combined")
+				return NewSelector[TestModel](postgresDB()).Select(combined.C("Id")).From(combined).
+					Where(combined.C("Id").GT(100))
+			}(),
+			wantSql: `SELECT "combined"."id" FROM ((SELECT "id" FROM "test_model" WHERE "age">$1) ` +
+				`UNION ALL (SELECT "id" FROM "test_model" WHERE "age"<$2)) AS "combined" WHERE "combined"."id">$3;`,
+			wantArgs: []interface{}{18, 10, 100},
+		},
+		{
+			name: "order by and limit",
+			builder: NewSelector[TestModel](db).Select(C("Id"), C("Age")).Where(C("Age").GT(18)).Limit(5).
+				Union(NewSelector[TestModel](db).Select(C("Id"), C("Age")).Where(C("FirstName").EQ("Tom"))).
+				OrderBy(DESC("Age"), ASC("Id")).Limit(10),
+			wantSql: "(SELECT `id`,`age` FROM `test_model` WHERE `age`>? LIMIT ?) " +
+				"UNION (SELECT `id`,`age` FROM `test_model` WHERE `first_name`=?) ORDER BY `age` DESC,`id` ASC LIMIT ?;",
+			wantArgs: []interface{}{18, 5, "Tom", 10},
+		},
+		{
+			name: "postgres order by and limit",
+			builder: NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").GT(18)).
+				UnionAll(NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").LT(10))).
+				OrderBy(ASC("Id")).Limit(10).Offset(5),
+			wantSql:  `(SELECT "id" FROM "test_model" WHERE "age">$1) UNION ALL (SELECT "id" FROM "test_model" WHERE "age"<$2) ORDER BY "id" ASC LIMIT $3 OFFSET $4;`,
+			wantArgs: []interface{}{18, 10, 10, 5},
+		},
+		{
+			name: "order by and limit in derived table",
+			builder: func() QueryBuilder {
+				combined := NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).
+					Union(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").LT(10))).
+					OrderBy(ASC("Id")).Limit(3).AsSubquery("combined")
+				return NewSelector[TestModel](db).Select(combined.C("Id")).From(combined).Where(combined.C("Id").GT(100))
+			}(),
+			wantSql: "SELECT `combined`.`id` FROM ((SELECT `id` FROM `test_model` WHERE `age`>?) " +
+				"UNION (SELECT `id` FROM `test_model` WHERE `age`<?) ORDER BY `id` ASC LIMIT ?) AS `combined` WHERE `combined`.`id`>?;",
+			wantArgs: []interface{}{18, 10, 3, 100},
+		},
+		{
+			name: "invalid order by",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Union(NewSelector[TestModel](db).Select(C("Id"))).OrderBy(ASC("Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
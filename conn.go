@@ -0,0 +1,56 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"database/sql"
+)
+
+var _ session = &Conn{}
+
+// Conn 代表一个固定的连接
+// 在它上面执行的所有语句都会使用同一个连接，
+// 适用于 FOUND_ROWS()、临时表这种依赖连接状态的场景
+type Conn struct {
+	conn *sql.Conn
+	db   *DB
+}
+
+func (c *Conn) getCore() core {
+	return c.db.core
+}
+
+func (c *Conn) queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return c.conn.QueryContext(ctx, tagQuery(ctx, query), args...)
+}
+
+func (c *Conn) execContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return c.conn.ExecContext(ctx, tagQuery(ctx, query), args...)
+}
+
+// BeginTx 在该连接上开启事务
+func (c *Conn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := c.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx, db: c.db}, nil
+}
+
+// Close 将连接归还给连接池
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
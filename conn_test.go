@@ -0,0 +1,93 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_Conn(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectExec("UPDATE `test_model` SET `age`=").WithArgs(18).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery("SELECT `id` FROM `test_model`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	res := NewUpdater[TestModel](conn).Set(Assign("Age", 18)).Exec(context.Background())
+	affected, err := res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), affected)
+	tm, err := NewSelector[TestModel](conn).Select(C("Id")).Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 1}, tm)
+	require.NoError(t, conn.Close())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_Conn_TemporaryTable(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	// 只有一个连接，如果语句没有跑在固定的连接上，那么就拿不到连接
+	mockDB.SetMaxOpenConns(1)
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectExec("CREATE TEMPORARY TABLE `test_model`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO `test_model`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT `id` FROM `test_model`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	res := RawQuery[any](conn, "CREATE TEMPORARY TABLE `test_model` (`id` INT)").Exec(ctx)
+	require.NoError(t, res.Err())
+	res = NewInserter[TestModel](conn).Values(&TestModel{Id: 1}).Exec(ctx)
+	require.NoError(t, res.Err())
+	tm, err := NewSelector[TestModel](conn).Select(C("Id")).Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 1}, tm)
+
+	// 连接被固定住了，DB 上的查询拿不到连接
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = NewSelector[TestModel](db).Get(timeoutCtx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// 归还连接之后，DB 可以继续使用这个连接
+	require.NoError(t, conn.Close())
+	mock.ExpectQuery("SELECT `id` FROM `test_model`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	tm, err = NewSelector[TestModel](db).Select(C("Id")).Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 1}, tm)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
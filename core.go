@@ -17,6 +17,7 @@ package eorm
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/gotomicro/eorm/internal/dialect"
 	"github.com/gotomicro/eorm/internal/errs"
@@ -29,18 +30,40 @@ type core struct {
 	metaRegistry model.MetaRegistry
 	dialect      dialect.Dialect
 	valCreator   valuer.BasicTypeCreator
+	// explicitInnerJoin 为 true 的时候，内连接会被渲染为 INNER JOIN 而不是 JOIN
+	explicitInnerJoin bool
+	// defaultSelectLimit 大于 0 的时候，没有设置 Limit 的 GetMulti 之类的查询会使用该值作为 LIMIT
+	defaultSelectLimit int
+	// retryPolicy 为 nil 的时候，GetForUpdate 和 DoTx 都不会重试
+	retryPolicy *RetryPolicy
+	// maxJoinDepth 大于 0 的时候，JOIN 嵌套的深度不能超过它
+	maxJoinDepth int
+	// clock 用于获取当前时间，例如填充创建时间、更新时间和软删除的时间
+	clock func() time.Time
+	// timeLocation 不为 nil 的时候，Get 和 GetMulti 读取到的 time.Time 都会被转换到该时区
+	timeLocation *time.Location
+	// tablePrefix 会在构造 SQL 的时候加到所有表名的前面
+	tablePrefix string
 }
 
 func getHandler[T any](ctx context.Context, sess session, c core, qc *QueryContext) *QueryResult {
+	return getIntoHandler[T](ctx, sess, c, qc, new(T))
+}
+
+// getIntoHandler 读取第一行数据，并且写入到 tp 中
+func getIntoHandler[T any](ctx context.Context, sess session, c core, qc *QueryContext, tp *T) *QueryResult {
 	rows, err := sess.queryContext(ctx, qc.q.SQL, qc.q.Args...)
 	if err != nil {
 		return &QueryResult{Err: err}
 	}
+	// 只读取第一行，需要主动关闭，否则固定的连接上无法执行下一个语句
+	defer func() {
+		_ = rows.Close()
+	}()
 	if !rows.Next() {
 		return &QueryResult{Err: errs.ErrNoRows}
 	}
 
-	tp := new(T)
 	meta := qc.meta
 	if meta == nil && reflect.TypeOf(tp).Elem().Kind() == reflect.Struct {
 		//  当通过 RawQuery 方法调用 Get ,如果 T 是 time.Time, sql.Scanner 的实现，
@@ -53,6 +76,7 @@ func getHandler[T any](ctx context.Context, sess session, c core, qc *QueryConte
 	if err = val.SetColumns(rows); err != nil {
 		return &QueryResult{Err: err}
 	}
+	c.inLocation(tp, meta)
 	return &QueryResult{Result: tp}
 }
 
@@ -68,11 +92,27 @@ func get[T any](ctx context.Context, sess session, core core, qc *QueryContext)
 }
 
 func getMultiHandler[T any](ctx context.Context, sess session, c core, qc *QueryContext) *QueryResult {
-	rows, err := sess.queryContext(ctx, qc.q.SQL, qc.q.Args...)
+	res := make([]*T, 0, 16)
+	err := forEach[T](ctx, sess, c, qc, func(t *T) error {
+		res = append(res, t)
+		return nil
+	})
 	if err != nil {
 		return &QueryResult{Err: err}
 	}
-	res := make([]*T, 0, 16)
+	return &QueryResult{Result: res}
+}
+
+// forEach 执行查询，并且在遍历结果集的过程中，对每一行数据调用 fn
+// fn 返回 error 的时候会中断遍历
+func forEach[T any](ctx context.Context, sess session, c core, qc *QueryContext, fn func(t *T) error) error {
+	rows, err := sess.queryContext(ctx, qc.q.SQL, qc.q.Args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
 	meta := qc.meta
 	if meta == nil {
 		t := new(T)
@@ -87,11 +127,50 @@ func getMultiHandler[T any](ctx context.Context, sess session, c core, qc *Query
 		tp := new(T)
 		val := c.valCreator.NewBasicTypeValue(tp, meta)
 		if err = val.SetColumns(rows); err != nil {
-			return &QueryResult{Err: err}
+			return err
+		}
+		c.inLocation(tp, meta)
+		if err = fn(tp); err != nil {
+			return err
 		}
-		res = append(res, tp)
 	}
-	return &QueryResult{Result: res}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// features 返回当前方言支持的特性
+func (c core) features() dialect.Features {
+	return dialect.FeaturesOf(c.dialect)
+}
+
+// inLocation 将 tp 中的 time.Time 转换到 timeLocation 对应的时区
+// tp 可以是 *time.Time，也可以是指向结构体的指针，此时会处理 time.Time 和 *time.Time 类型的字段
+func (c core) inLocation(tp any, meta *model.TableMeta) {
+	if c.timeLocation == nil {
+		return
+	}
+	val := reflect.ValueOf(tp).Elem()
+	if val.Type() == timeType {
+		setInLocation(val, c.timeLocation)
+		return
+	}
+	if meta == nil || val.Kind() != reflect.Struct || meta.Typ.Elem() != val.Type() {
+		return
+	}
+	for _, cm := range meta.Columns {
+		fd := val.FieldByIndex(cm.FieldIndexes)
+		switch {
+		case fd.Type() == timeType:
+			setInLocation(fd, c.timeLocation)
+		case fd.Kind() == reflect.Ptr && fd.Type().Elem() == timeType && !fd.IsNil():
+			setInLocation(fd.Elem(), c.timeLocation)
+		}
+	}
+}
+
+func setInLocation(val reflect.Value, loc *time.Location) {
+	val.Set(reflect.ValueOf(val.Interface().(time.Time).In(loc)))
 }
 
 func getMulti[T any](ctx context.Context, sess session, core core, qc *QueryContext) *QueryResult {
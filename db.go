@@ -19,6 +19,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"log"
+	"reflect"
 	"time"
 
 	"github.com/gotomicro/eorm/internal/dialect"
@@ -41,6 +42,43 @@ type DBOption func(db *DB)
 type DB struct {
 	db *sql.DB
 	core
+	// stmtCache 不为 nil 的时候，DB 上的查询会复用预编译的 stmt
+	stmtCache *stmtCache
+	// converters 是通过 DBWithConverter 注册的类型转换器
+	converters map[reflect.Type]model.Converter
+}
+
+// Dialect 代表 SQL 方言，用户可以实现该接口来支持 eorm 没有内置的数据库
+type Dialect = dialect.Dialect
+
+// DialectFeatures 描述了方言支持的特性，自定义的方言可以实现 Features() DialectFeatures 方法，
+// 没有实现的方言只使用标准 SQL 的写法
+type DialectFeatures = dialect.Features
+
+// DateTrunc、JSONTable 和 Median 在不同方言中的写法，用于 DialectFeatures
+const (
+	DateTruncFunc        = dialect.DateTruncFunc
+	DateTruncFormat      = dialect.DateTruncFormat
+	DateTruncStrftime    = dialect.DateTruncStrftime
+	JSONTableUnsupported = dialect.JSONTableUnsupported
+	JSONTableFunc        = dialect.JSONTableFunc
+	JSONTableRecordset   = dialect.JSONTableRecordset
+	MedianUnsupported    = dialect.MedianUnsupported
+	MedianPercentileCont = dialect.MedianPercentileCont
+	MedianGroupConcat    = dialect.MedianGroupConcat
+)
+
+// RegisterDialect 为 driver 注册方言，之后使用该 driver 的 DB 都会使用这个方言
+// 一般在 init 方法中调用
+func RegisterDialect(driver string, d Dialect) {
+	dialect.Register(driver, d)
+}
+
+// DBWithDialect 为 db 指定方言，它的优先级比根据 driver 查找到的方言高
+func DBWithDialect(d Dialect) DBOption {
+	return func(db *DB) {
+		db.dialect = d
+	}
 }
 
 // DBWithMiddleware 为 db 配置 Middleware
@@ -50,6 +88,92 @@ func DBWithMiddleware(ms ...Middleware) DBOption {
 	}
 }
 
+// DBWithHooks 为 db 配置 Hook，它们会按照顺序被转化为 Middleware，
+// 排在已经配置的 Middleware 之后
+// 注意 DBWithMiddleware 会覆盖之前的配置，所以需要放在它的后面
+func DBWithHooks(hs ...Hook) DBOption {
+	return func(db *DB) {
+		for _, h := range hs {
+			db.ms = append(db.ms, h.Middleware())
+		}
+	}
+}
+
+// DBWithExplicitInnerJoin 内连接使用 INNER JOIN 而不是 JOIN，
+// 不影响 LEFT JOIN、RIGHT JOIN 等其它连接
+func DBWithExplicitInnerJoin() DBOption {
+	return func(db *DB) {
+		db.explicitInnerJoin = true
+	}
+}
+
+// DBWithDefaultSelectLimit 为读取多行数据的查询设置一个默认的 LIMIT，
+// 包括 GetMulti、GetMultiVal、MapMulti 和 Rows，避免在生产环境中意外地扫描全表
+// 如果 Selector 已经设置了 Limit，或者调用了 NoLimit，那么不会使用该值
+func DBWithDefaultSelectLimit(n int) DBOption {
+	return func(db *DB) {
+		db.defaultSelectLimit = n
+	}
+}
+
+// DBWithStmtCache 缓存预编译的语句，最多缓存 size 条，超过之后按照 LRU 淘汰
+// 开启之后，直接在 DB 上执行的查询会复用 *sql.Stmt，从而减少数据库解析 SQL 的开销
+// 事务 Tx 和 Conn 上的查询不会使用该缓存，通过 WithQueryTag 加上了标签的查询也不会使用该缓存
+func DBWithStmtCache(size int) DBOption {
+	return func(db *DB) {
+		if size > 0 {
+			db.stmtCache = newStmtCache(size)
+		}
+	}
+}
+
+// DBWithMaxJoinDepth 限制 JOIN 嵌套的深度，超过之后 Build 会返回错误
+// 例如 t1.Join(t2).Join(t3) 的深度是 2，一般用于防止动态拼接查询的时候出现失控的递归
+func DBWithMaxJoinDepth(depth int) DBOption {
+	return func(db *DB) {
+		db.maxJoinDepth = depth
+	}
+}
+
+// DBWithClock 指定获取当前时间的方法，默认是 time.Now
+// 一般用于测试，使得自动填充的时间是确定的
+func DBWithClock(clock func() time.Time) DBOption {
+	return func(db *DB) {
+		db.clock = clock
+	}
+}
+
+// DBWithTimeLocation 指定时区，Get 和 GetMulti 读取到的 time.Time 都会被转换到 loc
+// 不同的驱动对时区的处理不一样，例如 MySQL 驱动默认会按照 UTC 解析 DATETIME，
+// 设置之后无论驱动返回的是哪个时区，得到的都是同一个时刻在 loc 中的表示
+func DBWithTimeLocation(loc *time.Location) DBOption {
+	return func(db *DB) {
+		db.timeLocation = loc
+	}
+}
+
+// DBWithConverter 为类型 typ 注册转换器，适用于既没有实现 driver.Valuer 也没有实现 sql.Scanner 的类型
+// 插入、更新以及在 WHERE 中和该类型的值比较的时候会使用 toDB 转换参数，
+// 读取的时候会使用 fromDB 将数据库中的值转换为字段的值，返回值必须能够赋值给 typ
+func DBWithConverter(typ reflect.Type, toDB func(val any) (driver.Value, error),
+	fromDB func(src []byte) (any, error)) DBOption {
+	return func(db *DB) {
+		if db.converters == nil {
+			db.converters = make(map[reflect.Type]model.Converter, 4)
+		}
+		db.converters[typ] = model.Converter{ToDB: toDB, FromDB: fromDB}
+	}
+}
+
+// DBWithTablePrefix 为所有的表名加上前缀 prefix，例如 prefix 为 tenant123_ 的时候，
+// TestModel 对应的表名是 tenant123_test_model。
+// 前缀只会在构造 SQL 的时候加上，不会修改元数据中的 TableName，单个查询可以通过 TablePrefix 覆盖
+func DBWithTablePrefix(prefix string) DBOption {
+	return func(db *DB) {
+		db.tablePrefix = prefix
+	}
+}
+
 func UseReflection() DBOption {
 	return func(db *DB) {
 		db.valCreator = valuer.BasicTypeCreator{Creator: valuer.NewUnsafeValue}
@@ -57,11 +181,32 @@ func UseReflection() DBOption {
 }
 
 func (db *DB) queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
-	return db.db.QueryContext(ctx, query, args...)
+	// 带有标签的 SQL 每次都可能不一样，预编译之后几乎不会被复用，还会把缓存中的语句挤出去，
+	// 所以不使用缓存
+	if tagged := tagQuery(ctx, query); db.stmtCache == nil || tagged != query {
+		return db.db.QueryContext(ctx, tagged, args...)
+	}
+	e, err := db.stmtCache.acquire(ctx, db.db, query)
+	if err != nil {
+		return nil, err
+	}
+	// 即便 stmt 在 rows 关闭之前被淘汰，database/sql 也会等到 rows 关闭之后才真正释放它
+	defer db.stmtCache.release(e)
+	return e.stmt.QueryContext(ctx, args...)
 }
 
 func (db *DB) execContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
-	return db.db.ExecContext(ctx, query, args...)
+	// 带有标签的 SQL 每次都可能不一样，预编译之后几乎不会被复用，还会把缓存中的语句挤出去，
+	// 所以不使用缓存
+	if tagged := tagQuery(ctx, query); db.stmtCache == nil || tagged != query {
+		return db.db.ExecContext(ctx, tagged, args...)
+	}
+	e, err := db.stmtCache.acquire(ctx, db.db, query)
+	if err != nil {
+		return nil, err
+	}
+	defer db.stmtCache.release(e)
+	return e.stmt.ExecContext(ctx, args...)
 }
 
 // Open 创建一个 ORM 实例
@@ -75,24 +220,30 @@ func Open(driver string, dsn string, opts ...DBOption) (*DB, error) {
 }
 
 func openDB(driver string, db *sql.DB, opts ...DBOption) (*DB, error) {
-	dl, err := dialect.Of(driver)
-	if err != nil {
-		return nil, err
-	}
 	orm := &DB{
 		core: core{
 			metaRegistry: model.NewMetaRegistry(),
-			dialect:      dl,
 			// 可以设为默认，因为原本这里也有默认
 			valCreator: valuer.BasicTypeCreator{
 				Creator: valuer.NewUnsafeValue,
 			},
+			clock: time.Now,
 		},
 		db: db,
 	}
 	for _, o := range opts {
 		o(orm)
 	}
+	if len(orm.converters) > 0 {
+		orm.metaRegistry = model.NewMetaRegistry(model.WithConverters(orm.converters))
+	}
+	if orm.dialect == nil {
+		dl, err := dialect.Of(driver)
+		if err != nil {
+			return nil, err
+		}
+		orm.dialect = dl
+	}
 	return orm, nil
 }
 
@@ -105,6 +256,54 @@ func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 	return &Tx{tx: tx, db: db}, nil
 }
 
+// DoTx 开启一个事务并且执行 fn，fn 返回 nil 的时候提交事务，否则回滚事务
+// 如果设置了 DBWithRetryPolicy，那么遇到死锁或者锁等待超时的时候，
+// 会回滚事务，然后开启一个新的事务重新执行 fn，所以 fn 必须是可以重复执行的
+func (db *DB) DoTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *Tx) error) error {
+	err := db.doTx(ctx, opts, fn)
+	p := db.retryPolicy
+	if p == nil {
+		return err
+	}
+	for attempt := 1; attempt < p.MaxAttempts && err != nil && p.retryable(err); attempt++ {
+		if werr := p.wait(ctx, attempt); werr != nil {
+			return werr
+		}
+		err = db.doTx(ctx, opts, fn)
+	}
+	return err
+}
+
+func (db *DB) doTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx *Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		// fn 返回错误或者 panic 的时候都需要回滚，
+		// 回滚的错误会被忽略，以免覆盖掉 fn 的错误，导致无法判断是否需要重试
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+	if err = fn(ctx, tx); err != nil {
+		return err
+	}
+	committed = true
+	return tx.Commit()
+}
+
+// Conn 从连接池中拿出一个连接，之后在该连接上执行的语句都使用同一个连接
+// 用完之后需要调用 Conn.Close 将连接归还给连接池
+func (db *DB) Conn(ctx context.Context) (*Conn, error) {
+	conn, err := db.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn, db: db}, nil
+}
+
 // Wait 会等待数据库连接
 // 注意只能用于测试
 func (db *DB) Wait() error {
@@ -118,6 +317,9 @@ func (db *DB) Wait() error {
 }
 
 func (db *DB) Close() error {
+	if db.stmtCache != nil {
+		db.stmtCache.close()
+	}
 	return db.db.Close()
 }
 
@@ -17,14 +17,21 @@ package eorm
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/dialect"
+	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/gotomicro/eorm/internal/valuer"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDB_BeginTx(t *testing.T) {
@@ -50,6 +57,85 @@ func TestDB_BeginTx(t *testing.T) {
 	assert.NotNil(t, tx)
 }
 
+func TestDBWithDialect(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	_, err = openDB("sqlserver", mockDB)
+	assert.Equal(t, errs.NewUnsupportedDriverError("sqlserver"), err)
+
+	db, err := openDB("sqlserver", mockDB, DBWithDialect(sqlServer{}))
+	require.NoError(t, err)
+	query, err := NewSelector[TestModel](db).Select(C("Id")).
+		Where(C("Age").GT(18)).Offset(10).Limit(5).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT [id] FROM [test_model] WHERE [age]>@p1 ORDER BY (SELECT NULL) OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY;", query.SQL)
+	assert.Equal(t, []any{18, 10, 5}, query.Args)
+
+	RegisterDialect("sqlserver", sqlServer{})
+	db, err = openDB("sqlserver", mockDB)
+	require.NoError(t, err)
+	assert.Equal(t, "SQLServer", db.dialect.Name())
+}
+
+// sqlServer 是一个简化的 SQL Server 方言，用于测试自定义方言
+type sqlServer struct{}
+
+func (sqlServer) Name() string {
+	return "SQLServer"
+}
+
+func (sqlServer) Quote(identifier string) string {
+	return "[" + identifier + "]"
+}
+
+func (sqlServer) Placeholder(index int) string {
+	return "@p" + strconv.Itoa(index)
+}
+
+func (sqlServer) LimitOffset(limit int, offset int) (string, []any) {
+	if limit > 0 {
+		return " ORDER BY (SELECT NULL) OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []any{offset, limit}
+	}
+	return " ORDER BY (SELECT NULL) OFFSET ? ROWS", []any{offset}
+}
+
+func (sqlServer) SupportsReturning() bool {
+	return false
+}
+
+func (sqlServer) MaxParams() int {
+	return 2100
+}
+
+func (sqlServer) SupportsApply() bool {
+	return true
+}
+
+// tidb 用于测试自定义的方言通过 Features 声明自己支持的特性，而不是根据名字判断
+type tidb struct {
+	Dialect
+}
+
+func (tidb) Name() string {
+	return "TiDB"
+}
+
+func (tidb) Features() DialectFeatures {
+	return dialect.FeaturesOf(dialect.MySQL)
+}
+
+// limitedDialect 用于测试参数个数的限制
+type limitedDialect struct {
+	Dialect
+	maxParams int
+}
+
+func (d limitedDialect) MaxParams() int {
+	return d.maxParams
+}
+
 func TestDB_Wait(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	if err != nil {
@@ -107,7 +193,7 @@ func ExampleDB_BeginTx() {
 func ExampleOpen() {
 	// case1 without DBOption
 	db, _ := Open("sqlite3", "file:test.db?cache=shared&mode=memory")
-	fmt.Printf("case1 dialect: %s\n", db.dialect.Name)
+	fmt.Printf("case1 dialect: %s\n", db.dialect.Name())
 
 	// Output:
 	// case1 dialect: SQLite
@@ -142,6 +228,19 @@ func memoryDB() *DB {
 	return orm
 }
 
+// postgresDB 返回一个使用 PostgreSQL 方言的 ORM，它只能用于构造 SQL
+func postgresDB() *DB {
+	mockDB, _, err := sqlmock.New()
+	if err != nil {
+		panic(err)
+	}
+	orm, err := openDB("postgres", mockDB)
+	if err != nil {
+		panic(err)
+	}
+	return orm
+}
+
 func memoryDBWithDB(db string) *DB {
 	orm, err := Open("sqlite3", fmt.Sprintf("file:%s.db?cache=shared&mode=memory", db))
 	if err != nil {
@@ -207,3 +306,176 @@ func BenchmarkQuerier_Get(b *testing.B) {
 		}
 	})
 }
+
+// testSecret 既没有实现 driver.Valuer 也没有实现 sql.Scanner，存储的时候加上 enc: 前缀
+type testSecret struct {
+	plain string
+}
+
+func TestDBWithConverter(t *testing.T) {
+	type Account struct {
+		Id     int64
+		Secret testSecret
+	}
+	converter := DBWithConverter(reflect.TypeOf(testSecret{}),
+		func(val any) (driver.Value, error) {
+			return "enc:" + val.(testSecret).plain, nil
+		},
+		func(src []byte) (any, error) {
+			s := string(src)
+			if !strings.HasPrefix(s, "enc:") {
+				return nil, errors.New("缺少前缀")
+			}
+			return testSecret{plain: strings.TrimPrefix(s, "enc:")}, nil
+		})
+	db, err := openDB("sqlite3", nil, converter)
+	require.NoError(t, err)
+	acc := &Account{Id: 1, Secret: testSecret{plain: "pwd"}}
+	testCases := []CommonTestCase{
+		{
+			name:     "insert",
+			builder:  NewInserter[Account](db).Values(acc),
+			wantSql:  "INSERT INTO `account`(`id`,`secret`) VALUES(?,?);",
+			wantArgs: []interface{}{int64(1), "enc:pwd"},
+		},
+		{
+			name:     "update",
+			builder:  NewUpdater[Account](db).Update(acc).Set(C("Secret")).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `account` SET `secret`=? WHERE `id`=?;",
+			wantArgs: []interface{}{"enc:pwd", 1},
+		},
+		{
+			name:     "where",
+			builder:  NewSelector[Account](db).Where(C("Secret").EQ(testSecret{plain: "pwd"})),
+			wantSql:  "SELECT `id`,`secret` FROM `account` WHERE `secret`=?;",
+			wantArgs: []interface{}{"enc:pwd"},
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+
+	scanCases := []struct {
+		name string
+		opts []DBOption
+	}{
+		{
+			name: "unsafe",
+		},
+		{
+			name: "reflect",
+			opts: []DBOption{UseReflection()},
+		},
+	}
+	for _, sc := range scanCases {
+		t.Run(sc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB, append(sc.opts, converter)...)
+			require.NoError(t, err)
+
+			mock.ExpectQuery("SELECT `id`,`secret` FROM `account`;").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "secret"}).
+					AddRow(1, []byte("enc:pwd")).AddRow(2, nil))
+			res, err := NewSelector[Account](db).GetMulti(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []*Account{acc, {Id: 2}}, res)
+
+			mock.ExpectQuery("SELECT `id`,`secret` FROM `account` LIMIT ?;").WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "secret"}).AddRow(1, "pwd"))
+			_, err = NewSelector[Account](db).Get(context.Background())
+			require.Error(t, err)
+			assert.True(t, strings.Contains(err.Error(), "eorm: 列 secret 类型转换失败: 缺少前缀"))
+		})
+	}
+}
+
+func TestDBWithTablePrefix(t *testing.T) {
+	db, err := openDB("sqlite3", nil, DBWithTablePrefix("tenant123_"))
+	require.NoError(t, err)
+	type TestModel2 struct {
+		UserId int64
+		Phone  int64
+	}
+	t1 := TableOf(&TestModel{}).As("t1")
+	t2 := TableOf(&TestModel2{})
+	testCases := []CommonTestCase{
+		{
+			name:     "select",
+			builder:  NewSelector[TestModel](db).Where(C("Id").EQ(1)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `tenant123_test_model` WHERE `id`=?;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "join",
+			builder: NewSelector[TestModel](db).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId")))),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM (`tenant123_test_model` AS `t1` JOIN `tenant123_test_model2` ON `t1`.`id`=`user_id`);",
+		},
+		{
+			name:     "insert",
+			builder:  NewInserter[TestModel](db).Values(&TestModel{Id: 1}).Columns("Id"),
+			wantSql:  "INSERT INTO `tenant123_test_model`(`id`) VALUES(?);",
+			wantArgs: []interface{}{int64(1)},
+		},
+		{
+			name:     "update",
+			builder:  NewUpdater[TestModel](db).Set(Assign("Age", 18)),
+			wantSql:  "UPDATE `tenant123_test_model` SET `age`=?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:    "delete",
+			builder: NewDeleter[TestModel](db),
+			wantSql: "DELETE FROM `tenant123_test_model`;",
+		},
+		{
+			name:    "override select",
+			builder: NewSelector[TestModel](db).TablePrefix("tenant456_"),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `tenant456_test_model`;",
+		},
+		{
+			name:     "override insert",
+			builder:  NewInserter[TestModel](db).TablePrefix("").Values(&TestModel{Id: 1}).Columns("Id"),
+			wantSql:  "INSERT INTO `test_model`(`id`) VALUES(?);",
+			wantArgs: []interface{}{int64(1)},
+		},
+		{
+			name:     "override update",
+			builder:  NewUpdater[TestModel](db).TablePrefix("tenant456_").Set(Assign("Age", 18)),
+			wantSql:  "UPDATE `tenant456_test_model` SET `age`=?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:    "override delete",
+			builder: NewDeleter[TestModel](db).TablePrefix("tenant456_"),
+			wantSql: "DELETE FROM `tenant456_test_model`;",
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+
+	// 覆盖前缀不会影响其它查询
+	query, err := NewSelector[TestModel](db).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `id`,`first_name`,`age`,`last_name` FROM `tenant123_test_model`;", query.SQL)
+}
@@ -17,6 +17,7 @@ package eorm
 import (
 	"context"
 
+	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/valyala/bytebufferpool"
 )
 
@@ -26,6 +27,8 @@ type Deleter[T any] struct {
 	session
 	table interface{}
 	where []Predicate
+	// unscoped 为 true 的时候，即便模型有软删除的列，也会物理删除
+	unscoped bool
 }
 
 // NewDeleter 开始构建一个 DELETE 查询
@@ -42,7 +45,6 @@ func NewDeleter[T any](sess session) *Deleter[T] {
 // Build returns DELETE query
 func (d *Deleter[T]) Build() (*Query, error) {
 	defer bytebufferpool.Put(d.buffer)
-	_, _ = d.buffer.WriteString("DELETE FROM ")
 	var err error
 	if d.table == nil {
 		d.table = new(T)
@@ -51,15 +53,35 @@ func (d *Deleter[T]) Build() (*Query, error) {
 	if err != nil {
 		return nil, err
 	}
+	if d.meta.IsView {
+		return nil, errs.NewReadOnlyViewError(d.meta.TableName)
+	}
 
-	d.quote(d.meta.TableName)
-	if len(d.where) > 0 {
+	where := d.where
+	if sd := d.meta.SoftDeleteColumn; sd != nil && !d.unscoped {
+		// 软删除，已经被删除的数据不需要再次更新
+		d.writeString("UPDATE ")
+		d.quoteTable(d.meta.TableName)
+		d.writeString(" SET ")
+		d.quote(sd.ColumnName)
+		d.writeByte('=')
+		d.parameter(timestampValue(sd.Typ, d.clock()))
+		where = append(append(make([]Predicate, 0, len(d.where)+1), d.where...),
+			d.isNullPredicate("", sd.ColumnName))
+	} else {
+		d.writeString("DELETE FROM ")
+		d.quoteTable(d.meta.TableName)
+	}
+	if len(where) > 0 {
 		d.writeString(" WHERE ")
-		err = d.buildPredicates(d.where)
+		err = d.buildPredicates(where)
 		if err != nil {
 			return nil, err
 		}
 	}
+	if err = d.checkArgs(); err != nil {
+		return nil, err
+	}
 	d.end()
 	return &Query{SQL: d.buffer.String(), Args: d.args}, nil
 }
@@ -76,6 +98,18 @@ func (d *Deleter[T]) Where(predicates ...Predicate) *Deleter[T] {
 	return d
 }
 
+// TablePrefix 参考 Selector.TablePrefix
+func (d *Deleter[T]) TablePrefix(prefix string) *Deleter[T] {
+	d.tablePrefix = prefix
+	return d
+}
+
+// Unscoped 物理删除数据，即便模型有 eorm:"soft_delete" 的列
+func (d *Deleter[T]) Unscoped() *Deleter[T] {
+	d.unscoped = true
+	return d
+}
+
 // Exec sql
 func (d *Deleter[T]) Exec(ctx context.Context) Result {
 	query, err := d.Build()
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gotomicro/eorm/internal/errs"
 
@@ -53,6 +54,11 @@ func TestDeleter_Build(t *testing.T) {
 			wantSql:  "DELETE FROM `test_combined_model` WHERE `create_time`=?;",
 			wantArgs: []interface{}{uint64(1000)},
 		},
+		{
+			name:    "view",
+			builder: NewDeleter[TestModelView](memoryDB()).Where(C("Id").EQ(16)),
+			wantErr: errs.NewReadOnlyViewError("test_model_view"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -60,6 +66,9 @@ func TestDeleter_Build(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			query, err := c.builder.Build()
 			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
 			assert.Equal(t, c.wantSql, query.SQL)
 			assert.Equal(t, c.wantArgs, query.Args)
 		})
@@ -187,3 +196,126 @@ func ExampleDeleter_Where() {
 	// SQL: DELETE FROM `test_model` WHERE `id`=?;
 	// Args: [12]
 }
+
+func TestDeleter_SoftDelete(t *testing.T) {
+	type User struct {
+		Id        int64
+		Name      string
+		DeletedAt *time.Time `eorm:"soft_delete"`
+	}
+	type Order struct {
+		Id        int64
+		DeletedAt *int64 `eorm:"soft_delete"`
+	}
+	testCases := []struct {
+		name     string
+		q        func(db *DB) (*Query, error)
+		wantSql  string
+		wantArgs func(t *testing.T, args []any)
+	}{
+		{
+			name: "soft delete",
+			q: func(db *DB) (*Query, error) {
+				return NewDeleter[User](db).Where(C("Id").EQ(1)).Build()
+			},
+			wantSql: "UPDATE `user` SET `deleted_at`=? WHERE (`id`=?) AND (`deleted_at` IS NULL);",
+			wantArgs: func(t *testing.T, args []any) {
+				require.Equal(t, 2, len(args))
+				_, ok := args[0].(time.Time)
+				assert.True(t, ok)
+				assert.Equal(t, 1, args[1])
+			},
+		},
+		{
+			name: "soft delete without where",
+			q: func(db *DB) (*Query, error) {
+				return NewDeleter[Order](db).Build()
+			},
+			wantSql: "UPDATE `order` SET `deleted_at`=? WHERE `deleted_at` IS NULL;",
+			wantArgs: func(t *testing.T, args []any) {
+				require.Equal(t, 1, len(args))
+				_, ok := args[0].(int64)
+				assert.True(t, ok)
+			},
+		},
+		{
+			name: "null int64",
+			q: func(db *DB) (*Query, error) {
+				type Item struct {
+					Id        int64
+					DeletedAt sql.NullInt64 `eorm:"soft_delete"`
+				}
+				return NewDeleter[Item](db).Build()
+			},
+			wantSql: "UPDATE `item` SET `deleted_at`=? WHERE `deleted_at` IS NULL;",
+			wantArgs: func(t *testing.T, args []any) {
+				require.Equal(t, 1, len(args))
+				_, ok := args[0].(int64)
+				assert.True(t, ok)
+			},
+		},
+		{
+			name: "unscoped",
+			q: func(db *DB) (*Query, error) {
+				return NewDeleter[User](db).Where(C("Id").EQ(1)).Unscoped().Build()
+			},
+			wantSql: "DELETE FROM `user` WHERE `id`=?;",
+			wantArgs: func(t *testing.T, args []any) {
+				assert.Equal(t, []any{1}, args)
+			},
+		},
+	}
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.q(db)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSql, q.SQL)
+			tc.wantArgs(t, q.Args)
+		})
+	}
+}
+
+func TestDeleter_SoftDeleteRows(t *testing.T) {
+	type SoftDeleteOrder struct {
+		Id        int64
+		Amount    int64
+		DeletedAt *int64 `eorm:"soft_delete"`
+	}
+	db := memoryDBWithDB("deleterSoftDeleteRows")
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+	err := RawQuery[any](db, "CREATE TABLE IF NOT EXISTS `soft_delete_order`("+
+		"`id` INTEGER PRIMARY KEY,`amount` INTEGER NOT NULL,`deleted_at` INTEGER)").Exec(ctx).Err()
+	require.NoError(t, err)
+	err = NewInserter[SoftDeleteOrder](db).Values(&SoftDeleteOrder{Id: 1, Amount: 10},
+		&SoftDeleteOrder{Id: 2, Amount: 20}, &SoftDeleteOrder{Id: 3, Amount: 30}).Exec(ctx).Err()
+	require.NoError(t, err)
+
+	// 新插入的数据都没有被删除
+	orders, err := NewSelector[SoftDeleteOrder](db).OrderBy(ASC("Id")).GetMulti(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(orders))
+
+	affected, err := NewDeleter[SoftDeleteOrder](db).Where(C("Id").EQ(2)).Exec(ctx).RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	// 已经被删除的数据不会再次更新
+	affected, err = NewDeleter[SoftDeleteOrder](db).Where(C("Id").EQ(2)).Exec(ctx).RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected)
+
+	orders, err = NewSelector[SoftDeleteOrder](db).OrderBy(ASC("Id")).GetMulti(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(orders))
+	assert.Equal(t, int64(1), orders[0].Id)
+	assert.Equal(t, int64(3), orders[1].Id)
+
+	deleted, err := NewSelector[SoftDeleteOrder](db).Where(C("Id").EQ(2)).WithDeleted().Get(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, deleted.DeletedAt)
+	assert.True(t, *deleted.DeletedAt > 0)
+}
@@ -20,4 +20,6 @@ import "github.com/gotomicro/eorm/internal/errs"
 var (
 	// ErrNoRows 代表没有找到数据
 	ErrNoRows = errs.ErrNoRows
+	// ErrOptimisticLock 代表乐观锁冲突，即更新的时候版本号已经变了
+	ErrOptimisticLock = errs.ErrOptimisticLock
 )
@@ -23,6 +23,8 @@ type Expr interface {
 type RawExpr struct {
 	raw  string
 	args []interface{}
+	// fields 是 raw 中声明引用的字段，构造 SQL 的时候会校验
+	fields []string
 }
 
 func (RawExpr) fieldName() string {
@@ -38,6 +40,8 @@ func (RawExpr) selectedAlias() string {
 }
 
 // Raw just take expr as Expr
+// expr 中可以使用 ? 作为占位符，args 会按照顺序加入到最终的参数列表中，
+// 例如在 WHERE 中使用：Where(Raw("JSON_EXTRACT(`data`, '$.x') = ?", 5).AsPredicate())
 func Raw(expr string, args ...interface{}) RawExpr {
 	return RawExpr{
 		raw:  expr,
@@ -49,14 +53,31 @@ func (r RawExpr) expr() (string, error) {
 	return r.raw, nil
 }
 
+// Using 声明 raw 中引用到的字段，构造 SQL 的时候会校验这些字段是否存在于模型中
+// eorm 并不会解析 raw 本身，所以只会校验声明的字段
+func (r RawExpr) Using(fields ...string) RawExpr {
+	r.fields = fields
+	return r
+}
+
 // AsPredicate 将会返回一个 Predicate，RawExpr 将会作为这个 Predicate 的左边部分
-// eorm 将不会校验任何从 RawExpr 生成的 Predicate
+// 除了通过 Using 声明的字段，eorm 将不会校验任何从 RawExpr 生成的 Predicate
 func (r RawExpr) AsPredicate() Predicate {
 	return Predicate{
 		left: r,
 	}
 }
 
+// rangeExpr 是 BETWEEN 的右边部分，即 start AND end
+type rangeExpr struct {
+	start any
+	end   any
+}
+
+func (rangeExpr) expr() (string, error) {
+	return "", nil
+}
+
 type binaryExpr struct {
 	left  Expr
 	op    op
@@ -67,9 +88,12 @@ func (binaryExpr) expr() (string, error) {
 	return "", nil
 }
 
+// MathExpr 是算术表达式，val 可以是普通的值，也可以是列或者其它表达式
+// 作为子表达式的时候会加上括号，例如 C("Age").Add(1).Multi(2) 生成 (`age`+?)*?
 type MathExpr binaryExpr
 
-func (m MathExpr) Add(val interface{}) Expr {
+// Add generate an additive expression
+func (m MathExpr) Add(val interface{}) MathExpr {
 	return MathExpr{
 		left:  m,
 		op:    opAdd,
@@ -77,6 +101,16 @@ func (m MathExpr) Add(val interface{}) Expr {
 	}
 }
 
+// Sub generate a subtraction expression
+func (m MathExpr) Sub(val interface{}) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+// Multi generate a multiplication expression
 func (m MathExpr) Multi(val interface{}) MathExpr {
 	return MathExpr{
 		left:  m,
@@ -85,10 +119,97 @@ func (m MathExpr) Multi(val interface{}) MathExpr {
 	}
 }
 
+// Div generate a division expression
+func (m MathExpr) Div(val interface{}) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
+// As 指定别名，使表达式可以出现在 SELECT 列表中，
+// 例如 C("Age").Multi(100.0).Div(Sum("Age").Over()).As("pct")
+func (m MathExpr) As(alias string) Selectable {
+	return aliasedExpr{expr: m, alias: alias}
+}
+
+// EQ =
+func (m MathExpr) EQ(val interface{}) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opEQ,
+		right: valueOf(val),
+	}
+}
+
+// NEQ !=
+func (m MathExpr) NEQ(val interface{}) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opNEQ,
+		right: valueOf(val),
+	}
+}
+
+// LT <
+func (m MathExpr) LT(val interface{}) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opLT,
+		right: valueOf(val),
+	}
+}
+
+// LTEQ <=
+func (m MathExpr) LTEQ(val interface{}) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opLTEQ,
+		right: valueOf(val),
+	}
+}
+
+// GT >
+func (m MathExpr) GT(val interface{}) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opGT,
+		right: valueOf(val),
+	}
+}
+
+// GTEQ >=
+func (m MathExpr) GTEQ(val interface{}) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opGTEQ,
+		right: valueOf(val),
+	}
+}
+
 func (MathExpr) expr() (string, error) {
 	return "", nil
 }
 
+// aliasedExpr 是带有别名的表达式
+type aliasedExpr struct {
+	expr  Expr
+	alias string
+}
+
+func (a aliasedExpr) selectedAlias() string {
+	return a.alias
+}
+
+func (aliasedExpr) selectedTable() TableReference {
+	return nil
+}
+
+func (aliasedExpr) fieldName() string {
+	return ""
+}
+
 func valueOf(val interface{}) Expr {
 	switch v := val.(type) {
 	case Expr:
@@ -98,6 +219,29 @@ func valueOf(val interface{}) Expr {
 	}
 }
 
+// ParamExpr 代表一个参数
+type ParamExpr struct {
+	val any
+	typ string
+}
+
+// Param 将 val 作为参数，通常和 Cast 一起使用
+func Param(val any) ParamExpr {
+	return ParamExpr{val: val}
+}
+
+// Cast 显式指定参数的类型，例如在 PostgreSQL 中
+// C("CreateTime").GT(Param(ts).Cast("timestamptz")) 会生成 "create_time">$1::timestamptz
+// 在不支持该写法的方言（例如 MySQL）中，类型会被忽略
+func (p ParamExpr) Cast(typ string) ParamExpr {
+	p.typ = typ
+	return p
+}
+
+func (ParamExpr) expr() (string, error) {
+	return "", nil
+}
+
 type SubqueryExpr struct {
 	s Subquery
 	// 謂詞： ALL、ANY、SOME
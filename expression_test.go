@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,6 +45,190 @@ func TestRawExpr_AsPredicate(t *testing.T) {
 			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE (`id`<?) OR (`age`<?);",
 			wantArgs: []interface{}{12, 18},
 		},
+		{
+			// 参数按照出现的顺序排列
+			name: "mixed with predicates",
+			builder: NewSelector[TestModel](db).Where(C("Age").GT(18),
+				Raw("JSON_EXTRACT(`first_name`, '$.x') = ?", 5).AsPredicate(), C("Id").LT(100)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE ((`age`>?) AND (JSON_EXTRACT(`first_name`, '$.x') = ?)) AND (`id`<?);",
+			wantArgs: []interface{}{18, 5, 100},
+		},
+		{
+			name: "multiple args",
+			builder: NewSelector[TestModel](db).Where(Raw("`age` BETWEEN ? AND ?", 18, 30).AsPredicate().
+				And(C("FirstName").EQ("Tom"))),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE (`age` BETWEEN ? AND ?) AND (`first_name`=?);",
+			wantArgs: []interface{}{18, 30, "Tom"},
+		},
+		{
+			name:     "not",
+			builder:  NewSelector[TestModel](db).Where(Not(Raw("`id`<?", 12).AsPredicate())),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE NOT (`id`<?);",
+			wantArgs: []interface{}{12},
+		},
+		{
+			name:     "as value",
+			builder:  NewSelector[TestModel](db).Where(C("Age").GT(Raw("? + ?", 1, 2))),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>? + ?;",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "update",
+			builder: NewUpdater[TestModel](db).Set(Assign("Age", 18)).
+				Where(C("Id").EQ(1), Raw("`first_name` LIKE ?", "Tom%").AsPredicate()),
+			wantSql:  "UPDATE `test_model` SET `age`=? WHERE (`id`=?) AND (`first_name` LIKE ?);",
+			wantArgs: []interface{}{18, 1, "Tom%"},
+		},
+		{
+			name:     "delete",
+			builder:  NewDeleter[TestModel](db).Where(Raw("`id` IN (?,?)", 1, 2).AsPredicate()),
+			wantSql:  "DELETE FROM `test_model` WHERE `id` IN (?,?);",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "using valid fields",
+			builder: NewSelector[TestModel](db).Where(RawPredicate("`age`>? AND `id`<?", 18, 100).
+				Using("Age", "Id")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>? AND `id`<?;",
+			wantArgs: []interface{}{18, 100},
+		},
+		{
+			name: "using invalid field",
+			builder: NewSelector[TestModel](db).Where(C("Id").EQ(1),
+				RawPredicate("`invalid`>?", 18).Using("Age", "Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "using invalid field in select",
+			builder: NewSelector[TestModel](db).Select(Raw("COUNT(DISTINCT `invalid`)").Using("Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name: "using in update",
+			builder: NewUpdater[TestModel](db).Set(Assign("Age", 18)).
+				Where(RawPredicate("`first_name` LIKE ?", "Tom%").Using("FirstName")),
+			wantSql:  "UPDATE `test_model` SET `age`=? WHERE `first_name` LIKE ?;",
+			wantArgs: []interface{}{18, "Tom%"},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestParamExpr_Cast(t *testing.T) {
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "postgres",
+			builder:  NewSelector[TestCombinedModel](pg).Select(C("Id")).Where(C("CreateTime").GT(Param(uint64(1000)).Cast("timestamptz"))),
+			wantSql:  `SELECT "id" FROM "test_combined_model" WHERE "create_time">$1::timestamptz;`,
+			wantArgs: []interface{}{uint64(1000)},
+		},
+		{
+			name: "postgres multiple",
+			builder: NewSelector[TestModel](pg).Select(C("Id")).
+				Where(C("Age").GT(Param(18).Cast("int"))).Limit(10),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "age">$1::int LIMIT $2;`,
+			wantArgs: []interface{}{18, 10},
+		},
+		{
+			name:     "postgres no cast",
+			builder:  NewSelector[TestModel](pg).Select(C("Id")).Where(C("Age").GT(Param(18))),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "age">$1;`,
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:     "mysql drop cast",
+			builder:  NewSelector[TestCombinedModel](memoryDB()).Select(C("Id")).Where(C("CreateTime").GT(Param(uint64(1000)).Cast("timestamptz"))),
+			wantSql:  "SELECT `id` FROM `test_combined_model` WHERE `create_time`>?;",
+			wantArgs: []interface{}{uint64(1000)},
+		},
+		{
+			name: "postgres raw and in",
+			builder: NewSelector[TestModel](pg).Select(C("Id")).
+				Where(C("Id").In(1, 2), Raw(`"first_name"->>'?x' = ?`, "Tom").AsPredicate()),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE ("id" IN ($1,$2)) AND ("first_name"->>'?x' = $3);`,
+			wantArgs: []interface{}{1, 2, "Tom"},
+		},
+		{
+			name: "postgres subquery",
+			builder: NewSelector[TestModel](pg).Select(C("Id")).
+				Where(C("Age").GT(18), C("Id").In(NewSelector[TestModel](pg).Select(C("Id")).
+					Where(C("Age").LT(30)).AsSubquery("sub"))),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE ("age">$1) AND ("id" IN (SELECT "id" FROM "test_model" WHERE "age"<$2));`,
+			wantArgs: []interface{}{18, 30},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestMathExpr(t *testing.T) {
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "select add",
+			builder:  NewSelector[TestModel](db).Select(C("Id"), C("Age").Add(1).As("next_age")),
+			wantSql:  "SELECT `id`,`age`+? AS `next_age` FROM `test_model`;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "select sub column",
+			builder:  NewSelector[TestModel](db).Select(C("Age").Sub(C("Id")).As("diff")),
+			wantSql:  "SELECT `age`-`id` AS `diff` FROM `test_model`;",
+			wantArgs: nil,
+		},
+		{
+			name:     "where multi",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").Multi(2).GT(40)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (`age`*?)>?;",
+			wantArgs: []interface{}{2, 40},
+		},
+		{
+			name:     "where div",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").Div(C("Id")).LTEQ(3)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (`age`/`id`)<=?;",
+			wantArgs: []interface{}{3},
+		},
+		{
+			name:     "nested",
+			builder:  NewSelector[TestModel](db).Select(C("Age").Add(1).Multi(C("Id").Sub(2)).As("v")),
+			wantSql:  "SELECT (`age`+?)*(`id`-?) AS `v` FROM `test_model`;",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name:     "nested in where",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").Sub(1).Div(2).EQ(C("Id"))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE ((`age`-?)/?)=`id`;",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name:    "invalid column",
+			builder: NewSelector[TestModel](db).Select(C("Invalid").Add(1).As("v")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
 	}
 
 	for _, tc := range testCases {
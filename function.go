@@ -0,0 +1,197 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+// dateTruncFormats 是 MySQL 的 DATE_FORMAT 和 SQLite 的 strftime 中各个精度对应的格式
+// 两者的分钟和秒的占位符不同
+var dateTruncFormats = map[string][2]string{
+	"year":   {"%Y-01-01", "%Y-01-01"},
+	"month":  {"%Y-%m-01", "%Y-%m-01"},
+	"day":    {"%Y-%m-%d", "%Y-%m-%d"},
+	"hour":   {"%Y-%m-%d %H:00:00", "%Y-%m-%d %H:00:00"},
+	"minute": {"%Y-%m-%d %H:%i:00", "%Y-%m-%d %H:%M:00"},
+	"second": {"%Y-%m-%d %H:%i:%s", "%Y-%m-%d %H:%M:%S"},
+}
+
+// DateTruncExpr 将时间截断到指定的精度，一般用于按照时间分桶统计
+type DateTruncExpr struct {
+	unit  string
+	field string
+	alias string
+}
+
+// DateTrunc 将 field 截断到 unit 指定的精度，unit 可以是 year、month、day、hour、minute 和 second
+// 在 PostgreSQL 上会被翻译为 DATE_TRUNC('day', col)，
+// 在 MySQL 上会被翻译为 DATE_FORMAT(col, '%Y-%m-%d')，在 SQLite 上会被翻译为 strftime('%Y-%m-%d', col)
+// 它可以用在 Select、GroupByExpr 和 ASCExpr、DESCExpr 中
+func DateTrunc(unit string, field string) DateTruncExpr {
+	return DateTruncExpr{
+		unit:  unit,
+		field: field,
+	}
+}
+
+// As specifies the alias
+func (d DateTruncExpr) As(alias string) Selectable {
+	d.alias = alias
+	return d
+}
+
+func (d DateTruncExpr) selectedAlias() string {
+	return d.alias
+}
+
+func (DateTruncExpr) selectedTable() TableReference {
+	return nil
+}
+
+func (d DateTruncExpr) fieldName() string {
+	return d.field
+}
+
+func (DateTruncExpr) expr() (string, error) {
+	return "", nil
+}
+
+// FuncExpr 代表 SQL 函数调用，例如 COALESCE(`last_name`,?)
+// 参数中的 Column 会按照模型校验并且转化为列名，其余的值会作为参数
+type FuncExpr struct {
+	fn    string
+	args  []Expr
+	alias string
+}
+
+// Coalesce 返回第一个不为 NULL 的参数，即 COALESCE(arg1, arg2, ...)
+// 列需要使用 C 来表示，例如 Coalesce(C("LastName"), C("FirstName"), "unknown")，
+// 其余的值会作为参数，它可以用在 Select 和 Where 中
+func Coalesce(args ...any) FuncExpr {
+	return newFuncExpr("COALESCE", args...)
+}
+
+// Concat 拼接字符串，即 CONCAT(arg1, arg2, ...)
+// 列需要使用 C 来表示，其余的值会作为参数，例如 Concat(C("FirstName"), " ", C("LastName"))
+// SQLite 不支持 CONCAT，会被翻译为 arg1||arg2||...
+func Concat(args ...any) FuncExpr {
+	return newFuncExpr("CONCAT", args...)
+}
+
+// Lower 将字符串转为小写，即 LOWER(arg)
+func Lower(arg any) FuncExpr {
+	return newFuncExpr("LOWER", arg)
+}
+
+// Upper 将字符串转为大写，即 UPPER(arg)
+func Upper(arg any) FuncExpr {
+	return newFuncExpr("UPPER", arg)
+}
+
+// Length 返回字符串的字符数，即 LENGTH(arg)
+// MySQL 的 LENGTH 返回的是字节数，所以在 MySQL 上会被翻译为 CHAR_LENGTH(arg)
+func Length(arg any) FuncExpr {
+	return newFuncExpr("LENGTH", arg)
+}
+
+// Greatest 返回参数中最大的值，即 GREATEST(arg1, arg2, ...)
+// SQLite 不支持 GREATEST，会被翻译为多参数的 MAX(arg1, arg2, ...)
+func Greatest(args ...any) FuncExpr {
+	return newFuncExpr("GREATEST", args...)
+}
+
+func newFuncExpr(fn string, args ...any) FuncExpr {
+	exprs := make([]Expr, 0, len(args))
+	for _, arg := range args {
+		exprs = append(exprs, valueOf(arg))
+	}
+	return FuncExpr{
+		fn:   fn,
+		args: exprs,
+	}
+}
+
+// As specifies the alias
+func (f FuncExpr) As(alias string) Selectable {
+	f.alias = alias
+	return f
+}
+
+func (f FuncExpr) selectedAlias() string {
+	return f.alias
+}
+
+func (FuncExpr) selectedTable() TableReference {
+	return nil
+}
+
+func (FuncExpr) fieldName() string {
+	return ""
+}
+
+func (FuncExpr) expr() (string, error) {
+	return "", nil
+}
+
+// EQ =
+func (f FuncExpr) EQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opEQ,
+		right: valueOf(val),
+	}
+}
+
+// NEQ !=
+func (f FuncExpr) NEQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opNEQ,
+		right: valueOf(val),
+	}
+}
+
+// LT <
+func (f FuncExpr) LT(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opLT,
+		right: valueOf(val),
+	}
+}
+
+// LTEQ <=
+func (f FuncExpr) LTEQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opLTEQ,
+		right: valueOf(val),
+	}
+}
+
+// GT >
+func (f FuncExpr) GT(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opGT,
+		right: valueOf(val),
+	}
+}
+
+// GTEQ >=
+func (f FuncExpr) GTEQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opGTEQ,
+		right: valueOf(val),
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateTrunc(t *testing.T) {
+	type Order struct {
+		Id         int64
+		Amount     int64
+		CreateTime time.Time
+	}
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name: "mysql daily",
+			builder: NewSelector[Order](mysqlDB).
+				Select(DateTrunc("day", "CreateTime").As("day"), Sum("Amount")).
+				GroupByExpr(DateTrunc("day", "CreateTime")).
+				OrderBy(ASCExpr(DateTrunc("day", "CreateTime"))),
+			wantSql: "SELECT DATE_FORMAT(`create_time`,'%Y-%m-%d') AS `day`,SUM(`amount`) FROM `order` " +
+				"GROUP BY DATE_FORMAT(`create_time`,'%Y-%m-%d') ORDER BY DATE_FORMAT(`create_time`,'%Y-%m-%d') ASC;",
+		},
+		{
+			name: "mysql group by column and minute",
+			builder: NewSelector[Order](mysqlDB).Select(C("Id"), DateTrunc("minute", "CreateTime")).
+				GroupBy("Id").GroupByExpr(DateTrunc("minute", "CreateTime")),
+			wantSql: "SELECT `id`,DATE_FORMAT(`create_time`,'%Y-%m-%d %H:%i:00') FROM `order` " +
+				"GROUP BY `id`,DATE_FORMAT(`create_time`,'%Y-%m-%d %H:%i:00');",
+		},
+		{
+			name: "postgres daily",
+			builder: NewSelector[Order](pg).
+				Select(DateTrunc("day", "CreateTime").As("day"), Sum("Amount")).
+				Where(C("Amount").GT(100)).
+				GroupByExpr(DateTrunc("day", "CreateTime")).
+				OrderBy(DESCExpr(DateTrunc("day", "CreateTime"))),
+			wantSql: `SELECT DATE_TRUNC('day',"create_time") AS "day",SUM("amount") FROM "order" WHERE "amount">$1 ` +
+				`GROUP BY DATE_TRUNC('day',"create_time") ORDER BY DATE_TRUNC('day',"create_time") DESC;`,
+			wantArgs: []interface{}{100},
+		},
+		{
+			name: "sqlite monthly",
+			builder: NewSelector[Order](memoryDB()).Select(DateTrunc("month", "CreateTime")).
+				GroupByExpr(DateTrunc("month", "CreateTime")),
+			wantSql: "SELECT strftime('%Y-%m-01',`create_time`) FROM `order` GROUP BY strftime('%Y-%m-01',`create_time`);",
+		},
+		{
+			name:    "invalid unit",
+			builder: NewSelector[Order](pg).Select(DateTrunc("week'--", "CreateTime")),
+			wantErr: errs.NewUnsupportedDateTruncUnitError("week'--"),
+		},
+		{
+			name:    "invalid field",
+			builder: NewSelector[Order](mysqlDB).Select(C("Id")).GroupByExpr(DateTrunc("day", "Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	pg := postgresDB()
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "select",
+			builder:  NewSelector[TestModel](db).Select(C("Id"), Coalesce(C("LastName"), "unknown").As("name")),
+			wantSql:  "SELECT `id`,COALESCE(`last_name`,?) AS `name` FROM `test_model`;",
+			wantArgs: []interface{}{"unknown"},
+		},
+		{
+			name: "where",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Where(C("Age").GT(18), Coalesce(C("LastName"), C("FirstName"), "").EQ("Tom")),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (`age`>?) AND (COALESCE(`last_name`,`first_name`,?)=?);",
+			wantArgs: []interface{}{18, "", "Tom"},
+		},
+		{
+			name: "postgres",
+			builder: NewSelector[TestModel](pg).Select(Coalesce(C("LastName"), "unknown")).
+				Where(Coalesce(C("Age"), 0).GTEQ(18)),
+			wantSql:  `SELECT COALESCE("last_name",$1) FROM "test_model" WHERE COALESCE("age",$2)>=$3;`,
+			wantArgs: []interface{}{"unknown", 0, 18},
+		},
+		{
+			name: "qualified column",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				return NewSelector[TestModel](db).Select(Coalesce(t1.C("LastName"), t1.C("FirstName"))).From(t1)
+			}(),
+			wantSql: "SELECT COALESCE(`t1`.`last_name`,`t1`.`first_name`) FROM `test_model` AS `t1`;",
+		},
+		{
+			name:    "invalid column",
+			builder: NewSelector[TestModel](db).Select(Coalesce(C("Invalid"), "unknown")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestStringFunc(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "mysql concat",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id"), Concat(C("FirstName"), " ", C("LastName")).As("name")),
+			wantSql:  "SELECT `id`,CONCAT(`first_name`,?,`last_name`) AS `name` FROM `test_model`;",
+			wantArgs: []interface{}{" "},
+		},
+		{
+			name:     "postgres concat",
+			builder:  NewSelector[TestModel](pg).Select(C("Id")).Where(Concat(C("FirstName"), " ", C("LastName")).EQ("Tom Jerry")),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE CONCAT("first_name",$1,"last_name")=$2;`,
+			wantArgs: []interface{}{" ", "Tom Jerry"},
+		},
+		{
+			name:     "sqlite concat",
+			builder:  NewSelector[TestModel](db).Select(Concat(C("FirstName"), " ", C("LastName")).As("name")),
+			wantSql:  "SELECT (`first_name`||?||`last_name`) AS `name` FROM `test_model`;",
+			wantArgs: []interface{}{" "},
+		},
+		{
+			name:     "lower",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(Lower(C("FirstName")).EQ("tom")),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE LOWER(`first_name`)=?;",
+			wantArgs: []interface{}{"tom"},
+		},
+		{
+			name:    "upper",
+			builder: NewSelector[TestModel](pg).Select(Upper(C("LastName")).As("last")),
+			wantSql: `SELECT UPPER("last_name") AS "last" FROM "test_model";`,
+		},
+		{
+			name:     "nested",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(Lower(Concat(C("FirstName"), C("LastName"))).EQ("tomjerry")),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE LOWER((`first_name`||`last_name`))=?;",
+			wantArgs: []interface{}{"tomjerry"},
+		},
+		{
+			name:     "mysql length",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(Length(C("FirstName")).GT(3)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE CHAR_LENGTH(`first_name`)>?;",
+			wantArgs: []interface{}{3},
+		},
+		{
+			name:    "postgres length",
+			builder: NewSelector[TestModel](pg).Select(Length(C("FirstName")).As("len")),
+			wantSql: `SELECT LENGTH("first_name") AS "len" FROM "test_model";`,
+		},
+		{
+			name:    "invalid column",
+			builder: NewSelector[TestModel](db).Select(Concat(C("FirstName"), C("Invalid"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
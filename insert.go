@@ -30,6 +30,61 @@ type Inserter[T any] struct {
 	session
 	columns []string
 	values  []*T
+	// returning 为 true 的时候会构造 RETURNING 部分
+	returning     bool
+	returningCols []string
+	upsert        *Upsert
+	chunkSize     int
+}
+
+// Upsert 代表 INSERT 冲突的时候执行的更新
+type Upsert struct {
+	conflictColumns []string
+	assigns         []Assignable
+}
+
+// UpsertBuilder 用于构造 Upsert
+type UpsertBuilder[T any] struct {
+	i               *Inserter[T]
+	conflictColumns []string
+}
+
+// OnConflict 开始构造冲突时的更新语句，cs 是冲突的列，必须是模型的字段名
+// 在 MySQL 中会生成 ON DUPLICATE KEY UPDATE，此时 cs 会被忽略，冲突由唯一索引决定；
+// 在 PostgreSQL 和 SQLite 中会生成 ON CONFLICT (cs) DO UPDATE SET，此时 cs 不能为空
+func (i *Inserter[T]) OnConflict(cs ...string) *UpsertBuilder[T] {
+	return &UpsertBuilder[T]{
+		i:               i,
+		conflictColumns: cs,
+	}
+}
+
+// Update 指定冲突时更新的列
+// 如果传入的是 C 或者 Columns，那么会使用插入的值进行更新，
+// 在 MySQL 中是 col=VALUES(col)，在 PostgreSQL 和 SQLite 中是 col=EXCLUDED.col；
+// 如果传入的是 Assign，那么会使用指定的值或者表达式进行更新
+func (u *UpsertBuilder[T]) Update(assigns ...Assignable) *Inserter[T] {
+	u.i.upsert = &Upsert{
+		conflictColumns: u.conflictColumns,
+		assigns:         assigns,
+	}
+	return u.i
+}
+
+// ExcludedExpr 代表 upsert 中原本要插入的值
+type ExcludedExpr struct {
+	field string
+}
+
+// Excluded 引用 upsert 中 field 原本要插入的值，只能用在 OnConflict().Update 的 Assign 中，
+// 例如保留较大值：Assign("Score", Greatest(C("Score"), Excluded("Score")))
+// 在 MySQL 中是 VALUES(col)，在 PostgreSQL 和 SQLite 中是 EXCLUDED.col
+func Excluded(field string) ExcludedExpr {
+	return ExcludedExpr{field: field}
+}
+
+func (ExcludedExpr) expr() (string, error) {
+	return "", nil
 }
 
 // NewInserter 开始构建一个 INSERT 查询
@@ -58,7 +113,10 @@ func (i *Inserter[T]) Build() (*Query, error) {
 	if err != nil {
 		return &Query{}, err
 	}
-	i.quote(i.meta.TableName)
+	if i.meta.IsView {
+		return &Query{}, errs.NewReadOnlyViewError(i.meta.TableName)
+	}
+	i.quoteTable(i.meta.TableName)
 	i.writeString("(")
 	fields, err := i.buildColumns()
 	if err != nil {
@@ -66,6 +124,7 @@ func (i *Inserter[T]) Build() (*Query, error) {
 	}
 	i.writeString(")")
 	i.writeString(" VALUES")
+	now := i.clock()
 	for index, val := range i.values {
 		if index > 0 {
 			i.comma()
@@ -77,6 +136,13 @@ func (i *Inserter[T]) Build() (*Query, error) {
 			if err != nil {
 				return nil, err
 			}
+			// 用户没有设置创建时间和更新时间的时候自动填充
+			if (v.IsCreatedAt || v.IsUpdatedAt) && isZeroValue(fdVal) {
+				fdVal = timestampValue(v.Typ, now)
+			}
+			if fdVal, err = columnValue(v, fdVal); err != nil {
+				return nil, err
+			}
 			i.parameter(fdVal)
 			if j != len(fields)-1 {
 				i.comma()
@@ -84,6 +150,19 @@ func (i *Inserter[T]) Build() (*Query, error) {
 		}
 		i.writeString(")")
 	}
+	if i.upsert != nil {
+		if err = i.buildUpsert(); err != nil {
+			return &Query{}, err
+		}
+	}
+	if i.returning {
+		if err = i.buildReturning(i.returningCols); err != nil {
+			return &Query{}, err
+		}
+	}
+	if err = i.checkArgs(); err != nil {
+		return &Query{}, err
+	}
 	i.end()
 	return &Query{SQL: i.buffer.String(), Args: i.args}, nil
 }
@@ -104,8 +183,36 @@ func (i *Inserter[T]) Values(values ...*T) *Inserter[T] {
 	return i
 }
 
+// Returning 指定 RETURNING 的列，不传入列的时候表示 RETURNING *
+// cs 必须是模型的字段名，只有支持 RETURNING 的方言才可以使用，
+// 例如 PostgreSQL 和 SQLite，其它方言会在 Build 的时候返回错误
+// 之后可以使用 GetMulti 读取返回的数据
+func (i *Inserter[T]) Returning(cs ...string) *Inserter[T] {
+	i.returning = true
+	i.returningCols = cs
+	return i
+}
+
+// TablePrefix 参考 Selector.TablePrefix
+func (i *Inserter[T]) TablePrefix(prefix string) *Inserter[T] {
+	i.tablePrefix = prefix
+	return i
+}
+
+// ChunkSize 指定每一条 INSERT 语句最多插入多少行
+// 如果 Values 超过了 size 行，那么 Exec 会将它们拆分为多条 INSERT 语句，并且在同一个事务中执行，
+// 任何一条语句出错都会回滚整个事务。如果当前已经在事务中，那么会直接使用该事务，由用户决定提交还是回滚
+// size <= 0 表示不拆分
+func (i *Inserter[T]) ChunkSize(size int) *Inserter[T] {
+	i.chunkSize = size
+	return i
+}
+
 // Exec 发起查询
 func (i *Inserter[T]) Exec(ctx context.Context) Result {
+	if i.chunkSize > 0 && len(i.values) > i.chunkSize {
+		return i.execChunks(ctx)
+	}
 	query, err := i.Build()
 	if err != nil {
 		return Result{err: err}
@@ -113,14 +220,189 @@ func (i *Inserter[T]) Exec(ctx context.Context) Result {
 	return newQuerier[T](i.session, query, i.meta, INSERT).Exec(ctx)
 }
 
+// execChunks 按照 chunkSize 拆分 Values，在同一个事务中执行多条 INSERT 语句
+func (i *Inserter[T]) execChunks(ctx context.Context) Result {
+	// 不会调用 i.Build，所以需要在这里归还 buffer
+	bytebufferpool.Put(i.buffer)
+	var tx *Tx
+	var err error
+	sess := i.session
+	switch s := i.session.(type) {
+	case *DB:
+		tx, err = s.BeginTx(ctx, nil)
+	case *Conn:
+		tx, err = s.BeginTx(ctx, nil)
+	}
+	if err != nil {
+		return Result{err: err}
+	}
+	if tx != nil {
+		sess = tx
+	}
+	res := chunksResult{}
+	for start := 0; start < len(i.values); start += i.chunkSize {
+		end := start + i.chunkSize
+		if end > len(i.values) {
+			end = len(i.values)
+		}
+		chunk := NewInserter[T](sess).Columns(i.columns...).Values(i.values[start:end]...)
+		chunk.upsert = i.upsert
+		chunk.tablePrefix = i.tablePrefix
+		r := chunk.Exec(ctx)
+		if err = r.Err(); err == nil {
+			err = res.add(r)
+		}
+		if err != nil {
+			if tx != nil {
+				_ = tx.Rollback()
+			}
+			return Result{err: err}
+		}
+	}
+	if tx != nil {
+		if err = tx.Commit(); err != nil {
+			return Result{err: err}
+		}
+	}
+	return Result{res: res}
+}
+
+// chunksResult 汇总多条 INSERT 语句的执行结果
+// LastInsertId 返回的是最后一条语句的结果
+type chunksResult struct {
+	lastInsertId int64
+	rowsAffected int64
+}
+
+func (c *chunksResult) add(r Result) error {
+	affected, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	c.rowsAffected += affected
+	// 部分驱动不支持 LastInsertId，忽略错误
+	c.lastInsertId, _ = r.LastInsertId()
+	return nil
+}
+
+func (c chunksResult) LastInsertId() (int64, error) {
+	return c.lastInsertId, nil
+}
+
+func (c chunksResult) RowsAffected() (int64, error) {
+	return c.rowsAffected, nil
+}
+
+// GetMulti 执行带有 RETURNING 的插入语句，并且读取返回的数据
+func (i *Inserter[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	query, err := i.Build()
+	if err != nil {
+		return nil, err
+	}
+	return newQuerier[T](i.session, query, i.meta, INSERT).GetMulti(ctx)
+}
+
+func (i *Inserter[T]) buildUpsert() error {
+	if len(i.upsert.assigns) == 0 {
+		return errs.NewValueNotSetError()
+	}
+	features := i.features()
+	if features.OnDuplicateKey {
+		i.writeString(" ON DUPLICATE KEY UPDATE ")
+	} else {
+		if len(i.upsert.conflictColumns) == 0 {
+			return errs.ErrUpsertWithoutConflictColumns
+		}
+		i.writeString(" ON CONFLICT (")
+		for idx, c := range i.upsert.conflictColumns {
+			cMeta, err := fieldMeta(i.meta, c)
+			if err != nil {
+				return err
+			}
+			if idx > 0 {
+				i.comma()
+			}
+			i.quote(cMeta.ColumnName)
+		}
+		i.writeString(") DO UPDATE SET ")
+	}
+	for idx, assign := range i.upsert.assigns {
+		if idx > 0 {
+			i.comma()
+		}
+		switch a := assign.(type) {
+		case Column:
+			if err := i.buildUpsertColumn(a.name, features.OnDuplicateKey); err != nil {
+				return err
+			}
+		case columns:
+			for j, c := range a.cs {
+				if j > 0 {
+					i.comma()
+				}
+				if err := i.buildUpsertColumn(c, features.OnDuplicateKey); err != nil {
+					return err
+				}
+			}
+		case Assignment:
+			if col, ok := a.left.(Column); ok {
+				if _, err := i.writableColumn(col.name); err != nil {
+					return err
+				}
+			}
+			if err := i.buildUpsertAssignment(a, features.QualifyUpsertColumns); err != nil {
+				return err
+			}
+		default:
+			return errs.NewErrUnsupportedAssignableType(a)
+		}
+	}
+	return nil
+}
+
+// buildUpsertAssignment 构造冲突时的赋值语句
+// PostgreSQL 中右边的列如果不加上表名，会和 EXCLUDED 中的列产生歧义，所以需要加上表名
+func (i *Inserter[T]) buildUpsertAssignment(a Assignment, qualify bool) error {
+	if err := i.buildExpr(a.left); err != nil {
+		return err
+	}
+	i.writeString(a.op.text)
+	right, err := i.convertOperand(a.left, a.right)
+	if err != nil {
+		return err
+	}
+	i.qualifyColumns = qualify
+	err = i.buildSubExpr(right)
+	i.qualifyColumns = false
+	return err
+}
+
+// buildUpsertColumn 使用插入的值更新 field 对应的列
+func (i *Inserter[T]) buildUpsertColumn(field string, onDuplicateKey bool) error {
+	cMeta, err := i.writableColumn(field)
+	if err != nil {
+		return err
+	}
+	i.quote(cMeta.ColumnName)
+	if onDuplicateKey {
+		i.writeString("=VALUES(")
+		i.quote(cMeta.ColumnName)
+		i.writeByte(')')
+	} else {
+		i.writeString("=EXCLUDED.")
+		i.quote(cMeta.ColumnName)
+	}
+	return nil
+}
+
 func (i *Inserter[T]) buildColumns() ([]*model.ColumnMeta, error) {
 	cs := i.meta.Columns
 	if len(i.columns) != 0 {
 		cs = make([]*model.ColumnMeta, 0, len(i.columns))
 		for index, c := range i.columns {
-			v, isOk := i.meta.FieldMap[c]
-			if !isOk {
-				return cs, errs.NewInvalidFieldError(c)
+			v, err := i.writableColumn(c)
+			if err != nil {
+				return cs, err
 			}
 			i.quote(v.ColumnName)
 			if index != len(i.columns)-1 {
@@ -129,11 +411,17 @@ func (i *Inserter[T]) buildColumns() ([]*model.ColumnMeta, error) {
 			cs = append(cs, v)
 		}
 	} else {
-		for index, val := range i.meta.Columns {
-			i.quote(val.ColumnName)
-			if index != len(cs)-1 {
+		// 跳过数据库生成的列
+		cs = make([]*model.ColumnMeta, 0, len(i.meta.Columns))
+		for _, val := range i.meta.Columns {
+			if val.IsGenerated {
+				continue
+			}
+			if len(cs) > 0 {
 				i.comma()
 			}
+			i.quote(val.ColumnName)
+			cs = append(cs, val)
 		}
 	}
 	return cs, nil
@@ -15,11 +15,17 @@ package eorm
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInserter_Values(t *testing.T) {
@@ -46,6 +52,11 @@ func TestInserter_Values(t *testing.T) {
 			builder: NewInserter[User](db).Values(),
 			wantErr: errors.New("插入0行"),
 		},
+		{
+			name:    "view",
+			builder: NewInserter[TestModelView](db).Values(&TestModelView{Id: 12, FirstName: "Tom"}),
+			wantErr: errs.NewReadOnlyViewError("test_model_view"),
+		},
 		{
 			name:     "single example of values",
 			builder:  NewInserter[User](db).Values(u),
@@ -178,6 +189,238 @@ func TestInserter_ValuesForCombination(t *testing.T) {
 	}
 }
 
+func TestInserter_MaxParams(t *testing.T) {
+	type User struct {
+		Id        int64
+		FirstName string
+	}
+	db := memoryDB()
+	db.dialect = limitedDialect{Dialect: db.dialect, maxParams: 4}
+	testCases := []CommonTestCase{
+		{
+			name:     "under limit",
+			builder:  NewInserter[User](db).Values(&User{Id: 1, FirstName: "Tom"}, &User{Id: 2, FirstName: "Jerry"}),
+			wantSql:  "INSERT INTO `user`(`id`,`first_name`) VALUES(?,?),(?,?);",
+			wantArgs: []interface{}{int64(1), "Tom", int64(2), "Jerry"},
+		},
+		{
+			name: "exceed limit",
+			builder: NewInserter[User](db).Values(&User{Id: 1, FirstName: "Tom"},
+				&User{Id: 2, FirstName: "Jerry"}, &User{Id: 3, FirstName: "Kate"}),
+			wantErr: errs.NewTooManyParametersError("SQLite", 6, 4),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestInserter_Returning(t *testing.T) {
+	type User struct {
+		Id        int64
+		FirstName string
+	}
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "postgres returning id",
+			builder:  NewInserter[User](pg).Columns("FirstName").Values(&User{FirstName: "Tom"}).Returning("Id"),
+			wantSql:  `INSERT INTO "user"("first_name") VALUES($1) RETURNING "id";`,
+			wantArgs: []interface{}{"Tom"},
+		},
+		{
+			name: "postgres returning all",
+			builder: NewInserter[User](pg).Values(&User{Id: 1, FirstName: "Tom"},
+				&User{Id: 2, FirstName: "Jerry"}).Returning(),
+			wantSql:  `INSERT INTO "user"("id","first_name") VALUES($1,$2),($3,$4) RETURNING *;`,
+			wantArgs: []interface{}{int64(1), "Tom", int64(2), "Jerry"},
+		},
+		{
+			name:     "sqlite",
+			builder:  NewInserter[User](memoryDB()).Values(&User{Id: 1, FirstName: "Tom"}).Returning("Id", "FirstName"),
+			wantSql:  "INSERT INTO `user`(`id`,`first_name`) VALUES(?,?) RETURNING `id`,`first_name`;",
+			wantArgs: []interface{}{int64(1), "Tom"},
+		},
+		{
+			name:    "invalid column",
+			builder: NewInserter[User](pg).Values(&User{Id: 1, FirstName: "Tom"}).Returning("Invalid"),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "mysql",
+			builder: NewInserter[User](mysqlDB).Values(&User{Id: 1, FirstName: "Tom"}).Returning("Id"),
+			wantErr: errs.NewUnsupportedFeatureError("MySQL", "RETURNING"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestInserter_OnConflict(t *testing.T) {
+	type User struct {
+		Id        int64
+		Email     string
+		FirstName string
+		Age       int
+	}
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	u := &User{Id: 1, Email: "tom@example.com", FirstName: "Tom", Age: 18}
+	testCases := []CommonTestCase{
+		{
+			name: "mysql columns",
+			builder: NewInserter[User](mysqlDB).Values(u).
+				OnConflict("Email").Update(Columns("FirstName", "Age")),
+			wantSql:  "INSERT INTO `user`(`id`,`email`,`first_name`,`age`) VALUES(?,?,?,?) ON DUPLICATE KEY UPDATE `first_name`=VALUES(`first_name`),`age`=VALUES(`age`);",
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18},
+		},
+		{
+			name: "mysql assignment",
+			builder: NewInserter[User](mysqlDB).Values(u).
+				OnConflict().Update(C("FirstName"), Assign("Age", C("Age").Add(1))),
+			wantSql:  "INSERT INTO `user`(`id`,`email`,`first_name`,`age`) VALUES(?,?,?,?) ON DUPLICATE KEY UPDATE `first_name`=VALUES(`first_name`),`age`=(`age`+?);",
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18, 1},
+		},
+		{
+			name: "postgres columns",
+			builder: NewInserter[User](pg).Values(u).
+				OnConflict("Email").Update(Columns("FirstName", "Age")),
+			wantSql:  `INSERT INTO "user"("id","email","first_name","age") VALUES($1,$2,$3,$4) ON CONFLICT ("email") DO UPDATE SET "first_name"=EXCLUDED."first_name","age"=EXCLUDED."age";`,
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18},
+		},
+		{
+			name: "postgres assignment",
+			builder: NewInserter[User](pg).Values(u).
+				OnConflict("Id", "Email").Update(C("FirstName"), Assign("Age", 20)),
+			wantSql:  `INSERT INTO "user"("id","email","first_name","age") VALUES($1,$2,$3,$4) ON CONFLICT ("id","email") DO UPDATE SET "first_name"=EXCLUDED."first_name","age"=$5;`,
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18, 20},
+		},
+		{
+			name: "sqlite",
+			builder: NewInserter[User](memoryDB()).Values(u).
+				OnConflict("Email").Update(C("Age")),
+			wantSql:  "INSERT INTO `user`(`id`,`email`,`first_name`,`age`) VALUES(?,?,?,?) ON CONFLICT (`email`) DO UPDATE SET `age`=EXCLUDED.`age`;",
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18},
+		},
+		{
+			name: "postgres keep max",
+			builder: NewInserter[User](pg).Values(u).
+				OnConflict("Id").Update(Assign("Age", Greatest(C("Age"), Excluded("Age")))),
+			wantSql:  `INSERT INTO "user"("id","email","first_name","age") VALUES($1,$2,$3,$4) ON CONFLICT ("id") DO UPDATE SET "age"=GREATEST("user"."age",EXCLUDED."age");`,
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18},
+		},
+		{
+			name: "postgres qualified expression",
+			builder: NewInserter[User](pg).Values(u).
+				OnConflict("Id").Update(Assign("Age", C("Age").Add(Excluded("Age")))),
+			wantSql:  `INSERT INTO "user"("id","email","first_name","age") VALUES($1,$2,$3,$4) ON CONFLICT ("id") DO UPDATE SET "age"=("user"."age"+EXCLUDED."age");`,
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18},
+		},
+		{
+			name: "mysql keep max",
+			builder: NewInserter[User](mysqlDB).Values(u).
+				OnConflict().Update(Assign("Age", Greatest(C("Age"), Excluded("Age")))),
+			wantSql:  "INSERT INTO `user`(`id`,`email`,`first_name`,`age`) VALUES(?,?,?,?) ON DUPLICATE KEY UPDATE `age`=GREATEST(`age`,VALUES(`age`));",
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18},
+		},
+		{
+			name: "sqlite keep max",
+			builder: NewInserter[User](memoryDB()).Values(u).
+				OnConflict("Id").Update(Assign("Age", Greatest(C("Age"), Excluded("Age")))),
+			wantSql:  "INSERT INTO `user`(`id`,`email`,`first_name`,`age`) VALUES(?,?,?,?) ON CONFLICT (`id`) DO UPDATE SET `age`=MAX(`age`,EXCLUDED.`age`);",
+			wantArgs: []interface{}{int64(1), "tom@example.com", "Tom", 18},
+		},
+		{
+			name: "invalid excluded column",
+			builder: NewInserter[User](pg).Values(u).
+				OnConflict("Id").Update(Assign("Age", Greatest(C("Age"), Excluded("Invalid")))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "postgres without conflict columns",
+			builder: NewInserter[User](pg).Values(u).OnConflict().Update(C("Age")),
+			wantErr: errs.ErrUpsertWithoutConflictColumns,
+		},
+		{
+			name:    "invalid conflict column",
+			builder: NewInserter[User](pg).Values(u).OnConflict("Invalid").Update(C("Age")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid update column",
+			builder: NewInserter[User](mysqlDB).Values(u).OnConflict().Update(Columns("Age", "Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "no update columns",
+			builder: NewInserter[User](mysqlDB).Values(u).OnConflict().Update(),
+			wantErr: errs.NewValueNotSetError(),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestInserter_GetMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("postgres", mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`INSERT INTO "test_model"("first_name","age") VALUES($1,$2),($3,$4) RETURNING "id";`).
+		WithArgs("Tom", int8(18), "Jerry", int8(20)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	res, err := NewInserter[TestModel](db).Columns("FirstName", "Age").
+		Values(&TestModel{FirstName: "Tom", Age: 18}, &TestModel{FirstName: "Jerry", Age: 20}).
+		Returning("Id").GetMulti(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*TestModel{{Id: 1}, {Id: 2}}, res)
+
+	_, err = NewInserter[TestModel](db).Values().Returning("Id").GetMulti(context.Background())
+	assert.Equal(t, errors.New("插入0行"), err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestInserter_Exec(t *testing.T) {
 	orm := memoryDB()
 	testCases := []struct {
@@ -216,6 +459,112 @@ func TestInserter_Exec(t *testing.T) {
 	}
 }
 
+func TestInserter_ChunkSize(t *testing.T) {
+	type User struct {
+		Id        int64
+		FirstName string
+	}
+	users := make([]*User, 0, 5000)
+	for j := 0; j < 5000; j++ {
+		users = append(users, &User{Id: int64(j + 1), FirstName: fmt.Sprintf("user%d", j)})
+	}
+
+	testCases := []struct {
+		name         string
+		mock         func(mock sqlmock.Sqlmock)
+		wantErr      error
+		wantAffected int64
+	}{
+		{
+			name: "commit",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				for j := 0; j < 10; j++ {
+					mock.ExpectExec("INSERT INTO `user`").
+						WillReturnResult(sqlmock.NewResult(int64(500*(j+1)), 500))
+				}
+				mock.ExpectCommit()
+			},
+			wantAffected: 5000,
+		},
+		{
+			name: "rollback",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				for j := 0; j < 2; j++ {
+					mock.ExpectExec("INSERT INTO `user`").
+						WillReturnResult(sqlmock.NewResult(int64(500*(j+1)), 500))
+				}
+				mock.ExpectExec("INSERT INTO `user`").WillReturnError(errors.New("mock error"))
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name: "begin error",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(errors.New("begin error"))
+			},
+			wantErr: errors.New("begin error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB)
+			require.NoError(t, err)
+			tc.mock(mock)
+
+			res := NewInserter[User](db).Values(users...).ChunkSize(500).Exec(context.Background())
+			assert.Equal(t, tc.wantErr, res.Err())
+			assert.NoError(t, mock.ExpectationsWereMet())
+			if res.Err() != nil {
+				return
+			}
+			affected, err := res.RowsAffected()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantAffected, affected)
+		})
+	}
+}
+
+func TestInserter_ChunkSizeInTx(t *testing.T) {
+	type User struct {
+		Id        int64
+		FirstName string
+	}
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `user`\\(`id`,`first_name`\\) VALUES\\(\\?,\\?\\),\\(\\?,\\?\\);").
+		WithArgs(int64(1), "Tom", int64(2), "Jerry").WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectExec("INSERT INTO `user`\\(`id`,`first_name`\\) VALUES\\(\\?,\\?\\);").
+		WithArgs(int64(3), "Kate").WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	// 已经在事务中的时候，直接使用该事务，不会再开启新的事务
+	res := NewInserter[User](tx).ChunkSize(2).Values(&User{Id: 1, FirstName: "Tom"},
+		&User{Id: 2, FirstName: "Jerry"}, &User{Id: 3, FirstName: "Kate"}).Exec(context.Background())
+	require.NoError(t, res.Err())
+	affected, err := res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), affected)
+	id, err := res.LastInsertId()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), id)
+	require.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func ExampleInserter_Build() {
 	db := memoryDB()
 	query, _ := NewInserter[TestModel](db).Values(&TestModel{
@@ -280,3 +629,183 @@ func ExampleNewInserter() {
 	// Output:
 	// SQL: INSERT INTO `test_model`(`id`,`first_name`,`age`,`last_name`) VALUES(?,?,?,?);
 }
+
+func TestInserter_GeneratedColumn(t *testing.T) {
+	type Product struct {
+		Id       int64
+		Price    int64
+		Quantity int64
+		Total    int64 `eorm:"generated"`
+	}
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	p := &Product{Id: 1, Price: 10, Quantity: 3, Total: 30}
+	testCases := []CommonTestCase{
+		{
+			name:     "all columns",
+			builder:  NewInserter[Product](mysqlDB).Values(p),
+			wantSql:  "INSERT INTO `product`(`id`,`price`,`quantity`) VALUES(?,?,?);",
+			wantArgs: []interface{}{int64(1), int64(10), int64(3)},
+		},
+		{
+			name:    "specify generated column",
+			builder: NewInserter[Product](mysqlDB).Columns("Id", "Total").Values(p),
+			wantErr: errs.NewGeneratedColumnError("Total"),
+		},
+		{
+			name:    "upsert generated column",
+			builder: NewInserter[Product](postgresDB()).Values(p).OnConflict("Id").Update(Columns("Price", "Total")),
+			wantErr: errs.NewGeneratedColumnError("Total"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestInserter_Timestamps(t *testing.T) {
+	type Article struct {
+		Id        int64
+		CreatedAt time.Time `eorm:"created_at"`
+		UpdatedAt int64     `eorm:"updated_at"`
+		CreateUx  uint64    `eorm:"created_at"`
+	}
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock, DBWithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name:     "zero values",
+			builder:  NewInserter[Article](mysqlDB).Values(&Article{Id: 1}),
+			wantSql:  "INSERT INTO `article`(`id`,`created_at`,`updated_at`,`create_ux`) VALUES(?,?,?,?);",
+			wantArgs: []interface{}{int64(1), now, now.Unix(), uint64(now.Unix())},
+		},
+		{
+			name:     "keep user values",
+			builder:  NewInserter[Article](mysqlDB).Values(&Article{Id: 1, CreatedAt: created, UpdatedAt: 10, CreateUx: 20}),
+			wantSql:  "INSERT INTO `article`(`id`,`created_at`,`updated_at`,`create_ux`) VALUES(?,?,?,?);",
+			wantArgs: []interface{}{int64(1), created, int64(10), uint64(20)},
+		},
+		{
+			name:     "multiple values",
+			builder:  NewInserter[Article](mysqlDB).Columns("Id", "CreatedAt").Values(&Article{Id: 1}, &Article{Id: 2, CreatedAt: created}),
+			wantSql:  "INSERT INTO `article`(`id`,`created_at`) VALUES(?,?),(?,?);",
+			wantArgs: []interface{}{int64(1), now, int64(2), created},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestInserter_IgnoreTag(t *testing.T) {
+	type Order struct {
+		Id    int64
+		Price int64
+		Total int64 `eorm:"-"`
+	}
+	db := memoryDB()
+	o := &Order{Id: 1, Price: 10, Total: 30}
+	testCases := []CommonTestCase{
+		{
+			name:     "all columns",
+			builder:  NewInserter[Order](db).Values(o),
+			wantSql:  "INSERT INTO `order`(`id`,`price`) VALUES(?,?);",
+			wantArgs: []interface{}{int64(1), int64(10)},
+		},
+		{
+			name:    "specify ignored field",
+			builder: NewInserter[Order](db).Columns("Id", "Total").Values(o),
+			wantErr: errs.NewInvalidFieldError("Total"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestInserter_JSON(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Profile struct {
+		Id      int64
+		Meta    map[string]any `eorm:"json"`
+		Tags    []string       `eorm:"json"`
+		Address *Address       `eorm:"json"`
+	}
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name: "map struct and slice",
+			builder: NewInserter[Profile](db).Values(&Profile{
+				Id:      1,
+				Meta:    map[string]any{"age": 18},
+				Tags:    []string{"a", "b"},
+				Address: &Address{City: "深圳"},
+			}),
+			wantSql:  "INSERT INTO `profile`(`id`,`meta`,`tags`,`address`) VALUES(?,?,?,?);",
+			wantArgs: []interface{}{int64(1), `{"age":18}`, `["a","b"]`, `{"city":"深圳"}`},
+		},
+		{
+			name:     "nil",
+			builder:  NewInserter[Profile](db).Values(&Profile{Id: 1}),
+			wantSql:  "INSERT INTO `profile`(`id`,`meta`,`tags`,`address`) VALUES(?,?,?,?);",
+			wantArgs: []interface{}{int64(1), nil, nil, nil},
+		},
+		{
+			name: "unsupported value",
+			builder: NewInserter[Profile](db).Values(&Profile{
+				Id:   1,
+				Meta: map[string]any{"fn": func() {}},
+			}),
+			wantErr: errs.NewInvalidJSONError("meta", &json.UnsupportedTypeError{Type: reflect.TypeOf(func() {})}),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
@@ -14,33 +14,204 @@
 
 package dialect
 
-import "github.com/gotomicro/eorm/internal/errs"
+import (
+	"strconv"
+	"sync"
+
+	"github.com/gotomicro/eorm/internal/errs"
+)
 
 // Dialect specify config or behavior of special SQL dialects
-type Dialect struct {
-	Name string
-	// in MYSQL, it's "`"
-	Quote byte
+// 除了内置的 MySQL、SQLite 和 PostgreSQL，用户也可以实现该接口，
+// 然后通过 Register 注册自己的方言，例如 SQL Server
+type Dialect interface {
+	// Name 返回方言的名字，例如 MySQL
+	Name() string
+	// Quote 返回用引号括起来的标识符，例如在 MySQL 里面是 `name`
+	Quote(identifier string) string
+	// Placeholder 返回第 index 个参数的占位符，index 从 1 开始
+	// 如果占位符带有序号，那么格式必须是一个字符加上序号，例如 $1
+	Placeholder(index int) string
+	// LimitOffset 返回 LIMIT 和 OFFSET 部分，其中参数使用 ? 作为占位符，
+	// 参数按照出现的顺序返回。limit 或者 offset 为 0 的时候表示没有设置
+	LimitOffset(limit int, offset int) (string, []any)
+	// SupportsReturning 判断是否支持 RETURNING
+	SupportsReturning() bool
+	// MaxParams 是一条语句最多可以使用的参数个数，0 表示不限制
+	MaxParams() int
+}
+
+// standard 是内置方言的实现
+type standard struct {
+	name  string
+	quote byte
+	// bindVar 是占位符，在 MySQL 里面是 '?'
+	// 在 PostgreSQL 里面是 '$'，并且后面需要跟着参数的序号，例如 $1
+	bindVar   byte
+	maxParams int
+	returning bool
+	// offsetNeedsLimit 表示 OFFSET 必须和 LIMIT 一起使用，例如 MySQL 和 SQLite
+	// 此时只设置了 OFFSET 的时候会使用 noLimit 作为 LIMIT
+	offsetNeedsLimit bool
+	noLimit          string
+	features         Features
+}
+
+func (d standard) Name() string {
+	return d.name
+}
+
+func (d standard) Quote(identifier string) string {
+	return string(d.quote) + identifier + string(d.quote)
+}
+
+func (d standard) Placeholder(index int) string {
+	if d.bindVar == '$' {
+		return "$" + strconv.Itoa(index)
+	}
+	return "?"
+}
+
+func (d standard) LimitOffset(limit int, offset int) (string, []any) {
+	switch {
+	case limit > 0 && offset > 0:
+		return " LIMIT ? OFFSET ?", []any{limit, offset}
+	case limit > 0:
+		return " LIMIT ?", []any{limit}
+	case offset > 0 && d.offsetNeedsLimit:
+		return " LIMIT " + d.noLimit + " OFFSET ?", []any{offset}
+	case offset > 0:
+		return " OFFSET ?", []any{offset}
+	default:
+		return "", nil
+	}
+}
+
+func (d standard) SupportsReturning() bool {
+	return d.returning
+}
+
+func (d standard) MaxParams() int {
+	return d.maxParams
+}
+
+func (d standard) Features() Features {
+	return d.features
+}
+
+// ApplySupporter 是一个可选的接口，支持 CROSS APPLY 和 OUTER APPLY 的方言可以实现它，例如 SQL Server
+type ApplySupporter interface {
+	SupportsApply() bool
+}
+
+// SupportsApply 判断 d 是否支持 CROSS APPLY 和 OUTER APPLY
+func SupportsApply(d Dialect) bool {
+	as, ok := d.(ApplySupporter)
+	return ok && as.SupportsApply()
+}
+
+// NumberedBindVar 判断 d 的占位符是否需要带上参数的序号
+func NumberedBindVar(d Dialect) bool {
+	return d.Placeholder(1) != d.Placeholder(2)
 }
 
 var (
-	MySQL = Dialect{
-		Name:  "MySQL",
-		Quote: '`',
+	MySQL Dialect = standard{
+		name:      "MySQL",
+		quote:     '`',
+		bindVar:   '?',
+		maxParams: 65535,
+		// MySQL 不支持单独使用 OFFSET，
+		// 官方文档推荐使用最大的 BIGINT UNSIGNED 作为不限制行数的 LIMIT
+		offsetNeedsLimit: true,
+		noLimit:          "18446744073709551615",
+		features: Features{
+			Median:           MedianGroupConcat,
+			DateTrunc:        DateTruncFormat,
+			JSONTable:        JSONTableFunc,
+			FuncNames:        map[string]string{"LENGTH": "CHAR_LENGTH"},
+			Regexp:           " REGEXP ",
+			NotRegexp:        " NOT REGEXP ",
+			OnDuplicateKey:   true,
+			CalcFoundRows:    true,
+			IndexHints:       true,
+			FullTextMatch:    true,
+			FieldFunc:        true,
+			CompoundParens:   true,
+			NullSafeEqual:    " <=> ",
+			BackslashEscapes: true,
+		},
 	}
-	SQLite = Dialect{
-		Name:  "SQLite",
-		Quote: '`',
+	SQLite Dialect = standard{
+		name:    "SQLite",
+		quote:   '`',
+		bindVar: '?',
+		// SQLITE_MAX_VARIABLE_NUMBER 的默认值
+		maxParams: 32766,
+		returning: true,
+		// SQLite 也不支持单独使用 OFFSET，LIMIT -1 表示不限制行数
+		offsetNeedsLimit: true,
+		noLimit:          "-1",
+		features: Features{
+			AggregateFilter: true,
+			DateTrunc:       DateTruncStrftime,
+			ConcatOperator:  true,
+			FuncNames:       map[string]string{"GREATEST": "MAX"},
+			NullsOrdering:   true,
+			DistinctFrom:    " IS NOT ",
+		},
+	}
+	PostgreSQL Dialect = standard{
+		name:      "PostgreSQL",
+		quote:     '"',
+		bindVar:   '$',
+		maxParams: 65535,
+		returning: true,
+		features: Features{
+			TypeCast:             true,
+			AggregateFilter:      true,
+			StringAgg:            true,
+			BoolAggregate:        true,
+			Median:               MedianPercentileCont,
+			ILike:                true,
+			Regexp:               " ~ ",
+			NotRegexp:            " !~ ",
+			QualifyUpsertColumns: true,
+			NullsOrdering:        true,
+			ForShare:             true,
+			MaterializedCTE:      true,
+			JSONTable:            JSONTableRecordset,
+			FullOuterJoin:        true,
+			Lateral:              true,
+			CompoundParens:       true,
+			DistinctFrom:         " IS DISTINCT FROM ",
+		},
+	}
+)
+
+var (
+	mutex    sync.RWMutex
+	dialects = map[string]Dialect{
+		"sqlite3":  SQLite,
+		"mysql":    MySQL,
+		"postgres": PostgreSQL,
+		"pgx":      PostgreSQL,
 	}
 )
 
+// Register 为 driver 注册方言，已经注册过的 driver 会被覆盖
+func Register(driver string, d Dialect) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	dialects[driver] = d
+}
+
 func Of(driver string) (Dialect, error) {
-	switch driver {
-	case "sqlite3":
-		return SQLite, nil
-	case "mysql":
-		return MySQL, nil
-	default:
-		return Dialect{}, errs.NewUnsupportedDriverError(driver)
+	mutex.RLock()
+	defer mutex.RUnlock()
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, errs.NewUnsupportedDriverError(driver)
 	}
+	return d, nil
 }
@@ -38,6 +38,16 @@ func TestOf(t *testing.T) {
 			driver:      "sqlite3",
 			wantDialect: SQLite,
 		},
+		{
+			name:        "postgres",
+			driver:      "postgres",
+			wantDialect: PostgreSQL,
+		},
+		{
+			name:        "pgx",
+			driver:      "pgx",
+			wantDialect: PostgreSQL,
+		},
 		{
 			name:    "unsupported",
 			driver:  "abc",
@@ -56,3 +66,113 @@ func TestOf(t *testing.T) {
 		})
 	}
 }
+
+func TestRegister(t *testing.T) {
+	_, err := Of("mock")
+	assert.Equal(t, errs.NewUnsupportedDriverError("mock"), err)
+	Register("mock", SQLite)
+	d, err := Of("mock")
+	assert.NoError(t, err)
+	assert.Equal(t, SQLite, d)
+}
+
+func TestDialect_Quote(t *testing.T) {
+	assert.Equal(t, "`name`", MySQL.Quote("name"))
+	assert.Equal(t, `"name"`, PostgreSQL.Quote("name"))
+}
+
+func TestDialect_LimitOffset(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dialect  Dialect
+		limit    int
+		offset   int
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "none",
+			dialect: MySQL,
+		},
+		{
+			name:     "limit",
+			dialect:  MySQL,
+			limit:    10,
+			wantSQL:  " LIMIT ?",
+			wantArgs: []any{10},
+		},
+		{
+			name:     "offset",
+			dialect:  MySQL,
+			offset:   20,
+			wantSQL:  " LIMIT 18446744073709551615 OFFSET ?",
+			wantArgs: []any{20},
+		},
+		{
+			name:     "limit and offset",
+			dialect:  MySQL,
+			limit:    10,
+			offset:   20,
+			wantSQL:  " LIMIT ? OFFSET ?",
+			wantArgs: []any{10, 20},
+		},
+		{
+			name:     "sqlite offset",
+			dialect:  SQLite,
+			offset:   20,
+			wantSQL:  " LIMIT -1 OFFSET ?",
+			wantArgs: []any{20},
+		},
+		{
+			name:     "sqlite limit and offset",
+			dialect:  SQLite,
+			limit:    10,
+			offset:   20,
+			wantSQL:  " LIMIT ? OFFSET ?",
+			wantArgs: []any{10, 20},
+		},
+		{
+			name:     "postgres offset",
+			dialect:  PostgreSQL,
+			offset:   20,
+			wantSQL:  " OFFSET ?",
+			wantArgs: []any{20},
+		},
+		{
+			name:     "postgres limit and offset",
+			dialect:  PostgreSQL,
+			limit:    10,
+			offset:   20,
+			wantSQL:  " LIMIT ? OFFSET ?",
+			wantArgs: []any{10, 20},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args := tc.dialect.LimitOffset(tc.limit, tc.offset)
+			assert.Equal(t, tc.wantSQL, sql)
+			assert.Equal(t, tc.wantArgs, args)
+		})
+	}
+}
+
+func TestDialect_Placeholder(t *testing.T) {
+	assert.Equal(t, "?", MySQL.Placeholder(1))
+	assert.Equal(t, "?", SQLite.Placeholder(2))
+	assert.Equal(t, "$1", PostgreSQL.Placeholder(1))
+	assert.Equal(t, "$12", PostgreSQL.Placeholder(12))
+}
+
+func TestFeaturesOf(t *testing.T) {
+	assert.True(t, FeaturesOf(MySQL).BackslashEscapes)
+	assert.False(t, FeaturesOf(SQLite).BackslashEscapes)
+	assert.False(t, FeaturesOf(SQLite).CompoundParens)
+	assert.True(t, FeaturesOf(PostgreSQL).Lateral)
+	assert.Equal(t, StandardFeatures, FeaturesOf(customDialect{}))
+}
+
+// customDialect 是没有实现 FeatureSupporter 的方言
+type customDialect struct {
+	Dialect
+}
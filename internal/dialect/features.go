@@ -0,0 +1,129 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dialect
+
+// DateTruncStyle 是 DateTrunc 的写法
+type DateTruncStyle int
+
+const (
+	// DateTruncFunc 使用 DATE_TRUNC('unit',col)
+	DateTruncFunc DateTruncStyle = iota
+	// DateTruncFormat 使用 DATE_FORMAT(col,'format')，例如 MySQL
+	DateTruncFormat
+	// DateTruncStrftime 使用 strftime('format',col)，例如 SQLite
+	DateTruncStrftime
+)
+
+// JSONTableStyle 是 JSONTable 的写法
+type JSONTableStyle int
+
+const (
+	// JSONTableUnsupported 表示不支持
+	JSONTableUnsupported JSONTableStyle = iota
+	// JSONTableFunc 使用 JSON_TABLE，例如 MySQL
+	JSONTableFunc
+	// JSONTableRecordset 使用 jsonb_to_recordset，例如 PostgreSQL
+	JSONTableRecordset
+)
+
+// MedianStyle 是 Median 的写法
+type MedianStyle int
+
+const (
+	// MedianUnsupported 表示不支持
+	MedianUnsupported MedianStyle = iota
+	// MedianPercentileCont 使用 PERCENTILE_CONT(0.5) WITHIN GROUP，例如 PostgreSQL
+	MedianPercentileCont
+	// MedianGroupConcat 使用 GROUP_CONCAT 近似，例如 MySQL
+	MedianGroupConcat
+)
+
+// Features 描述了方言支持的特性以及一些语法上的差异
+// 构造 SQL 的时候只根据 Features 来判断，而不是根据方言的名字
+type Features struct {
+	// TypeCast 表示支持 ::type 的类型转换
+	TypeCast bool
+	// AggregateFilter 表示聚合函数支持 FILTER (WHERE ...)，不支持的时候会改写为 CASE WHEN
+	AggregateFilter bool
+	// StringAgg 表示使用 STRING_AGG 而不是 GROUP_CONCAT
+	StringAgg bool
+	// BoolAggregate 表示支持 BOOL_OR 和 BOOL_AND，不支持的时候使用 MAX 和 MIN 代替
+	BoolAggregate bool
+	Median        MedianStyle
+	DateTrunc     DateTruncStyle
+	JSONTable     JSONTableStyle
+	// ConcatOperator 表示使用 || 而不是 CONCAT 函数拼接字符串
+	ConcatOperator bool
+	// FuncNames 是函数的别名，例如 MySQL 里面 LENGTH 对应 CHAR_LENGTH
+	FuncNames map[string]string
+	// ILike 表示支持 ILIKE，不支持的时候会改写为 LOWER(a) LIKE LOWER(b)
+	ILike bool
+	// Regexp 和 NotRegexp 是正则匹配的操作符，例如 " REGEXP "，为空表示不支持
+	Regexp    string
+	NotRegexp string
+	// OnDuplicateKey 表示 upsert 使用 ON DUPLICATE KEY UPDATE 和 VALUES(col)，
+	// 否则使用 ON CONFLICT 和 EXCLUDED.col
+	OnDuplicateKey bool
+	// QualifyUpsertColumns 表示 upsert 的赋值语句右边的列需要加上表名，否则会和 EXCLUDED 中的列产生歧义
+	QualifyUpsertColumns bool
+	// CalcFoundRows 表示支持 SQL_CALC_FOUND_ROWS
+	CalcFoundRows bool
+	// IndexHints 表示支持 USE INDEX 之类的索引提示
+	IndexHints bool
+	// FullTextMatch 表示支持 MATCH ... AGAINST
+	FullTextMatch bool
+	// FieldFunc 表示支持 FIELD 函数，不支持的时候使用等价的 CASE 表达式
+	FieldFunc bool
+	// NullsOrdering 表示支持 NULLS FIRST/LAST，不支持的时候会先按照 ISNULL(expr) 排序
+	NullsOrdering bool
+	// ForShare 表示共享锁使用 FOR SHARE，否则使用 LOCK IN SHARE MODE
+	ForShare bool
+	// MaterializedCTE 表示 CTE 支持 MATERIALIZED 和 NOT MATERIALIZED
+	MaterializedCTE bool
+	// FullOuterJoin 表示支持 FULL OUTER JOIN
+	FullOuterJoin bool
+	// Lateral 表示支持 LATERAL 连接，不支持 APPLY 的方言会将 APPLY 改写为 LATERAL 连接
+	Lateral bool
+	// CompoundParens 表示 UNION 之类的组合查询中，每一个查询都可以用括号括起来
+	CompoundParens bool
+	// DistinctFrom 是 NULL 安全的不等于操作符，例如 " IS DISTINCT FROM "，
+	// 为空的时候使用 NOT (a NullSafeEqual b)，例如 MySQL 的 NOT (a <=> b)
+	DistinctFrom  string
+	NullSafeEqual string
+	// BackslashEscapes 表示字符串字面量里面的 \ 是转义字符，例如 MySQL 的 'It\'s'
+	BackslashEscapes bool
+}
+
+// FeatureSupporter 是一个可选的接口，方言可以实现它来描述自己支持的特性
+// 没有实现的方言使用 StandardFeatures
+type FeatureSupporter interface {
+	Features() Features
+}
+
+// StandardFeatures 是没有实现 FeatureSupporter 的方言使用的特性
+var StandardFeatures = Features{
+	AggregateFilter: true,
+	NullsOrdering:   true,
+	CompoundParens:  true,
+	DistinctFrom:    " IS DISTINCT FROM ",
+}
+
+// FeaturesOf 返回 d 支持的特性
+func FeaturesOf(d Dialect) Features {
+	if fs, ok := d.(FeatureSupporter); ok {
+		return fs.Features()
+	}
+	return StandardFeatures
+}
@@ -29,6 +29,55 @@ var (
 
 	// ErrCombinationIsNotStruct 不支持的组合类型，eorm 只支持结构体组合
 	ErrCombinationIsNotStruct = errors.New("eorm: 不支持的组合类型，eorm 只支持结构体组合")
+
+	// ErrLockModeConflict FOR UPDATE 和 FOR SHARE 只能选择其中一个
+	ErrLockModeConflict = errors.New("eorm: FOR UPDATE 和 FOR SHARE 不能同时使用")
+
+	// ErrCrossJoinWithCondition CROSS JOIN 不能带有 ON 或者 USING
+	ErrCrossJoinWithCondition = errors.New("eorm: CROSS JOIN 不能使用 ON 或者 USING")
+
+	// ErrLockWithAggregation 加锁的查询必须直接返回表中的行，所以不能和 GROUP BY、DISTINCT 或者聚合函数一起使用
+	ErrLockWithAggregation = errors.New("eorm: FOR UPDATE 和 FOR SHARE 只能用于直接返回行的查询，不能和 GROUP BY、DISTINCT 或者聚合函数一起使用")
+
+	// ErrMatchWithoutColumns 全文搜索的 MATCH 必须指定列
+	ErrMatchWithoutColumns = errors.New("eorm: MATCH 必须指定至少一个列")
+
+	// ErrJoinWithOnAndUsing 同一个 JOIN 只能使用 ON 和 USING 中的一个
+	ErrJoinWithOnAndUsing = errors.New("eorm: JOIN 不能同时使用 ON 和 USING")
+
+	// ErrFoundRowsWithoutConn FOUND_ROWS() 必须和 SQL_CALC_FOUND_ROWS 的查询使用同一个连接
+	ErrFoundRowsWithoutConn = errors.New("eorm: FOUND_ROWS() 需要在 Conn 或者 Tx 上执行")
+
+	// ErrTotalWindowWithDistinct 窗口函数在 DISTINCT 之前计算，
+	// 所以 COUNT(*) OVER() 得到的是去重前的总数，并且会影响去重的结果
+	ErrTotalWindowWithDistinct = errors.New("eorm: DISTINCT 不能和 COUNT(*) OVER() 一起使用")
+
+	// ErrUpsertWithoutConflictColumns 在 PostgreSQL 和 SQLite 中，ON CONFLICT DO UPDATE 必须指定冲突的列
+	ErrUpsertWithoutConflictColumns = errors.New("eorm: ON CONFLICT DO UPDATE 必须指定冲突的列")
+
+	// ErrNilDestination 代表接收结果的目标是 nil
+	ErrNilDestination = errors.New("eorm: 接收结果的目标不能为 nil")
+
+	// ErrOptimisticLock 乐观锁更新失败，数据已经被其它人修改或者删除了
+	ErrOptimisticLock = errors.New("eorm: 乐观锁冲突，数据已经被修改")
+
+	// ErrJSONTableWithoutAlias JSON_TABLE 展开的表必须指定别名
+	ErrJSONTableWithoutAlias = errors.New("eorm: JSONTable 必须通过 As 指定别名")
+
+	// ErrWindowWithDistinct 窗口函数不支持 DISTINCT，例如 CountDistinct("Id").Over()
+	ErrWindowWithDistinct = errors.New("eorm: 窗口函数不支持 DISTINCT")
+
+	// ErrNullsOrderingMultipleFields NullsFirst 和 NullsLast 只能用在单个列的排序上
+	ErrNullsOrderingMultipleFields = errors.New("eorm: NullsFirst 和 NullsLast 只能用在单个列上，多个列需要分别使用 ASC 或者 DESC")
+
+	// ErrInvalidNtileBuckets NTILE 的分组数量必须是正整数
+	ErrInvalidNtileBuckets = errors.New("eorm: NTILE 的分组数量必须大于 0")
+
+	// ErrInvalidPageSize Page 每一页的数据条数必须是正整数
+	ErrInvalidPageSize = errors.New("eorm: Page 的 size 必须大于 0")
+
+	// ErrInvalidMultiLimit GetMultiLimit 最多返回的数据条数必须是正整数
+	ErrInvalidMultiLimit = errors.New("eorm: GetMultiLimit 的 n 必须大于 0")
 )
 
 func NewFieldConflictError(field string) error {
@@ -42,6 +91,27 @@ func NewInvalidFieldError(field string) error {
 	return fmt.Errorf("eorm: 未知字段 %s", field)
 }
 
+// NewScalarSubqueryColumnsError 表示作为列使用的标量子查询返回的列数 cnt 不是一列
+func NewScalarSubqueryColumnsError(cnt int) error {
+	return fmt.Errorf("eorm: 标量子查询只能返回一列，实际返回 %d 列", cnt)
+}
+
+// NewInvalidDefaultValueError 表示字段 field 在 default 标签中指定的默认值 val 非法
+func NewInvalidDefaultValueError(field string, val string) error {
+	return fmt.Errorf("eorm: 字段 %s 的默认值 %s 非法", field, val)
+}
+
+// NewInvalidSoftDeleteColumnError 表示软删除的字段不能为 NULL，
+// 它必须是指针或者 sql.NullTime 之类的类型
+func NewInvalidSoftDeleteColumnError(field string) error {
+	return fmt.Errorf("eorm: 软删除字段 %s 必须是可以为 NULL 的类型，例如 *time.Time 或者 sql.NullTime", field)
+}
+
+// NewInvalidFieldPathError 表示模型中不存在 path 对应的字段，path 是组合结构体中字段的完整路径
+func NewInvalidFieldPathError(model string, path string) error {
+	return fmt.Errorf("eorm: 模型 %s 中不存在字段 %s", model, path)
+}
+
 // NewInvalidColumnError 返回代表未知列名的错误
 // 通常来说，是列名不对
 // 注意区分 NewInvalidFieldError
@@ -64,7 +134,100 @@ func NewUnsupportedDriverError(driver string) error {
 	return fmt.Errorf("eorm: 不支持driver类型 %s", driver)
 }
 
+// NewDistinctOrderByError 表示在使用 DISTINCT 的聚合函数中，ORDER BY 使用了参数以外的列
+// 例如 PostgreSQL 要求 STRING_AGG(DISTINCT ... ORDER BY ...) 里面的排序列必须是参数列
+func NewDistinctOrderByError(field string) error {
+	return fmt.Errorf("eorm: 使用 DISTINCT 时只能按照参数列排序，不能按照 %s 排序", field)
+}
+
+// NewDistinctSelectOrderByError 表示在 SELECT DISTINCT 中，ORDER BY 使用了不在 SELECT 列表中的列
+func NewDistinctSelectOrderByError(field string) error {
+	return fmt.Errorf("eorm: 使用 SELECT DISTINCT 时，ORDER BY 的列必须出现在 SELECT 列表中，但是 %s 不在", field)
+}
+
+// NewWindowAliasInHavingError 表示在 HAVING 中使用了窗口函数的别名
+// 窗口函数在 HAVING 之后才计算，所以需要把查询作为子查询，在外层使用 WHERE 过滤
+func NewWindowAliasInHavingError(alias string) error {
+	return fmt.Errorf("eorm: HAVING 不能使用窗口函数的别名 %s，"+
+		"请将查询作为子查询，在外层通过 WHERE 过滤，例如 From(sub).Where(sub.C(\"%s\").EQ(1))", alias, alias)
+}
+
+// NewUnsupportedJoinError 表示当前方言不支持这种 JOIN
+func NewUnsupportedJoinError(typ string, dialect string) error {
+	return fmt.Errorf("eorm: %s 不支持 %s", dialect, typ)
+}
+
+// NewUnsupportedFeatureError 表示当前方言不支持某个特性
+func NewUnsupportedFeatureError(dialect string, feature string) error {
+	return fmt.Errorf("eorm: %s 不支持 %s", dialect, feature)
+}
+
+// NewUnsupportedDateTruncUnitError 表示 DateTrunc 不支持该精度
+func NewUnsupportedDateTruncUnitError(unit string) error {
+	return fmt.Errorf("eorm: DateTrunc 不支持精度 %s", unit)
+}
+
+// NewGeneratedColumnError 表示试图写入数据库生成的列
+func NewGeneratedColumnError(field string) error {
+	return fmt.Errorf("eorm: %s 是生成列，不能插入或者更新", field)
+}
+
+// NewJoinTooDeepError 表示 JOIN 嵌套的深度超过了限制
+func NewJoinTooDeepError(depth int, limit int) error {
+	return fmt.Errorf("eorm: JOIN 的深度 %d 超过了限制 %d", depth, limit)
+}
+
+// NewColumnCountMismatchError 表示按照位置扫描的时候，结果集的列数和字段数不一致
+func NewColumnCountMismatchError(columns int, fields int) error {
+	return fmt.Errorf("eorm: 结果集有 %d 列，但是有 %d 个字段，无法按照位置扫描", columns, fields)
+}
+
+// NewPrimaryKeyNotFoundError 表示指定的主键字段不存在
+func NewPrimaryKeyNotFoundError(field string) error {
+	return fmt.Errorf("eorm: 主键 %s 不存在", field)
+}
+
+// NewInvalidJSONError 表示 JSON 列无法序列化，或者数据库中的值不是合法的 JSON
+func NewInvalidJSONError(column string, err error) error {
+	return fmt.Errorf("eorm: 列 %s 不是合法的 JSON: %w", column, err)
+}
+
+// NewConverterError 表示自定义的类型转换器转换列 column 的值失败
+func NewConverterError(column string, err error) error {
+	return fmt.Errorf("eorm: 列 %s 类型转换失败: %w", column, err)
+}
+
+// NewVersionColumnAssignedError 表示试图手动更新乐观锁的版本号
+func NewVersionColumnAssignedError(field string) error {
+	return fmt.Errorf("eorm: %s 是版本号，由 eorm 自动维护，不能手动更新", field)
+}
+
+// NewTooManyParametersError 表示参数个数超过了数据库的限制
+func NewTooManyParametersError(dialect string, cnt int, limit int) error {
+	return fmt.Errorf("eorm: 参数个数 %d 超过了 %s 的限制 %d，请考虑分批执行，或者将 IN 的参数改为数组绑定", cnt, dialect, limit)
+}
+
+// NewReadOnlyViewError 表示 table 是视图，不支持写操作
+func NewReadOnlyViewError(table string) error {
+	return fmt.Errorf("eorm: %s 是视图，不支持写操作", table)
+}
+
 // NewErrUnsupportedExpressionType 不支持表達式類型
 func NewErrUnsupportedExpressionType(exp any) error {
 	return fmt.Errorf("orm: 不支持表達式 %v", exp)
 }
+
+// NewUnknownDiscriminatorError 表示鉴别列的值没有对应的类型
+func NewUnknownDiscriminatorError(column string, val any) error {
+	return fmt.Errorf("eorm: 列 %s 的值 %v 没有注册对应的类型", column, val)
+}
+
+// NewMissingNamedParamError 表示没有提供命名参数的值
+func NewMissingNamedParamError(name string) error {
+	return fmt.Errorf("eorm: 缺少命名参数 %s 的值", name)
+}
+
+// NewErrUnsupportedAssignableType 表示不支持的赋值语句
+func NewErrUnsupportedAssignableType(a any) error {
+	return fmt.Errorf("eorm: 不支持的赋值语句 %v", a)
+}
@@ -221,7 +221,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res int",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[int](s.orm, "SELECT `id` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[int](s.orm, "SELECT `int_c` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *int {
@@ -232,7 +232,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res int convert string",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[string](s.orm, "SELECT `id` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[string](s.orm, "SELECT `int_c` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *string {
@@ -243,7 +243,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res int convert bytes",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[[]byte](s.orm, "SELECT `id` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[[]byte](s.orm, "SELECT `int_c` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *[]byte {
@@ -254,7 +254,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res string",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[string](s.orm, "SELECT `string` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[string](s.orm, "SELECT `string` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *string {
@@ -265,7 +265,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res string  convert bytes",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[[]byte](s.orm, "SELECT `string` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[[]byte](s.orm, "SELECT `string` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *[]byte {
@@ -276,7 +276,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res bytes",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[[]byte](s.orm, "SELECT `byte_array` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[[]byte](s.orm, "SELECT `byte_array` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *[]byte {
@@ -287,7 +287,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res bytes convert string",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[string](s.orm, "SELECT `byte_array` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[string](s.orm, "SELECT `byte_array` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *string {
@@ -298,7 +298,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res bool",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[bool](s.orm, "SELECT `bool` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[bool](s.orm, "SELECT `bool` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *bool {
@@ -309,7 +309,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res bool convert string",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[string](s.orm, "SELECT `bool` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[string](s.orm, "SELECT `bool` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *string {
@@ -320,7 +320,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res bool convert in",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[int](s.orm, "SELECT `bool` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[int](s.orm, "SELECT `bool` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *int {
@@ -331,7 +331,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res null string ptr",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[sql.NullString](s.orm, "SELECT `null_string_ptr` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[sql.NullString](s.orm, "SELECT `null_string_ptr` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *sql.NullString {
@@ -342,7 +342,7 @@ func (s *SelectTestSuite) TestRawQueryGetBaseType() {
 		{
 			name: "res sring convert null string ptr",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[sql.NullString](s.orm, "SELECT `string` FROM `simple_struct` WHERE `id` = ?;", 1)
+				queryer := eorm.RawQuery[sql.NullString](s.orm, "SELECT `string` FROM `simple_struct` WHERE `int_c` = ?;", 1)
 				return queryer.Get(context.Background())
 			},
 			wantRes: func() *sql.NullString {
@@ -622,7 +622,7 @@ func (s *SelectTestSuiteGetMulti) TestRawQueryGetMultiBaseType() {
 		{
 			name: "res int",
 			queryRes: func() (any, error) {
-				queryer := eorm.RawQuery[int](s.orm, "SELECT `id` FROM `simple_struct`;")
+				queryer := eorm.RawQuery[int](s.orm, "SELECT `int_c` FROM `simple_struct`;")
 				return queryer.GetMulti(context.Background())
 			},
 			wantRes: func() (res []*int) {
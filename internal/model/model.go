@@ -18,6 +18,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -41,12 +42,36 @@ type TableMeta struct {
 	// ColumnMap 是列名到列元数据的映射
 	ColumnMap map[string]*ColumnMeta
 	Typ       reflect.Type
+	// IsView 表示模型对应的是一个视图，视图是只读的
+	// 可以通过在任意字段（一般是 _ struct{}）上加上 eorm:"view" 来标记
+	IsView bool
+	// SoftDeleteColumn 是软删除的列，通过 eorm:"soft_delete" 标记，为 nil 表示没有软删除
+	// 该列为 NULL 表示数据没有被删除
+	SoftDeleteColumn *ColumnMeta
+	// VersionColumn 是乐观锁的版本号列，通过 eorm:"version" 标记，为 nil 表示没有
+	VersionColumn *ColumnMeta
+	// PrimaryKeys 是主键列，按照字段的声明顺序排列
+	// 复合主键的时候会有多个，没有标记 primary_key 的时候为空
+	PrimaryKeys []*ColumnMeta
+}
+
+// FieldByPath 按照字段的完整路径查找列，例如 BaseEntity.CreateTime
+func (t *TableMeta) FieldByPath(path string) (*ColumnMeta, bool) {
+	for _, c := range t.Columns {
+		if c.FieldPath == path {
+			return c, true
+		}
+	}
+	return nil, false
 }
 
 // ColumnMeta represents model's field, or column
 type ColumnMeta struct {
-	ColumnName      string
-	FieldName       string
+	ColumnName string
+	FieldName  string
+	// FieldPath 是从最外层结构体到字段的完整路径，例如 BaseEntity.CreateTime
+	// 没有组合的字段，它和 FieldName 是一样的
+	FieldPath       string
 	Typ             reflect.Type
 	IsPrimaryKey    bool
 	IsAutoIncrement bool
@@ -66,6 +91,40 @@ type ColumnMeta struct {
 	IsHolderType bool
 	// FieldIndexes 用于表达从最外层结构体找到当前ColumnMeta对应的Field所需要的索引集
 	FieldIndexes []int
+	// IsSoftDelete 表示该列是软删除的列
+	IsSoftDelete bool
+	// IsGenerated 表示该列是数据库生成的列，例如 GENERATED ALWAYS AS (...) STORED
+	// 通过 eorm:"generated" 标记，它只能被读取，不能被插入或者更新
+	IsGenerated bool
+	// IsVersion 表示该列是乐观锁的版本号
+	IsVersion bool
+	// IsCreatedAt 表示该列是创建时间，通过 eorm:"created_at" 标记，插入的时候自动填充
+	IsCreatedAt bool
+	// IsUpdatedAt 表示该列是更新时间，通过 eorm:"updated_at" 标记，插入和更新的时候自动填充
+	IsUpdatedAt bool
+	// IsJSON 表示该列存储的是 JSON，通过 eorm:"json" 标记
+	// 写入的时候会将字段序列化为 JSON 字符串，读取的时候反序列化到字段中
+	IsJSON bool
+	// Converter 不为 nil 的时候，写入和读取该列都会使用它进行转换
+	Converter *Converter
+	// Default 是通过 eorm:"default=xxx" 指定的默认值，类型和字段类型一致
+	// 读取到 NULL 的时候，字段会被设置为该值，而不需要使用指针或者 sql.NullXXX
+	Default any
+}
+
+// Converter 是用户自定义的类型转换器，用于既没有实现 driver.Valuer 也没有实现 sql.Scanner 的类型
+// 例如加密或者编码之后存储的列
+type Converter struct {
+	// ToDB 将字段的值转化为写入数据库的值
+	ToDB func(val any) (driver.Value, error)
+	// FromDB 将数据库中的值转化为字段的值，返回值必须能够赋值给字段
+	FromDB func(src []byte) (any, error)
+}
+
+// Nullable 判断列是否可能为 NULL
+// 指针类型，以及 sql.NullString 这一类实现了 sql.Scanner 的类型被认为是可以为 NULL 的
+func (c *ColumnMeta) Nullable() bool {
+	return c.Typ.Kind() == reflect.Ptr || reflect.PtrTo(c.Typ).Implements(scannerType)
 }
 
 // TableMetaOption represents options of TableMeta, this options will cover default cover.
@@ -77,13 +136,28 @@ type MetaRegistry interface {
 	Register(table interface{}, opts ...TableMetaOption) (*TableMeta, error)
 }
 
-func NewMetaRegistry() MetaRegistry {
-	return &tagMetaRegistry{}
+// MetaRegistryOption 用于定制默认的 MetaRegistry
+type MetaRegistryOption func(r *tagMetaRegistry)
+
+// WithConverters 指定类型转换器，类型为 key 的字段都会使用对应的转换器
+func WithConverters(converters map[reflect.Type]Converter) MetaRegistryOption {
+	return func(r *tagMetaRegistry) {
+		r.converters = converters
+	}
+}
+
+func NewMetaRegistry(opts ...MetaRegistryOption) MetaRegistry {
+	r := &tagMetaRegistry{}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
 }
 
 // tagMetaRegistry is the default implementation based on tag eorm
 type tagMetaRegistry struct {
-	metas sync.Map
+	metas      sync.Map
+	converters map[reflect.Type]Converter
 }
 
 func NewTagMetaRegistry() MetaRegistry {
@@ -109,6 +183,12 @@ func (t *tagMetaRegistry) Register(table interface{}, opts ...TableMetaOption) (
 	for _, o := range opts {
 		o(tableMeta)
 	}
+	// 主键可能被 PrimaryKeysOption 修改过，要确保它们都是存在的列
+	for _, pk := range tableMeta.PrimaryKeys {
+		if tableMeta.FieldMap[pk.FieldName] != pk {
+			return nil, errs.NewPrimaryKeyNotFoundError(pk.FieldName)
+		}
+	}
 	rtype := reflect.TypeOf(table)
 	t.metas.Store(rtype, tableMeta)
 	return tableMeta, nil
@@ -122,46 +202,97 @@ func (t *tagMetaRegistry) parseMeta(val any) (*TableMeta, error) {
 	columnMetas := make([]*ColumnMeta, 0, lens)
 	fieldMap := make(map[string]*ColumnMeta, lens)
 	columnMap := make(map[string]*ColumnMeta, lens)
-	err := t.parseFields(v, []int{}, &columnMetas, fieldMap, 0)
+	var isView bool
+	err := t.parseFields(v, []int{}, "", &columnMetas, fieldMap, 0, &isView)
 	if err != nil {
 		return nil, err
 	}
 
+	var softDelete, version *ColumnMeta
+	var pks []*ColumnMeta
 	for _, columnMeta := range columnMetas {
 		columnMap[columnMeta.ColumnName] = columnMeta
+		if columnMeta.IsPrimaryKey {
+			pks = append(pks, columnMeta)
+		}
+		if columnMeta.IsSoftDelete {
+			softDelete = columnMeta
+		}
+		if columnMeta.IsVersion {
+			version = columnMeta
+		}
 	}
 
 	return &TableMeta{
-		Columns:   columnMetas,
-		TableName: underscoreName(v.Name()),
-		Typ:       rtype,
-		FieldMap:  fieldMap,
-		ColumnMap: columnMap,
+		Columns:          columnMetas,
+		TableName:        underscoreName(v.Name()),
+		Typ:              rtype,
+		FieldMap:         fieldMap,
+		ColumnMap:        columnMap,
+		IsView:           isView,
+		SoftDeleteColumn: softDelete,
+		VersionColumn:    version,
+		PrimaryKeys:      pks,
 	}, nil
 }
 
-func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int,
+func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int, pPath string,
 	columnMetas *[]*ColumnMeta, fieldMap map[string]*ColumnMeta,
-	pOffset uintptr) error {
+	pOffset uintptr, isView *bool) error {
 	lens := v.NumField()
 	for i := 0; i < lens; i++ {
 		structField := v.Field(i)
 		tag := structField.Tag.Get("eorm")
-		var isKey, isAuto, isIgnore bool
+		var isKey, isAuto, isIgnore, isSoftDelete, isGenerated, isVersion, isCreatedAt, isUpdatedAt, isJSON bool
+		columnName := underscoreName(structField.Name)
+		var defaultVal any
 		for _, t := range strings.Split(tag, ",") {
 			switch t {
 			case "primary_key":
 				isKey = true
 			case "auto_increment":
 				isAuto = true
+			case "soft_delete":
+				isSoftDelete = true
+			case "generated":
+				isGenerated = true
+			case "version":
+				isVersion = true
+			case "created_at":
+				isCreatedAt = true
+			case "updated_at":
+				isUpdatedAt = true
+			case "json":
+				isJSON = true
 			case "-":
 				isIgnore = true
+			case "view":
+				// view 只是一个标记，该字段本身不会成为列
+				*isView = true
+				isIgnore = true
+			default:
+				// column=xxx 指定列名，用于字段名和列名对不上的情况
+				if name := strings.TrimPrefix(t, "column="); name != t && name != "" {
+					columnName = name
+				}
+				// default=xxx 指定读取到 NULL 时使用的默认值
+				if def := strings.TrimPrefix(t, "default="); def != t {
+					val, err := parseDefault(structField.Type, def)
+					if err != nil {
+						return errs.NewInvalidDefaultValueError(v.Name()+"."+structField.Name, def)
+					}
+					defaultVal = val
+				}
 			}
 		}
 		if isIgnore {
 			// skip the field.
 			continue
 		}
+		fieldPath := structField.Name
+		if pPath != "" {
+			fieldPath = pPath + "." + structField.Name
+		}
 		// 检查列有没有冲突
 		if fieldMap[structField.Name] != nil {
 			return errs.NewFieldConflictError(v.Name() + "." + structField.Name)
@@ -174,7 +305,7 @@ func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int,
 			}
 			// 递归解析
 			o := structField.Offset + pOffset
-			err := t.parseFields(structField.Type, append(fieldIndexes, i), columnMetas, fieldMap, o)
+			err := t.parseFields(structField.Type, append(fieldIndexes, i), fieldPath, columnMetas, fieldMap, o, isView)
 			if err != nil {
 				return err
 			}
@@ -182,14 +313,29 @@ func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int,
 		}
 
 		columnMeta := &ColumnMeta{
-			ColumnName:      underscoreName(structField.Name),
+			ColumnName:      columnName,
 			FieldName:       structField.Name,
+			FieldPath:       fieldPath,
 			Typ:             structField.Type,
 			IsAutoIncrement: isAuto,
 			IsPrimaryKey:    isKey,
 			Offset:          structField.Offset + pOffset,
 			IsHolderType:    structField.Type.AssignableTo(scannerType) && structField.Type.AssignableTo(driverValuerType),
 			FieldIndexes:    append(fieldIndexes, i),
+			IsSoftDelete:    isSoftDelete,
+			IsGenerated:     isGenerated,
+			IsVersion:       isVersion,
+			IsCreatedAt:     isCreatedAt,
+			IsUpdatedAt:     isUpdatedAt,
+			IsJSON:          isJSON,
+			Default:         defaultVal,
+		}
+		if c, ok := t.converters[structField.Type]; ok {
+			columnMeta.Converter = &c
+		}
+		// 软删除依赖 deleted_at IS NULL 判断数据有没有被删除，所以必须是可以为 NULL 的类型
+		if isSoftDelete && !columnMeta.Nullable() {
+			return errs.NewInvalidSoftDeleteColumnError(v.Name() + "." + structField.Name)
 		}
 		*columnMetas = append(*columnMetas, columnMeta)
 		fieldMap[columnMeta.FieldName] = columnMeta
@@ -197,6 +343,42 @@ func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int,
 	return nil
 }
 
+// parseDefault 将 default 标签中的值转化为 typ 类型，只支持字符串、数字和布尔类型
+func parseDefault(typ reflect.Type, s string) (any, error) {
+	val := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		val.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+		val.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		val.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		val.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return nil, err
+		}
+		val.SetFloat(f)
+	default:
+		return nil, errs.NewUnsupportedTypeError(typ)
+	}
+	return val.Interface(), nil
+}
+
 // IgnoreFieldsOption function provide an option to ignore some fields when register table.
 func IgnoreFieldsOption(fieldNames ...string) TableMetaOption {
 	return func(meta *TableMeta) {
@@ -212,24 +394,67 @@ func IgnoreFieldsOption(fieldNames ...string) TableMetaOption {
 				}
 				// delete field in fieldMap
 				delete(meta.FieldMap, field)
+				if meta.SoftDeleteColumn != nil && meta.SoftDeleteColumn.FieldName == field {
+					meta.SoftDeleteColumn = nil
+				}
+				if meta.VersionColumn != nil && meta.VersionColumn.FieldName == field {
+					meta.VersionColumn = nil
+				}
+				for index, pk := range meta.PrimaryKeys {
+					if pk.FieldName == field {
+						meta.PrimaryKeys = append(meta.PrimaryKeys[:index], meta.PrimaryKeys[index+1:]...)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// PrimaryKeysOption 指定主键，它会覆盖 primary_key 标签，fields 的顺序就是主键列的顺序
+// 适用于无法修改标签的结构体，例如组合了第三方的结构体。fields 必须都是存在的字段，否则注册的时候会返回错误
+func PrimaryKeysOption(fields ...string) TableMetaOption {
+	return func(meta *TableMeta) {
+		for _, pk := range meta.PrimaryKeys {
+			pk.IsPrimaryKey = false
+		}
+		pks := make([]*ColumnMeta, 0, len(fields))
+		for _, field := range fields {
+			cm, ok := meta.FieldMap[field]
+			if !ok {
+				// 留给 Register 校验
+				cm = &ColumnMeta{FieldName: field}
 			}
+			cm.IsPrimaryKey = true
+			pks = append(pks, cm)
 		}
+		meta.PrimaryKeys = pks
 	}
 }
 
-// underscoreName function mainly converts upper case to lower case and adds an underscore in between
-func underscoreName(tableName string) string {
-	var buf []byte
-	for i, v := range tableName {
+// underscoreName 将驼峰命名转换为下划线命名，转换规则如下：
+// 1. 大写字母统一转为小写
+// 2. 大写字母前面是小写字母或者数字时，在它前面插入下划线，例如 FirstName -> first_name, Model2Name -> model2_name
+// 3. 连续大写字母（缩写）视为一个单词，直到最后一个大写字母后面跟着小写字母，
+// 这个大写字母才作为新单词的开头，例如 UserID -> user_id, HTTPStatus -> http_status
+// 4. 数字跟随前一个单词，不会插入下划线，例如 Addr2 -> addr2
+func underscoreName(name string) string {
+	runes := []rune(name)
+	buf := make([]rune, 0, len(runes)+4)
+	for i, v := range runes {
 		if unicode.IsUpper(v) {
-			if i != 0 {
-				buf = append(buf, '_')
+			if i > 0 {
+				prev := runes[i-1]
+				nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+					(unicode.IsUpper(prev) && nextIsLower) {
+					buf = append(buf, '_')
+				}
 			}
-			buf = append(buf, byte(unicode.ToLower(v)))
+			buf = append(buf, unicode.ToLower(v))
 		} else {
-			buf = append(buf, byte(v))
+			buf = append(buf, v)
 		}
-
 	}
 	return string(buf)
 }
@@ -15,9 +15,11 @@
 package model
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gotomicro/eorm/internal/errs"
 
@@ -72,6 +74,107 @@ func TestTagMetaRegistry(t *testing.T) {
 			}.build(),
 			input: &TestModel{},
 		},
+		{
+			name: "view",
+			wantMeta: tableMetaBuilder{
+				TableName: "test_view",
+				Columns: []*ColumnMeta{
+					{
+						ColumnName:   "id",
+						FieldName:    "Id",
+						Typ:          reflect.TypeOf(int64(0)),
+						FieldIndexes: []int{1},
+					},
+				},
+				Typ:    reflect.TypeOf(&TestView{}),
+				IsView: true,
+			}.build(),
+			input: &TestView{},
+		},
+		{
+			name: "soft delete",
+			wantMeta: tableMetaBuilder{
+				TableName: "test_soft_delete",
+				Columns: []*ColumnMeta{
+					{
+						ColumnName:   "id",
+						FieldName:    "Id",
+						Typ:          reflect.TypeOf(int64(0)),
+						FieldIndexes: []int{0},
+					},
+					{
+						ColumnName:   "deleted_at",
+						FieldName:    "DeletedAt",
+						Typ:          reflect.TypeOf((*time.Time)(nil)),
+						Offset:       8,
+						FieldIndexes: []int{1},
+						IsSoftDelete: true,
+					},
+				},
+				Typ: reflect.TypeOf(&TestSoftDelete{}),
+			}.build(),
+			input: &TestSoftDelete{},
+		},
+		{
+			name: "column name",
+			wantMeta: tableMetaBuilder{
+				TableName: "test_column_name",
+				Columns: []*ColumnMeta{
+					{
+						ColumnName:      "id",
+						FieldName:       "Id",
+						Typ:             reflect.TypeOf(int64(0)),
+						IsPrimaryKey:    true,
+						IsAutoIncrement: true,
+						FieldIndexes:    []int{0},
+					},
+					{
+						ColumnName:   "email_address",
+						FieldName:    "Email",
+						Typ:          reflect.TypeOf(""),
+						Offset:       8,
+						FieldIndexes: []int{1},
+					},
+				},
+				Typ: reflect.TypeOf(&TestColumnName{}),
+			}.build(),
+			input: &TestColumnName{},
+		},
+		{
+			name: "default value",
+			wantMeta: tableMetaBuilder{
+				TableName: "test_default",
+				Columns: []*ColumnMeta{
+					{
+						ColumnName:   "age",
+						FieldName:    "Age",
+						Typ:          reflect.TypeOf(int8(0)),
+						FieldIndexes: []int{0},
+						Default:      int8(18),
+					},
+					{
+						ColumnName:   "name",
+						FieldName:    "Name",
+						Typ:          reflect.TypeOf(""),
+						Offset:       8,
+						FieldIndexes: []int{1},
+						Default:      "unknown",
+					},
+				},
+				Typ: reflect.TypeOf(&TestDefault{}),
+			}.build(),
+			input: &TestDefault{},
+		},
+		{
+			name:    "invalid default value",
+			input:   &TestInvalidDefault{},
+			wantErr: errs.NewInvalidDefaultValueError("TestInvalidDefault.Age", "abc"),
+		},
+		{
+			name:    "soft delete not nullable",
+			input:   &TestInvalidSoftDelete{},
+			wantErr: errs.NewInvalidSoftDeleteColumnError("TestInvalidSoftDelete.DeletedAt"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -103,6 +206,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:      "create_time",
 						FieldName:       "CreateTime",
+						FieldPath:       "BaseEntity.CreateTime",
 						Typ:             reflect.TypeOf(uint64(0)),
 						IsPrimaryKey:    false,
 						IsAutoIncrement: false,
@@ -111,6 +215,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					}, {
 						ColumnName:      "update_time",
 						FieldName:       "UpdateTime",
+						FieldPath:       "BaseEntity.UpdateTime",
 						Typ:             reflect.TypeOf(uint64(0)),
 						IsPrimaryKey:    false,
 						IsAutoIncrement: false,
@@ -208,6 +313,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:      "create_time",
 						FieldName:       "CreateTime",
+						FieldPath:       "BaseEntity.CreateTime",
 						Typ:             reflect.TypeOf(uint64(0)),
 						IsPrimaryKey:    false,
 						IsAutoIncrement: false,
@@ -217,6 +323,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:      "update_time",
 						FieldName:       "UpdateTime",
+						FieldPath:       "BaseEntity.UpdateTime",
 						Typ:             reflect.TypeOf(uint64(0)),
 						IsPrimaryKey:    false,
 						IsAutoIncrement: false,
@@ -256,6 +363,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:   "phone",
 						FieldName:    "Phone",
+						FieldPath:    "Contact.Phone",
 						Typ:          reflect.TypeOf(""),
 						Offset:       56,
 						FieldIndexes: []int{5, 0},
@@ -263,6 +371,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:   "address",
 						FieldName:    "Address",
+						FieldPath:    "Contact.Address",
 						Typ:          reflect.TypeOf(""),
 						Offset:       72,
 						FieldIndexes: []int{5, 1},
@@ -281,6 +390,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:      "create_time",
 						FieldName:       "CreateTime",
+						FieldPath:       "TestCombinedModel.BaseEntity.CreateTime",
 						Typ:             reflect.TypeOf(uint64(0)),
 						IsPrimaryKey:    false,
 						IsAutoIncrement: false,
@@ -289,6 +399,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					}, {
 						ColumnName:      "update_time",
 						FieldName:       "UpdateTime",
+						FieldPath:       "TestCombinedModel.BaseEntity.UpdateTime",
 						Typ:             reflect.TypeOf(uint64(0)),
 						IsPrimaryKey:    false,
 						IsAutoIncrement: false,
@@ -298,6 +409,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:      "id",
 						FieldName:       "Id",
+						FieldPath:       "TestCombinedModel.Id",
 						Typ:             reflect.TypeOf(int64(0)),
 						IsPrimaryKey:    true,
 						IsAutoIncrement: true,
@@ -307,6 +419,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:   "first_name",
 						FieldName:    "FirstName",
+						FieldPath:    "TestCombinedModel.FirstName",
 						Typ:          reflect.TypeOf(""),
 						Offset:       24,
 						FieldIndexes: []int{0, 2},
@@ -314,6 +427,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:   "age",
 						FieldName:    "Age",
+						FieldPath:    "TestCombinedModel.Age",
 						Typ:          reflect.TypeOf(int8(0)),
 						Offset:       40,
 						FieldIndexes: []int{0, 3},
@@ -321,6 +435,7 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 					{
 						ColumnName:   "last_name",
 						FieldName:    "LastName",
+						FieldPath:    "TestCombinedModel.LastName",
 						Typ:          reflect.TypeOf((*string)(nil)),
 						Offset:       48,
 						FieldIndexes: []int{0, 4},
@@ -383,6 +498,132 @@ type TestIgnoreModel struct {
 	LastName  string
 }
 
+func TestPrimaryKeys(t *testing.T) {
+	type OrderItem struct {
+		ItemId  int64 `eorm:"primary_key"`
+		Amount  int64
+		OrderId int64 `eorm:"primary_key"`
+	}
+	testCases := []struct {
+		name    string
+		opts    []TableMetaOption
+		wantPKs []string
+		wantErr error
+	}{
+		{
+			name:    "tags",
+			wantPKs: []string{"ItemId", "OrderId"},
+		},
+		{
+			name:    "option",
+			opts:    []TableMetaOption{PrimaryKeysOption("OrderId", "ItemId")},
+			wantPKs: []string{"OrderId", "ItemId"},
+		},
+		{
+			name:    "ignore primary key",
+			opts:    []TableMetaOption{IgnoreFieldsOption("ItemId")},
+			wantPKs: []string{"OrderId"},
+		},
+		{
+			name:    "option with invalid field",
+			opts:    []TableMetaOption{PrimaryKeysOption("OrderId", "Invalid")},
+			wantErr: errs.NewPrimaryKeyNotFoundError("Invalid"),
+		},
+		{
+			name:    "option with ignored field",
+			opts:    []TableMetaOption{IgnoreFieldsOption("ItemId"), PrimaryKeysOption("OrderId", "ItemId")},
+			wantErr: errs.NewPrimaryKeyNotFoundError("ItemId"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := &tagMetaRegistry{}
+			meta, err := registry.Register(&OrderItem{}, tc.opts...)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			pks := make([]string, 0, len(meta.PrimaryKeys))
+			for _, pk := range meta.PrimaryKeys {
+				assert.True(t, pk.IsPrimaryKey)
+				pks = append(pks, pk.FieldName)
+			}
+			assert.Equal(t, tc.wantPKs, pks)
+		})
+	}
+}
+
+func TestColumnMeta_Nullable(t *testing.T) {
+	meta, err := (&tagMetaRegistry{}).Get(&struct {
+		Id        int64
+		FirstName *string
+		LastName  sql.NullString
+	}{})
+	assert.Nil(t, err)
+	assert.False(t, meta.FieldMap["Id"].Nullable())
+	assert.True(t, meta.FieldMap["FirstName"].Nullable())
+	assert.True(t, meta.FieldMap["LastName"].Nullable())
+}
+
+func TestUnderscoreName(t *testing.T) {
+	testCases := []struct {
+		name    string
+		srcStr  string
+		wantStr string
+	}{
+		{
+			name:    "upper cases",
+			srcStr:  "ID",
+			wantStr: "id",
+		},
+		{
+			name:    "camel case",
+			srcStr:  "FirstName",
+			wantStr: "first_name",
+		},
+		{
+			name:    "acronym suffix",
+			srcStr:  "UserID",
+			wantStr: "user_id",
+		},
+		{
+			name:    "acronym prefix",
+			srcStr:  "HTTPStatus",
+			wantStr: "http_status",
+		},
+		{
+			name:    "acronym in the middle",
+			srcStr:  "MyHTTPServer",
+			wantStr: "my_http_server",
+		},
+		{
+			name:    "digit suffix",
+			srcStr:  "Addr2",
+			wantStr: "addr2",
+		},
+		{
+			name:    "digit in the middle",
+			srcStr:  "Model2Name",
+			wantStr: "model2_name",
+		},
+		{
+			name:    "acronym with digit",
+			srcStr:  "OAuth2ID",
+			wantStr: "o_auth2_id",
+		},
+		{
+			name:    "lower case",
+			srcStr:  "name",
+			wantStr: "name",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantStr, underscoreName(tc.srcStr))
+		})
+	}
+}
+
 func ExampleMetaRegistry_Get() {
 	tm := &TestModel{}
 	registry := &tagMetaRegistry{}
@@ -442,6 +683,7 @@ type tableMetaBuilder struct {
 	TableName string
 	Columns   []*ColumnMeta
 	Typ       reflect.Type
+	IsView    bool
 }
 
 func (t tableMetaBuilder) build() *TableMeta {
@@ -449,19 +691,59 @@ func (t tableMetaBuilder) build() *TableMeta {
 		TableName: t.TableName,
 		Columns:   t.Columns,
 		Typ:       t.Typ,
+		IsView:    t.IsView,
 	}
 	n := len(t.Columns)
 	fieldMap := make(map[string]*ColumnMeta, n)
 	columnMap := make(map[string]*ColumnMeta, n)
 	for _, columnMeta := range t.Columns {
+		// 没有组合的字段，路径就是字段名
+		if columnMeta.FieldPath == "" {
+			columnMeta.FieldPath = columnMeta.FieldName
+		}
 		fieldMap[columnMeta.FieldName] = columnMeta
 		columnMap[columnMeta.ColumnName] = columnMeta
+		if columnMeta.IsSoftDelete {
+			res.SoftDeleteColumn = columnMeta
+		}
+		if columnMeta.IsPrimaryKey {
+			res.PrimaryKeys = append(res.PrimaryKeys, columnMeta)
+		}
 	}
 	res.FieldMap = fieldMap
 	res.ColumnMap = columnMap
 	return res
 }
 
+type TestView struct {
+	_  struct{} `eorm:"view"`
+	Id int64
+}
+
+type TestColumnName struct {
+	Id    int64  `eorm:"primary_key,column=id,auto_increment"`
+	Email string `eorm:"column=email_address"`
+}
+
+type TestDefault struct {
+	Age  int8   `eorm:"default=18"`
+	Name string `eorm:"default=unknown"`
+}
+
+type TestInvalidDefault struct {
+	Age int8 `eorm:"default=abc"`
+}
+
+type TestInvalidSoftDelete struct {
+	Id        int64
+	DeletedAt int64 `eorm:"soft_delete"`
+}
+
+type TestSoftDelete struct {
+	Id        int64
+	DeletedAt *time.Time `eorm:"soft_delete"`
+}
+
 type TestModel struct {
 	Id        int64 `eorm:"auto_increment,primary_key"`
 	FirstName string
@@ -17,6 +17,7 @@ package valuer
 import (
 	"database/sql"
 	"reflect"
+	"time"
 
 	"github.com/gotomicro/eorm/internal/model"
 )
@@ -37,15 +38,50 @@ func (s supportBasicTypeValue) Field(name string) (any, error) {
 func (s supportBasicTypeValue) SetColumns(rows *sql.Rows) error {
 	switch s.valType.Elem().Kind() {
 	case reflect.Struct:
+		// 实现了 sql.Scanner 的类型交给它自己解析，例如 shopspring/decimal 的 decimal.Decimal
+		// 它会把驱动返回的 []byte 或者 string 解析为精确的小数
 		if scanner, ok := s.val.(sql.Scanner); ok {
 			return rows.Scan(scanner)
 		}
+		// time.Time 虽然是结构体，但是驱动可以直接扫描，要作为基本类型处理
+		if t, ok := s.val.(*time.Time); ok {
+			return rows.Scan(t)
+		}
 		return s.Value.SetColumns(rows)
+	case reflect.Map:
+		if m, ok := s.val.(*map[string]any); ok {
+			return scanMap(rows, m)
+		}
+		return rows.Scan(s.val)
 	default:
+		// 包括实现了 sql.Scanner 的数组类型，例如 google/uuid 的 UUID，rows.Scan 会调用它的 Scan
 		return rows.Scan(s.val)
 	}
 }
 
+// scanMap 将当前行按照列名写入 m 中，值的类型由驱动决定
+func scanMap(rows *sql.Rows, m *map[string]any) error {
+	cs, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	vals := make([]any, len(cs))
+	ptrs := make([]any, len(cs))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	if err = rows.Scan(ptrs...); err != nil {
+		return err
+	}
+	if *m == nil {
+		*m = make(map[string]any, len(cs))
+	}
+	for i, c := range cs {
+		(*m)[c] = vals[i]
+	}
+	return nil
+}
+
 // BasicTypeCreator 支持基本类型的 Creator, 基于原生的 Creator 扩展
 type BasicTypeCreator struct {
 	Creator
@@ -218,12 +218,12 @@ func Test_basicTypeValue_SetColumn(t *testing.T) {
 			name:       "struct ptr value",
 			valCreator: NewUnsafeValue,
 			cs: map[string][]byte{
-				"id":               []byte("1"),
+				"int_c":            []byte("1"),
 				"bool":             []byte("true"),
 				"bool_ptr":         []byte("false"),
 				"int":              []byte("12"),
 				"int_ptr":          []byte("13"),
-				"int8":             []byte("8"),
+				"int8_c":           []byte("8"),
 				"int8_ptr":         []byte("-8"),
 				"int16":            []byte("16"),
 				"int16_ptr":        []byte("-16"),
@@ -267,6 +267,33 @@ func Test_basicTypeValue_SetColumn(t *testing.T) {
 			val:        &test.SimpleStruct{},
 			wantErr:    errs.NewInvalidColumnError("invalid_column"),
 		},
+		{
+			name:       "map",
+			valCreator: NewUnsafeValue,
+			cs: map[string][]byte{
+				"id":         []byte("10"),
+				"first_name": []byte("Tom"),
+			},
+			val: new(map[string]any),
+			wantVal: &map[string]any{
+				"id":         []byte("10"),
+				"first_name": []byte("Tom"),
+			},
+		},
+		{
+			name:       "reuse map",
+			valCreator: NewReflectValue,
+			cs: map[string][]byte{
+				"id": []byte("10"),
+			},
+			val: &map[string]any{
+				"age": 18,
+			},
+			wantVal: &map[string]any{
+				"id":  []byte("10"),
+				"age": 18,
+			},
+		},
 	}
 
 	r := model.NewMetaRegistry()
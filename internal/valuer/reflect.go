@@ -73,6 +73,7 @@ func (r reflectValue) SetColumns(rows *sql.Rows) error {
 	// colValues 和 colEleValues 实质上最终都指向同一个对象
 	colValues := make([]interface{}, len(cs))
 	colEleValues := make([]reflect.Value, len(cs))
+	var defaults []nullDefault
 
 	for i, c := range cs {
 		cm, ok := r.meta.ColumnMap[c]
@@ -80,13 +81,21 @@ func (r reflectValue) SetColumns(rows *sql.Rows) error {
 			return errs.NewInvalidColumnError(c)
 		}
 		val := reflect.New(cm.Typ)
-		colValues[i] = val.Interface()
 		colEleValues[i] = val.Elem()
+		if nd, ok := newNullDefault(cm, val); ok {
+			defaults = append(defaults, nd)
+			colValues[i] = nd.holder.Interface()
+			continue
+		}
+		colValues[i] = scanTarget(cm, val)
 	}
 
 	if err = rows.Scan(colValues...); err != nil {
 		return err
 	}
+	for _, nd := range defaults {
+		nd.apply()
+	}
 
 	for i, c := range cs {
 		cm := r.meta.ColumnMap[c]
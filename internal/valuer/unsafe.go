@@ -62,6 +62,7 @@ func (u unsafeValue) SetColumns(rows *sql.Rows) error {
 
 	// TODO 性能优化
 	colValues := make([]interface{}, len(cs))
+	var defaults []nullDefault
 	for i, c := range cs {
 		cm, ok := u.meta.ColumnMap[c]
 		if !ok {
@@ -69,7 +70,18 @@ func (u unsafeValue) SetColumns(rows *sql.Rows) error {
 		}
 		ptr := unsafe.Pointer(uintptr(u.addr) + cm.Offset)
 		val := reflect.NewAt(cm.Typ, ptr)
-		colValues[i] = val.Interface()
+		if nd, ok := newNullDefault(cm, val); ok {
+			defaults = append(defaults, nd)
+			colValues[i] = nd.holder.Interface()
+			continue
+		}
+		colValues[i] = scanTarget(cm, val)
+	}
+	if err = rows.Scan(colValues...); err != nil {
+		return err
+	}
+	for _, nd := range defaults {
+		nd.apply()
 	}
-	return rows.Scan(colValues...)
+	return nil
 }
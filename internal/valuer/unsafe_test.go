@@ -49,12 +49,12 @@ func testSetColumn(t *testing.T, creator Creator) {
 			{
 				name: "normal value",
 				cs: map[string][]byte{
-					"id":               []byte("1"),
+					"int_c":            []byte("1"),
 					"bool":             []byte("true"),
 					"bool_ptr":         []byte("false"),
 					"int":              []byte("12"),
 					"int_ptr":          []byte("13"),
-					"int8":             []byte("8"),
+					"int8_c":           []byte("8"),
 					"int8_ptr":         []byte("-8"),
 					"int16":            []byte("16"),
 					"int16_ptr":        []byte("-16"),
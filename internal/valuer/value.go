@@ -16,7 +16,11 @@ package valuer
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
 
+	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/gotomicro/eorm/internal/model"
 )
 
@@ -30,3 +34,99 @@ type Value interface {
 }
 
 type Creator func(val any, meta *model.TableMeta) Value
+
+// jsonScanner 用于读取 JSON 列，将数据库中的值反序列化到 val 中
+// val 必须是指向字段的指针，NULL 会保持字段的零值
+type jsonScanner struct {
+	val    any
+	column string
+}
+
+func (j jsonScanner) Scan(src any) error {
+	var bs []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		bs = v
+	case string:
+		bs = []byte(v)
+	default:
+		return errs.NewInvalidJSONError(j.column, fmt.Errorf("不支持的类型 %T", src))
+	}
+	if err := json.Unmarshal(bs, j.val); err != nil {
+		return errs.NewInvalidJSONError(j.column, err)
+	}
+	return nil
+}
+
+// converterScanner 使用自定义转换器读取列，ptr 是指向字段的指针
+type converterScanner struct {
+	ptr    reflect.Value
+	column string
+	fromDB func(src []byte) (any, error)
+}
+
+func (c converterScanner) Scan(src any) error {
+	var bs []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		bs = v
+	case string:
+		bs = []byte(v)
+	default:
+		bs = []byte(fmt.Sprint(v))
+	}
+	val, err := c.fromDB(bs)
+	if err != nil {
+		return errs.NewConverterError(c.column, err)
+	}
+	rv := reflect.ValueOf(val)
+	fd := c.ptr.Elem()
+	if !rv.IsValid() || !rv.Type().AssignableTo(fd.Type()) {
+		return errs.NewConverterError(c.column, fmt.Errorf("%T 不能赋值给 %s", val, fd.Type()))
+	}
+	fd.Set(rv)
+	return nil
+}
+
+// scanTarget 返回扫描 cm 列时传给 rows.Scan 的目标
+func scanTarget(cm *model.ColumnMeta, ptr reflect.Value) any {
+	if cm.Converter != nil {
+		return converterScanner{ptr: ptr, column: cm.ColumnName, fromDB: cm.Converter.FromDB}
+	}
+	if cm.IsJSON {
+		return jsonScanner{val: ptr.Interface(), column: cm.ColumnName}
+	}
+	return ptr.Interface()
+}
+
+// nullDefault 用于读取设置了默认值的列
+// 先扫描到 holder（指向字段类型指针的指针）中，读取到 NULL 的时候再将字段设置为默认值
+type nullDefault struct {
+	holder reflect.Value
+	field  reflect.Value
+	def    any
+}
+
+// newNullDefault 在 cm 设置了默认值的时候返回 true，自定义转换器和 JSON 列自行处理 NULL
+func newNullDefault(cm *model.ColumnMeta, ptr reflect.Value) (nullDefault, bool) {
+	if cm.Default == nil || cm.Converter != nil || cm.IsJSON {
+		return nullDefault{}, false
+	}
+	return nullDefault{
+		holder: reflect.New(reflect.PtrTo(cm.Typ)),
+		field:  ptr.Elem(),
+		def:    cm.Default,
+	}, true
+}
+
+func (n nullDefault) apply() {
+	if v := n.holder.Elem(); !v.IsNil() {
+		n.field.Set(v.Elem())
+		return
+	}
+	n.field.Set(reflect.ValueOf(n.def))
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"database/sql"
+
+	"github.com/gotomicro/eorm/internal/model"
+	"github.com/gotomicro/eorm/internal/valuer"
+)
+
+// Iterator 以流的形式遍历查询结果，每次只会在内存中保留一行数据
+// 使用完毕之后必须调用 Close 释放连接
+type Iterator[T any] struct {
+	rows       *sql.Rows
+	meta       *model.TableMeta
+	valCreator valuer.BasicTypeCreator
+}
+
+// Next 准备下一行数据，没有数据或者出错的时候返回 false，
+// 可以通过 Err 来判断是否出错
+func (it *Iterator[T]) Next() bool {
+	return it.rows.Next()
+}
+
+// Scan 将当前行的数据转换为 T
+func (it *Iterator[T]) Scan() (*T, error) {
+	tp := new(T)
+	val := it.valCreator.NewBasicTypeValue(tp, it.meta)
+	if err := val.SetColumns(it.rows); err != nil {
+		return nil, err
+	}
+	return tp, nil
+}
+
+// Err 返回遍历过程中出现的错误
+func (it *Iterator[T]) Err() error {
+	return it.rows.Err()
+}
+
+// Close 关闭底层的 *sql.Rows，可以重复调用
+func (it *Iterator[T]) Close() error {
+	return it.rows.Close()
+}
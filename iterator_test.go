@@ -0,0 +1,103 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelector_Rows(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		mockRows func()
+		wantErr  error
+		wantRes  []*TestModel
+	}{
+		{
+			name: "query error",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT .*").WillReturnError(errors.New("mock error"))
+			},
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name: "no rows",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT .*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "age"}))
+			},
+			wantRes: []*TestModel{},
+		},
+		{
+			name: "multiple rows",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT .*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "age"}).
+						AddRow(1, "Tom", 18).AddRow(2, "Jerry", 20))
+			},
+			wantRes: []*TestModel{
+				{Id: 1, FirstName: "Tom", Age: 18},
+				{Id: 2, FirstName: "Jerry", Age: 20},
+			},
+		},
+		{
+			name: "row error",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT .*").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "age"}).
+						AddRow(1, "Tom", 18).AddRow(2, "Jerry", 20).
+						RowError(1, errors.New("row error")))
+			},
+			wantErr: errors.New("row error"),
+			wantRes: []*TestModel{
+				{Id: 1, FirstName: "Tom", Age: 18},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRows()
+			it, err := NewSelector[TestModel](db).
+				Select(C("Id"), C("FirstName"), C("Age")).Rows(context.Background())
+			if err != nil {
+				assert.Equal(t, tc.wantErr, err)
+				return
+			}
+			res := make([]*TestModel, 0, len(tc.wantRes))
+			for it.Next() {
+				tm, err := it.Scan()
+				require.NoError(t, err)
+				res = append(res, tm)
+			}
+			assert.Equal(t, tc.wantErr, it.Err())
+			assert.NoError(t, it.Close())
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -16,6 +16,7 @@ package eorm
 
 import (
 	"context"
+	"time"
 
 	"github.com/gotomicro/eorm/internal/model"
 )
@@ -26,6 +27,11 @@ type QueryContext struct {
 	q    *Query
 }
 
+// GetQuery 返回即将执行的 SQL 和参数
+func (qc *QueryContext) GetQuery() *Query {
+	return qc.q
+}
+
 type QueryResult struct {
 	Result any
 	Err    error
@@ -34,3 +40,36 @@ type QueryResult struct {
 type Middleware func(next HandleFunc) HandleFunc
 
 type HandleFunc func(ctx context.Context, queryContext *QueryContext) *QueryResult
+
+// Hook 是对 Middleware 的简单封装，用于在查询执行的前后执行一些逻辑，例如日志、监控和链路追踪
+type Hook struct {
+	// Before 在执行查询之前被调用，可以返回一个新的 context
+	// 返回 error 的时候会中断执行，该 error 会作为查询的结果返回，After 依旧会被调用
+	Before func(ctx context.Context, qc *QueryContext) (context.Context, error)
+	// After 在执行查询之后被调用，duration 是执行耗时
+	// 可以通过修改 res.Err 来包装或者替换原本的错误
+	After func(ctx context.Context, qc *QueryContext, res *QueryResult, duration time.Duration)
+}
+
+// Middleware 将 Hook 转化为 Middleware
+func (h Hook) Middleware() Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, qc *QueryContext) *QueryResult {
+			start := time.Now()
+			var res *QueryResult
+			if h.Before != nil {
+				var err error
+				if ctx, err = h.Before(ctx, qc); err != nil {
+					res = &QueryResult{Err: err}
+				}
+			}
+			if res == nil {
+				res = next(ctx, qc)
+			}
+			if h.After != nil {
+				h.After(ctx, qc, res, time.Since(start))
+			}
+			return res
+		}
+	}
+}
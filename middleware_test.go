@@ -17,8 +17,12 @@ package eorm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/stretchr/testify/require"
 
 	"github.com/stretchr/testify/assert"
@@ -111,3 +115,64 @@ func Test_Middleware_order(t *testing.T) {
 	assert.Equal(t, "123", string(res))
 
 }
+
+func TestDBWithHooks(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	var logs []string
+	logHook := Hook{
+		After: func(ctx context.Context, qc *QueryContext, res *QueryResult, duration time.Duration) {
+			assert.True(t, duration >= 0)
+			logs = append(logs, fmt.Sprintf("%s %s %v %v", qc.Type, qc.GetQuery().SQL, qc.GetQuery().Args, res.Err))
+		},
+	}
+	errForbidden := errors.New("forbidden")
+	guardHook := Hook{
+		Before: func(ctx context.Context, qc *QueryContext) (context.Context, error) {
+			if qc.Type == DELETE {
+				return ctx, errForbidden
+			}
+			return ctx, nil
+		},
+	}
+	wrapHook := Hook{
+		After: func(ctx context.Context, qc *QueryContext, res *QueryResult, duration time.Duration) {
+			if res.Err != nil && res.Err != errs.ErrNoRows {
+				res.Err = fmt.Errorf("wrapped: %w", res.Err)
+			}
+		},
+	}
+	db, err := openDB("mysql", mockDB, DBWithHooks(logHook, guardHook, wrapHook))
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `id`=? LIMIT ?;").
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	res, err := NewSelector[TestModel](db).Where(C("Id").EQ(1)).Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.Id)
+
+	mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;").
+		WillReturnError(errors.New("mock error"))
+	_, err = NewSelector[TestModel](db).GetMulti(context.Background())
+	assert.Equal(t, "wrapped: mock error", err.Error())
+
+	mock.ExpectExec("UPDATE `test_model` SET `age`=?;").
+		WithArgs(18).WillReturnResult(sqlmock.NewResult(0, 3))
+	affected, err := NewUpdater[TestModel](db).Set(Assign("Age", 18)).Exec(context.Background()).RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), affected)
+
+	// 被 Before 中断，不会执行 SQL
+	err = NewDeleter[TestModel](db).From(&TestModel{}).Exec(context.Background()).Err()
+	assert.True(t, errors.Is(err, errForbidden))
+
+	assert.Equal(t, []string{
+		"SELECT SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `id`=? LIMIT ?; [1 1] <nil>",
+		"SELECT SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`; [] wrapped: mock error",
+		"UPDATE UPDATE `test_model` SET `age`=?; [18] <nil>",
+		"DELETE DELETE FROM `test_model`; [] forbidden",
+	}, logs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/gotomicro/eorm/internal/errs"
+)
+
+// Polymorphic 根据鉴别列的值，将每一行数据转换为不同的类型
+type Polymorphic struct {
+	discriminator string
+	types         map[string]reflect.Type
+}
+
+// RegisterPolymorphic 注册鉴别列 discriminator 的值和类型的对应关系，
+// types 的值必须是结构体或者指向结构体的指针的类型。
+// 鉴别列的值会被转换为字符串之后再查找对应的类型
+func RegisterPolymorphic(discriminator string, types map[string]reflect.Type) Polymorphic {
+	ts := make(map[string]reflect.Type, len(types))
+	for k, typ := range types {
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		ts[k] = typ
+	}
+	return Polymorphic{
+		discriminator: discriminator,
+		types:         ts,
+	}
+}
+
+// GetMulti 执行查询，返回的每一个元素都是指向对应类型的指针
+// 结果集中必须包含鉴别列，并且每一种类型都必须能够接收结果集中所有的列
+func (p Polymorphic) GetMulti(ctx context.Context, sess session, q *Query) ([]any, error) {
+	c := sess.getCore()
+	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		rows, err := sess.queryContext(ctx, qc.q.SQL, qc.q.Args...)
+		if err != nil {
+			return &QueryResult{Err: err}
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+		res, err := p.scan(c, rows)
+		if err != nil {
+			return &QueryResult{Err: err}
+		}
+		return &QueryResult{Result: res}
+	}
+	ms := c.ms
+	for i := len(ms) - 1; i >= 0; i-- {
+		handler = ms[i](handler)
+	}
+	qr := handler(ctx, &QueryContext{q: q, Type: SELECT})
+	if qr.Err != nil {
+		return nil, qr.Err
+	}
+	return qr.Result.([]any), nil
+}
+
+// scan 分两步读取每一行：先读取鉴别列的值，再按照对应的类型读取整行
+func (p Polymorphic) scan(c core, rows *sql.Rows) ([]any, error) {
+	cs, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i, col := range cs {
+		if col == p.discriminator {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, errs.NewInvalidColumnError(p.discriminator)
+	}
+	// 不能使用 sql.RawBytes，否则同一行无法再次 Scan
+	vals := make([]any, len(cs))
+	for i := range vals {
+		vals[i] = new(any)
+	}
+	res := make([]any, 0, 16)
+	for rows.Next() {
+		if err = rows.Scan(vals...); err != nil {
+			return nil, err
+		}
+		var kind string
+		switch v := (*vals[idx].(*any)).(type) {
+		case []byte:
+			kind = string(v)
+		default:
+			kind = fmt.Sprint(v)
+		}
+		typ, ok := p.types[kind]
+		if !ok {
+			return nil, errs.NewUnknownDiscriminatorError(p.discriminator, kind)
+		}
+		tp := reflect.New(typ).Interface()
+		meta, err := c.metaRegistry.Get(tp)
+		if err != nil {
+			return nil, err
+		}
+		// 同一行可以多次 Scan，这一次按照具体的类型读取
+		if err = c.valCreator.NewBasicTypeValue(tp, meta).SetColumns(rows); err != nil {
+			return nil, err
+		}
+		res = append(res, tp)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type polymorphicCat struct {
+	Id    int64
+	Kind  string
+	Name  string
+	Lives int
+}
+
+type polymorphicDog struct {
+	Id    int64
+	Kind  string
+	Name  string
+	Lives *int
+}
+
+func TestPolymorphic_GetMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	p := RegisterPolymorphic("kind", map[string]reflect.Type{
+		"cat": reflect.TypeOf(polymorphicCat{}),
+		"dog": reflect.TypeOf(&polymorphicDog{}),
+	})
+	query := &Query{SQL: "SELECT `id`,`kind`,`name`,`lives` FROM `pet`;"}
+	cols := []string{"id", "kind", "name", "lives"}
+
+	testCases := []struct {
+		name     string
+		mockRows func()
+		p        Polymorphic
+		wantErr  error
+		wantRes  []any
+	}{
+		{
+			name: "two kinds",
+			mockRows: func() {
+				mock.ExpectQuery(query.SQL).WillReturnRows(sqlmock.NewRows(cols).
+					AddRow(1, "cat", "Tom", 9).
+					AddRow(2, "dog", "Spike", nil).
+					AddRow(3, "cat", "Kitty", 7))
+			},
+			p: p,
+			wantRes: []any{
+				&polymorphicCat{Id: 1, Kind: "cat", Name: "Tom", Lives: 9},
+				&polymorphicDog{Id: 2, Kind: "dog", Name: "Spike"},
+				&polymorphicCat{Id: 3, Kind: "cat", Name: "Kitty", Lives: 7},
+			},
+		},
+		{
+			name: "no rows",
+			mockRows: func() {
+				mock.ExpectQuery(query.SQL).WillReturnRows(sqlmock.NewRows(cols))
+			},
+			p:       p,
+			wantRes: []any{},
+		},
+		{
+			name: "query error",
+			mockRows: func() {
+				mock.ExpectQuery(query.SQL).WillReturnError(errors.New("mock error"))
+			},
+			p:       p,
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name: "unknown kind",
+			mockRows: func() {
+				mock.ExpectQuery(query.SQL).WillReturnRows(sqlmock.NewRows(cols).
+					AddRow(1, "bird", "Tweety", 1))
+			},
+			p:       p,
+			wantErr: errs.NewUnknownDiscriminatorError("kind", "bird"),
+		},
+		{
+			name: "missing discriminator",
+			mockRows: func() {
+				mock.ExpectQuery(query.SQL).WillReturnRows(sqlmock.NewRows(cols).
+					AddRow(1, "cat", "Tom", 9))
+			},
+			p: RegisterPolymorphic("type", map[string]reflect.Type{
+				"cat": reflect.TypeOf(polymorphicCat{}),
+			}),
+			wantErr: errs.NewInvalidColumnError("type"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRows()
+			res, err := tc.p.GetMulti(context.Background(), db, query)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
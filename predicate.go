@@ -27,10 +27,10 @@ var (
 	opEQ   = op{symbol: "=", text: "="}
 	opNEQ  = op{symbol: "!=", text: "!="}
 	opAdd  = op{symbol: "+", text: "+"}
+	opSub  = op{symbol: "-", text: "-"}
 	// opIn   = op{symbol: "IN", text: " IN "}
-	// opMinus = op{symbol:"-", text: "-"}
-	opMulti = op{symbol: "*", text: "*"}
-	// opDiv = op{symbol:"/", text: "/"}
+	opMulti   = op{symbol: "*", text: "*"}
+	opDiv     = op{symbol: "/", text: "/"}
 	opAnd     = op{symbol: "AND", text: " AND "}
 	opOr      = op{symbol: "OR", text: " OR "}
 	opNot     = op{symbol: "NOT", text: "NOT "}
@@ -39,7 +39,12 @@ var (
 	opFalse   = op{symbol: "FALSE", text: "FALSE"}
 	opLike    = op{symbol: "LIKE", text: " LIKE "}
 	opNotLike = op{symbol: "NOT LIKE", text: " NOT LIKE "}
-	opExist   = op{symbol: "EXIST", text: "EXIST "}
+	opILike   = op{symbol: "ILIKE", text: " ILIKE "}
+	// REGEXP 在不同方言中的写法不同，text 会在构造的时候根据方言改写
+	opRegexp    = op{symbol: "REGEXP", text: " REGEXP "}
+	opNotRegexp = op{symbol: "NOT REGEXP", text: " NOT REGEXP "}
+	opExist     = op{symbol: "EXISTS", text: "EXISTS "}
+	opBetween   = op{symbol: "BETWEEN", text: " BETWEEN "}
 )
 
 // Predicate will be used in Where Or Having
@@ -49,7 +54,22 @@ func (Predicate) expr() (string, error) {
 	return "", nil
 }
 
-// Exist indicates "Exist"
+// RawPredicate 等价于 Raw(expr, args...).AsPredicate()
+// 可以进一步通过 Using 声明引用的字段，让 eorm 校验这些字段
+func RawPredicate(expr string, args ...any) Predicate {
+	return Raw(expr, args...).AsPredicate()
+}
+
+// Using 声明 RawPredicate 中引用到的字段，构造 SQL 的时候会校验这些字段
+// 只对 RawPredicate 或者 RawExpr.AsPredicate 生成的 Predicate 生效，其余 Predicate 保持不变
+func (p Predicate) Using(fields ...string) Predicate {
+	if raw, ok := p.left.(RawExpr); ok && p.op.symbol == "" {
+		p.left = raw.Using(fields...)
+	}
+	return p
+}
+
+// Exist indicates "EXISTS"
 func Exist(sub Subquery) Predicate {
 	return Predicate{
 		op:    opExist,
@@ -66,6 +86,68 @@ func Not(p Predicate) Predicate {
 	}
 }
 
+// Or 将多个 Predicate 用 OR 连接起来，效果和 Where 里面用 AND 连接多个 Predicate 相对应
+// 例如 Or(C("Id").EQ(1), C("Id").EQ(2)) 会生成 (`id`=?) OR (`id`=?)
+// 没有传入 Predicate 的时候，会被认为是 false，被解释成 FALSE
+func Or(predicates ...Predicate) Predicate {
+	if len(predicates) == 0 {
+		return Predicate{
+			op: opFalse,
+		}
+	}
+	p := predicates[0]
+	for i := 1; i < len(predicates); i++ {
+		p = p.Or(predicates[i])
+	}
+	return p
+}
+
+// MatchMode 是 MySQL 全文搜索的模式
+type MatchMode string
+
+const (
+	// NaturalLanguageMode IN NATURAL LANGUAGE MODE，也是 MySQL 默认的模式
+	NaturalLanguageMode MatchMode = "IN NATURAL LANGUAGE MODE"
+	// BooleanMode IN BOOLEAN MODE
+	BooleanMode MatchMode = "IN BOOLEAN MODE"
+	// QueryExpansionMode WITH QUERY EXPANSION
+	QueryExpansionMode MatchMode = "WITH QUERY EXPANSION"
+)
+
+// MatchExpr 代表 MySQL 全文搜索中的 MATCH (cols)
+type MatchExpr struct {
+	fields []string
+	query  string
+	mode   MatchMode
+}
+
+// Match 指定全文搜索的列，fields 必须是模型的字段名，
+// 之后需要调用 Against 生成 Predicate，例如 Match("FirstName", "LastName").Against("tom", BooleanMode)
+// 只有 MySQL 支持，其余方言会在构造的时候返回错误
+func Match(fields ...string) MatchExpr {
+	return MatchExpr{fields: fields}
+}
+
+// Against 生成 MATCH (cols) AGAINST (? mode)，query 会作为参数
+func (m MatchExpr) Against(query string, mode MatchMode) Predicate {
+	m.query = query
+	m.mode = mode
+	return Predicate{left: m}
+}
+
+func (MatchExpr) expr() (string, error) {
+	return "", nil
+}
+
+// and 将 predicates 使用 AND 连接起来，predicates 不能为空
+func and(predicates []Predicate) Predicate {
+	p := predicates[0]
+	for i := 1; i < len(predicates); i++ {
+		p = p.And(predicates[i])
+	}
+	return p
+}
+
 // And indicates "AND"
 func (p Predicate) And(pred Predicate) Predicate {
 	return Predicate{
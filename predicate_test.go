@@ -19,7 +19,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPredicate_C(t *testing.T) {
@@ -52,6 +55,33 @@ func TestPredicate_C(t *testing.T) {
 			wantSql:  "SELECT `id` FROM `test_model` WHERE (`id`<?) OR (`id`>?);",
 			wantArgs: []interface{}{13, 4},
 		},
+		{
+			name: "top-level or",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(Or(C("Id").EQ(1), C("Id").EQ(2))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (`id`=?) OR (`id`=?);",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "top-level or with multiple predicates",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(Or(C("Id").EQ(1), C("Age").GT(18).And(C("FirstName").EQ("Tom")), C("Id").EQ(3)), C("Age").LT(60)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (((`id`=?) OR ((`age`>?) AND (`first_name`=?))) OR (`id`=?)) AND (`age`<?);",
+			wantArgs: []interface{}{1, 18, "Tom", 3, 60},
+		},
+		{
+			name: "top-level or with single predicate",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(Or(C("Id").EQ(1))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `id`=?;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "top-level or without predicates",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(Or()),
+			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+		},
 		{
 			name: "not",
 			builder: NewSelector[TestModel](db).Select(Columns("Id")).
@@ -152,6 +182,189 @@ type TestModel struct {
 	LastName  *sql.NullString
 }
 
+// TestModelView 对应一个只读的视图
+type TestModelView struct {
+	_         struct{} `eorm:"view"`
+	Id        int64
+	FirstName string
+}
+
+func TestPredicate_ILike(t *testing.T) {
+	db := memoryDB()
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "postgres",
+			builder:  NewSelector[TestModel](pg).Select(C("Id")).Where(C("FirstName").ILike("%zhang%")),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "first_name" ILIKE $1;`,
+			wantArgs: []interface{}{"%zhang%"},
+		},
+		{
+			name:     "lower fallback",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(C("FirstName").ILike("%Zhang%")),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE LOWER(`first_name`) LIKE LOWER(?);",
+			wantArgs: []interface{}{"%Zhang%"},
+		},
+		{
+			name: "combined",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Where(C("Age").GT(18), C("FirstName").ILike("%zhang%").Or(C("LastName").ILike("%zhang%"))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (`age`>?) AND ((LOWER(`first_name`) LIKE LOWER(?)) OR (LOWER(`last_name`) LIKE LOWER(?)));",
+			wantArgs: []interface{}{18, "%zhang%", "%zhang%"},
+		},
+		{
+			name: "having",
+			builder: NewSelector[TestModel](pg).Select(C("FirstName"), Count("Id")).
+				GroupBy("FirstName").Having(C("FirstName").ILike("t%")),
+			wantSql:  `SELECT "first_name",COUNT("id") FROM "test_model" GROUP BY "first_name" HAVING "first_name" ILIKE $1;`,
+			wantArgs: []interface{}{"t%"},
+		},
+		{
+			name: "having fallback",
+			builder: NewSelector[TestModel](db).Select(C("FirstName"), Count("Id")).
+				GroupBy("FirstName").Having(C("FirstName").ILike("t%")),
+			wantSql:  "SELECT `first_name`,COUNT(`id`) FROM `test_model` GROUP BY `first_name` HAVING LOWER(`first_name`) LIKE LOWER(?);",
+			wantArgs: []interface{}{"t%"},
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestPredicate_Regexp(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "mysql",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(C("FirstName").Regexp("^zh")),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `first_name` REGEXP ?;",
+			wantArgs: []interface{}{"^zh"},
+		},
+		{
+			name:     "mysql not",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(C("Age").GT(18), C("FirstName").NotRegexp("^zh")),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (`age`>?) AND (`first_name` NOT REGEXP ?);",
+			wantArgs: []interface{}{18, "^zh"},
+		},
+		{
+			name:     "postgres",
+			builder:  NewSelector[TestModel](pg).Select(C("Id")).Where(C("FirstName").Regexp("^zh")),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "first_name" ~ $1;`,
+			wantArgs: []interface{}{"^zh"},
+		},
+		{
+			name:     "postgres not",
+			builder:  NewSelector[TestModel](pg).Select(C("Id")).Where(C("FirstName").NotRegexp("^zh")),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "first_name" !~ $1;`,
+			wantArgs: []interface{}{"^zh"},
+		},
+		{
+			name:    "sqlite",
+			builder: NewSelector[TestModel](memoryDB()).Select(C("Id")).Where(C("FirstName").Regexp("^zh")),
+			wantErr: errs.NewUnsupportedFeatureError("SQLite", "REGEXP"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name: "boolean mode",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				Where(Match("FirstName", "LastName").Against("+tom -jerry", BooleanMode)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE MATCH (`first_name`,`last_name`) AGAINST (? IN BOOLEAN MODE);",
+			wantArgs: []interface{}{"+tom -jerry"},
+		},
+		{
+			name: "natural language mode",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				Where(C("Age").GT(18), Match("FirstName").Against("tom", NaturalLanguageMode)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (`age`>?) AND (MATCH (`first_name`) AGAINST (? IN NATURAL LANGUAGE MODE));",
+			wantArgs: []interface{}{18, "tom"},
+		},
+		{
+			name: "query expansion",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				Where(Match("FirstName").Against("tom", QueryExpansionMode)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE MATCH (`first_name`) AGAINST (? WITH QUERY EXPANSION);",
+			wantArgs: []interface{}{"tom"},
+		},
+		{
+			name: "default mode",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				Where(Match("FirstName").Against("tom", "")),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE MATCH (`first_name`) AGAINST (?);",
+			wantArgs: []interface{}{"tom"},
+		},
+		{
+			name: "invalid column",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				Where(Match("FirstName", "Invalid").Against("tom", BooleanMode)),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name: "without columns",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				Where(Match().Against("tom", BooleanMode)),
+			wantErr: errs.ErrMatchWithoutColumns,
+		},
+		{
+			name: "postgres",
+			builder: NewSelector[TestModel](postgresDB()).Select(C("Id")).
+				Where(Match("FirstName").Against("tom", BooleanMode)),
+			wantErr: errs.NewUnsupportedFeatureError("PostgreSQL", "MATCH AGAINST"),
+		},
+		{
+			name: "sqlite",
+			builder: NewSelector[TestModel](memoryDB()).Select(C("Id")).
+				Where(Match("FirstName").Against("tom", BooleanMode)),
+			wantErr: errs.NewUnsupportedFeatureError("SQLite", "MATCH AGAINST"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
 func (TestModel) CreateSQL() string {
 	return `
 CREATE TABLE IF NOT EXISTS test_model(
@@ -189,6 +402,15 @@ func ExamplePredicate_And() {
 	// Args: []interface {}{18, 100}
 }
 
+func ExampleOr() {
+	db := memoryDB()
+	query, _ := NewSelector[TestModel](db).Select(Columns("Id")).Where(Or(C("Id").EQ(18), C("Age").GT(100))).Build()
+	fmt.Println(query.string())
+	// Output:
+	// SQL: SELECT `id` FROM `test_model` WHERE (`id`=?) OR (`age`>?);
+	// Args: []interface {}{18, 100}
+}
+
 func ExamplePredicate_Or() {
 	db := memoryDB()
 	query, _ := NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").EQ(18).Or(C("Age").GT(100))).Build()
@@ -0,0 +1,70 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"strings"
+)
+
+type queryTagKey struct{}
+
+type queryTag struct {
+	key   string
+	value string
+}
+
+// WithQueryTag 在 ctx 中加入一个标签，例如 trace id 或者 request id
+// 使用返回的 ctx 执行的语句会在开头加上注释 /* key=value */，多个标签按照加入的顺序用逗号分隔
+// 标签中的 * 会被去掉，以免提前结束注释
+func WithQueryTag(ctx context.Context, key string, value string) context.Context {
+	tags, _ := ctx.Value(queryTagKey{}).([]queryTag)
+	// 复制一份，避免影响父 ctx 中的标签
+	newTags := make([]queryTag, len(tags), len(tags)+1)
+	copy(newTags, tags)
+	newTags = append(newTags, queryTag{key: sanitizeQueryTag(key), value: sanitizeQueryTag(value)})
+	return context.WithValue(ctx, queryTagKey{}, newTags)
+}
+
+// tagQuery 将 ctx 中的标签作为注释加到 query 的开头
+func tagQuery(ctx context.Context, query string) string {
+	tags, _ := ctx.Value(queryTagKey{}).([]queryTag)
+	if len(tags) == 0 {
+		return query
+	}
+	var sb strings.Builder
+	sb.Grow(len(query) + 16*len(tags))
+	sb.WriteString("/* ")
+	for i, tag := range tags {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(tag.key)
+		sb.WriteByte('=')
+		sb.WriteString(tag.value)
+	}
+	sb.WriteString(" */ ")
+	sb.WriteString(query)
+	return sb.String()
+}
+
+func sanitizeQueryTag(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '*' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, s)
+}
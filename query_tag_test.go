@@ -0,0 +1,57 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithQueryTag(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	ctx := WithQueryTag(context.Background(), "request_id", "abc")
+	mock.ExpectQuery("/* request_id=abc */ SELECT `id` FROM `test_model` WHERE `id`=? LIMIT ?;").
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	_, err = NewSelector[TestModel](db).Select(C("Id")).Where(C("Id").EQ(1)).Get(ctx)
+	require.NoError(t, err)
+
+	traceCtx := WithQueryTag(ctx, "trace_id", "*/ DROP TABLE `user`; /*")
+	mock.ExpectQuery("/* request_id=abc,trace_id=/ DROP TABLE `user`; / */ SELECT `id` FROM `test_model`;").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	_, err = NewSelector[TestModel](db).Select(C("Id")).GetMulti(traceCtx)
+	require.NoError(t, err)
+
+	mock.ExpectExec("/* request_id=abc */ UPDATE `test_model` SET `age`=? WHERE `id`=?;").
+		WithArgs(18, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	res := NewUpdater[TestModel](db).Set(Assign("Age", 18)).Where(C("Id").EQ(1)).Exec(ctx)
+	require.NoError(t, res.Err())
+
+	// 没有标签的时候，语句保持原样
+	mock.ExpectExec("DELETE FROM `test_model` WHERE `id`=?;").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	res = NewDeleter[TestModel](db).From(&TestModel{}).Where(C("Id").EQ(1)).Exec(context.Background())
+	require.NoError(t, res.Err())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
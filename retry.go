@@ -0,0 +1,107 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+	pgLockNotAvailable   = "55P03"
+	pgDeadlockDetected   = "40P01"
+)
+
+// RetryPolicy 是遇到锁等待超时或者死锁的时候的重试策略
+type RetryPolicy struct {
+	// MaxAttempts 是最多执行的次数，包括第一次执行
+	MaxAttempts int
+	// Backoff 返回第 attempt 次重试之前需要等待的时间，attempt 从 1 开始
+	// 为 nil 的时候立刻重试
+	Backoff func(attempt int) time.Duration
+	// Retryable 判断 err 是否需要重试，为 nil 的时候使用 IsLockError
+	Retryable func(err error) bool
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsLockError(err)
+}
+
+// wait 在第 attempt 次重试之前等待，如果 ctx 先结束了则返回 ctx 的错误
+func (p *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	if p.Backoff == nil {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// DBWithRetryPolicy 设置遇到锁等待超时或者死锁的时候的重试策略
+// DB.DoTx 会回滚事务并且重新执行整个事务，
+// Selector.GetForUpdate 只会在事务中重试 MySQL 的锁等待超时，参考 GetForUpdate 的说明
+func DBWithRetryPolicy(p RetryPolicy) DBOption {
+	return func(db *DB) {
+		db.retryPolicy = &p
+	}
+}
+
+// IsLockError 判断 err 是不是锁等待超时或者死锁
+// MySQL 是 1205 和 1213，PostgreSQL 是 55P03 和 40P01。
+// PostgreSQL 的错误需要实现 SQLState() string 方法，pgx 和 lib/pq 的错误都实现了
+func IsLockError(err error) bool {
+	if code, ok := mysqlErrorNumber(err); ok {
+		return code == mysqlLockWaitTimeout || code == mysqlDeadlock
+	}
+	state := sqlState(err)
+	return state == pgLockNotAvailable || state == pgDeadlockDetected
+}
+
+// isStatementRetryable 判断 err 是否只回滚了出错的语句，也就是事务还可以继续使用
+// 只有 MySQL 的锁等待超时满足条件，死锁会回滚整个事务，
+// 而 PostgreSQL 的事务在出错之后就不能再执行任何语句了
+func isStatementRetryable(err error) bool {
+	code, ok := mysqlErrorNumber(err)
+	return ok && code == mysqlLockWaitTimeout
+}
+
+func mysqlErrorNumber(err error) (uint16, bool) {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		return me.Number, true
+	}
+	return 0, false
+}
+
+func sqlState(err error) string {
+	var se interface{ SQLState() string }
+	if errors.As(err, &se) {
+		return se.SQLState()
+	}
+	return ""
+}
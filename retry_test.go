@@ -0,0 +1,243 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelector_GetForUpdate(t *testing.T) {
+	lockErr := &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"}
+	deadlockErr := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}
+	query := "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `id`=? LIMIT ? FOR UPDATE;"
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		Backoff: func(attempt int) time.Duration {
+			return time.Millisecond * time.Duration(attempt)
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		inTx    bool
+		mock    func(mock sqlmock.Sqlmock)
+		wantRes *TestModel
+		wantErr error
+	}{
+		{
+			name: "lock timeout then success",
+			inTx: true,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(lockErr)
+				mock.ExpectQuery(query).WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(1, "Tom"))
+			},
+			wantRes: &TestModel{Id: 1, FirstName: "Tom"},
+		},
+		{
+			name: "exceed max attempts",
+			inTx: true,
+			mock: func(mock sqlmock.Sqlmock) {
+				for i := 0; i < 3; i++ {
+					mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(lockErr)
+				}
+			},
+			wantErr: lockErr,
+		},
+		{
+			// 死锁会回滚整个事务，在同一个事务中重试没有意义
+			name: "deadlock",
+			inTx: true,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(deadlockErr)
+			},
+			wantErr: deadlockErr,
+		},
+		{
+			name: "not lock error",
+			inTx: true,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(errors.New("bad conn"))
+			},
+			wantErr: errors.New("bad conn"),
+		},
+		{
+			name: "without tx",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(lockErr)
+			},
+			wantErr: lockErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB, DBWithRetryPolicy(policy))
+			require.NoError(t, err)
+
+			var sess session = db
+			if tc.inTx {
+				mock.ExpectBegin()
+				tx, err := db.BeginTx(context.Background(), nil)
+				require.NoError(t, err)
+				sess = tx
+			}
+			tc.mock(mock)
+			res, err := NewSelector[TestModel](sess).Where(C("Id").EQ(1)).GetForUpdate(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, res)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIsLockError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil"},
+		{name: "mysql lock wait timeout", err: &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"}, want: true},
+		{name: "mysql deadlock", err: &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}, want: true},
+		{name: "wrapped mysql deadlock", err: fmt.Errorf("select: %w", &mysql.MySQLError{Number: 1213}), want: true},
+		{name: "mysql other", err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}},
+		{name: "postgres deadlock", err: sqlStateError("40P01"), want: true},
+		{name: "postgres lock timeout", err: sqlStateError("55P03"), want: true},
+		{name: "postgres other", err: sqlStateError("23505")},
+		// 只是错误信息相似的错误不会被当作锁错误
+		{name: "message only", err: errors.New("Error 1205: Lock wait timeout exceeded; try restarting transaction")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsLockError(tc.err))
+		})
+	}
+}
+
+// sqlStateError 模拟 pgx 和 lib/pq 的错误
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "ERROR (SQLSTATE " + string(e) + ")"
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
+func TestDB_DoTx(t *testing.T) {
+	deadlockErr := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}
+	query := "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `id`=? LIMIT ? FOR UPDATE;"
+	policy := RetryPolicy{MaxAttempts: 2}
+
+	testCases := []struct {
+		name    string
+		opts    []DBOption
+		mock    func(mock sqlmock.Sqlmock)
+		wantRes *TestModel
+		wantErr error
+	}{
+		{
+			name: "commit",
+			opts: []DBOption{DBWithRetryPolicy(policy)},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(1, "Tom"))
+				mock.ExpectCommit()
+			},
+			wantRes: &TestModel{Id: 1, FirstName: "Tom"},
+		},
+		{
+			name: "deadlock then success",
+			opts: []DBOption{DBWithRetryPolicy(policy)},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(deadlockErr)
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(1, "Tom"))
+				mock.ExpectCommit()
+			},
+			wantRes: &TestModel{Id: 1, FirstName: "Tom"},
+		},
+		{
+			name: "exceed max attempts",
+			opts: []DBOption{DBWithRetryPolicy(policy)},
+			mock: func(mock sqlmock.Sqlmock) {
+				for i := 0; i < 2; i++ {
+					mock.ExpectBegin()
+					mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(deadlockErr)
+					mock.ExpectRollback()
+				}
+			},
+			wantErr: deadlockErr,
+		},
+		{
+			name: "not lock error",
+			opts: []DBOption{DBWithRetryPolicy(policy)},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(errors.New("bad conn"))
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("bad conn"),
+		},
+		{
+			name: "without retry policy",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(1, 1).WillReturnError(deadlockErr)
+				mock.ExpectRollback()
+			},
+			wantErr: deadlockErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB, tc.opts...)
+			require.NoError(t, err)
+			tc.mock(mock)
+
+			var res *TestModel
+			err = db.DoTx(context.Background(), nil, func(ctx context.Context, tx *Tx) error {
+				var err error
+				res, err = NewSelector[TestModel](tx).Where(C("Id").EQ(1)).GetForUpdate(ctx)
+				return err
+			})
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, res)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,11 +1,11 @@
 create database if not exists `integration_test`;
 create table if not exists `integration_test`.`simple_struct`(
-    `id` bigint auto_increment,
+    `int_c` bigint auto_increment,
     bool smallint not null,
     bool_ptr smallint,
     `int` int not null,
     int_ptr int,
-    `int8` smallint not null,
+    `int8_c` smallint not null,
     int8_ptr smallint,
     int16 int not null,
     int16_ptr int,
@@ -37,7 +37,7 @@ create table if not exists `integration_test`.`simple_struct`(
     null_time_ptr datetime,
     null_float64_ptr float,
     json_column varchar(2048),
-    primary key (`id`)
+    primary key (`int_c`)
 );
 
 create table if not exists `integration_test`.`combined_model`
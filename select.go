@@ -16,7 +16,11 @@ package eorm
 
 import (
 	"context"
+	"database/sql"
+	"reflect"
+	"strings"
 
+	"github.com/gotomicro/eorm/internal/dialect"
 	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/gotomicro/eorm/internal/model"
 	"github.com/valyala/bytebufferpool"
@@ -32,11 +36,42 @@ type Selector[T any] struct {
 	distinct bool
 	having   []Predicate
 	groupBy  []string
-	orderBy  []OrderBy
-	offset   int
-	limit    int
+	// groupByExprs 是 GroupByExpr 指定的表达式，排在 groupBy 之后
+	groupByExprs []Expr
+	orderBy      []OrderBy
+	offset       int
+	limit        int
+	// noLimit 为 true 的时候不使用 DB 上配置的默认 LIMIT
+	noLimit bool
+	ctes    []cte
+	// windowAliases 是窗口函数的别名，它们不能在 HAVING 中使用
+	windowAliases map[string]struct{}
+	// nulls 是 NULL 值在排序中的位置，只会作用在可以为 NULL 的列上
+	nulls NullsOrdering
+	// calcFoundRows 表示使用 MySQL 的 SQL_CALC_FOUND_ROWS
+	calcFoundRows bool
+	forUpdate     bool
+	forShare      bool
+	// totalAlias 不为空的时候，会在查询的列后面加上 COUNT(*) OVER() AS totalAlias
+	totalAlias string
+	// withDeleted 为 true 的时候不会过滤软删除的数据
+	withDeleted bool
+	// softDeleteWhere 是无法放在 ON 里面的软删除过滤条件，例如 USING 和 CROSS JOIN
+	softDeleteWhere []Predicate
 }
 
+// cte 是 WITH 子句中的一个公共表表达式
+type cte struct {
+	name    string
+	q       QueryBuilder
+	columns []string
+	// materialized 是 PostgreSQL 的 MATERIALIZED 或者 NOT MATERIALIZED 提示
+	materialized string
+}
+
+// maxPreallocRows 是 GetMultiVal 预分配结果的最大行数
+const maxPreallocRows = 64
+
 // NewSelector 创建一个 Selector
 func NewSelector[T any](sess session) *Selector[T] {
 	return &Selector[T]{
@@ -67,10 +102,22 @@ func (s *Selector[T]) Build() (*Query, error) {
 	if err != nil {
 		return nil, err
 	}
+	s.grow(s.estimate())
+	if len(s.ctes) > 0 {
+		if err = s.buildWith(); err != nil {
+			return nil, err
+		}
+	}
 	s.writeString("SELECT ")
 	if s.distinct {
 		s.writeString("DISTINCT ")
 	}
+	if s.calcFoundRows {
+		if !s.features().CalcFoundRows {
+			return nil, errs.NewUnsupportedFeatureError(s.dialect.Name(), "SQL_CALC_FOUND_ROWS")
+		}
+		s.writeString("SQL_CALC_FOUND_ROWS ")
+	}
 	if len(s.columns) == 0 {
 		s.buildAllColumns()
 	} else {
@@ -79,20 +126,40 @@ func (s *Selector[T]) Build() (*Query, error) {
 			return nil, err
 		}
 	}
+	if s.totalAlias != "" {
+		if s.distinct {
+			return nil, errs.ErrTotalWindowWithDistinct
+		}
+		s.writeString(",COUNT(*) OVER()")
+		s.buildAs(s.totalAlias)
+		if s.aliases == nil {
+			s.aliases = make(map[string]struct{}, 1)
+		}
+		s.aliases[s.totalAlias] = struct{}{}
+	}
 	s.writeString(" FROM ")
+	if s.maxJoinDepth > 0 {
+		if depth := joinDepth(s.table); depth > s.maxJoinDepth {
+			return nil, errs.NewJoinTooDeepError(depth, s.maxJoinDepth)
+		}
+	}
 	if err = s.buildTable(s.table); err != nil {
 		return nil, err
 	}
-	if len(s.where) > 0 {
+	where, err := s.whereWithSoftDelete()
+	if err != nil {
+		return nil, err
+	}
+	if len(where) > 0 {
 		s.writeString(" WHERE ")
-		err = s.buildPredicates(s.where)
+		err = s.buildPredicates(where)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// group by
-	if len(s.groupBy) > 0 {
+	if len(s.groupBy) > 0 || len(s.groupByExprs) > 0 {
 		err = s.buildGroupBy()
 		if err != nil {
 			return nil, err
@@ -101,6 +168,9 @@ func (s *Selector[T]) Build() (*Query, error) {
 
 	// order by
 	if len(s.orderBy) > 0 {
+		if err = s.checkDistinctOrderBy(); err != nil {
+			return nil, err
+		}
 		err = s.buildOrderBy()
 		if err != nil {
 			return nil, err
@@ -109,6 +179,11 @@ func (s *Selector[T]) Build() (*Query, error) {
 
 	// having
 	if len(s.having) > 0 {
+		for _, p := range s.having {
+			if err = s.checkWindowAlias(p); err != nil {
+				return nil, err
+			}
+		}
 		s.writeString(" HAVING ")
 		err = s.buildPredicates(s.having)
 		if err != nil {
@@ -116,74 +191,360 @@ func (s *Selector[T]) Build() (*Query, error) {
 		}
 	}
 
-	if s.offset > 0 {
-		s.writeString(" OFFSET ")
-		s.parameter(s.offset)
+	if s.limit > 0 || s.offset > 0 {
+		limitOffset, args := s.dialect.LimitOffset(s.limit, s.offset)
+		s.buildRawExpr(Raw(limitOffset, args...))
 	}
 
-	if s.limit > 0 {
-		s.writeString(" LIMIT ")
-		s.parameter(s.limit)
+	if err = s.buildLock(); err != nil {
+		return nil, err
+	}
+	if err = s.checkArgs(); err != nil {
+		return nil, err
 	}
 	s.end()
 	return &Query{SQL: s.buffer.String(), Args: s.args}, nil
 }
 
+// estimate 根据列和查询条件的数量预估 SQL 的长度和参数的个数
+func (s *Selector[T]) estimate() (int, int) {
+	// SELECT 、FROM 、表名以及结尾的 ;
+	size := 32 + len(s.meta.TableName)
+	if len(s.columns) == 0 {
+		for _, c := range s.meta.Columns {
+			size += len(c.ColumnName) + 3
+		}
+	} else {
+		size += len(s.columns) * 16
+	}
+	ws, wc := estimatePredicates(s.where)
+	hs, hc := estimatePredicates(s.having)
+	size += ws + hs + (len(s.groupBy)+len(s.orderBy))*16
+	cnt := wc + hc
+	if s.offset > 0 {
+		size, cnt = size+10, cnt+1
+	}
+	if s.limit > 0 {
+		size, cnt = size+9, cnt+1
+	}
+	return size, cnt
+}
+
+// buildWith 构造 WITH 子句，公共表表达式的参数会排在外层查询的参数之前
+func (s *Selector[T]) buildWith() error {
+	s.writeString("WITH ")
+	for i, c := range s.ctes {
+		if i > 0 {
+			s.comma()
+		}
+		s.quote(c.name)
+		if len(c.columns) > 0 {
+			s.writeString(" (")
+			for j, col := range c.columns {
+				if j > 0 {
+					s.comma()
+				}
+				s.quote(col)
+			}
+			s.writeByte(')')
+		}
+		s.writeString(" AS ")
+		// 只有 PostgreSQL 之类的方言支持，其余方言直接忽略
+		if c.materialized != "" && s.features().MaterializedCTE {
+			s.writeString(c.materialized)
+			s.space()
+		}
+		if err := s.buildSubquery(Subquery{q: c.q}, false); err != nil {
+			return err
+		}
+	}
+	s.space()
+	return nil
+}
+
+// checkWindowAlias 检查表达式中是否引用了窗口函数的别名
+func (s *Selector[T]) checkWindowAlias(e Expr) error {
+	switch expr := e.(type) {
+	case Column:
+		if _, ok := s.windowAliases[expr.name]; ok && expr.table == nil {
+			return errs.NewWindowAliasInHavingError(expr.name)
+		}
+	case Predicate:
+		return s.checkWindowAlias(binaryExpr(expr))
+	case MathExpr:
+		return s.checkWindowAlias(binaryExpr(expr))
+	case binaryExpr:
+		if err := s.checkWindowAlias(expr.left); err != nil {
+			return err
+		}
+		return s.checkWindowAlias(expr.right)
+	}
+	return nil
+}
+
+// containsWindow 判断表达式中是否使用了窗口函数
+func containsWindow(e Expr) bool {
+	switch expr := e.(type) {
+	case WindowFunc:
+		return true
+	case MathExpr:
+		return containsWindow(binaryExpr(expr))
+	case binaryExpr:
+		return containsWindow(expr.left) || containsWindow(expr.right)
+	}
+	return false
+}
+
+// isAggregation 判断查询是否使用了 GROUP BY、DISTINCT 或者在查询列表中使用了聚合函数
+func (s *Selector[T]) isAggregation() bool {
+	if s.distinct || len(s.groupBy) > 0 || len(s.groupByExprs) > 0 {
+		return true
+	}
+	for _, c := range s.columns {
+		switch c.(type) {
+		case Aggregate, GroupConcatExpr, BoolAggregate:
+			return true
+		}
+	}
+	return false
+}
+
+// buildLock 构造 FOR UPDATE 或者共享锁
+func (s *Selector[T]) buildLock() error {
+	if s.forUpdate && s.forShare {
+		return errs.ErrLockModeConflict
+	}
+	if (s.forUpdate || s.forShare) && s.isAggregation() {
+		return errs.ErrLockWithAggregation
+	}
+	if s.forUpdate {
+		s.writeString(" FOR UPDATE")
+	}
+	if s.forShare {
+		if s.features().ForShare {
+			s.writeString(" FOR SHARE")
+		} else {
+			s.writeString(" LOCK IN SHARE MODE")
+		}
+	}
+	return nil
+}
+
 func (s *Selector[T]) buildTable(table TableReference) error {
 	switch tab := table.(type) {
 	case nil:
-		s.quote(s.meta.TableName)
+		s.quoteTable(s.meta.TableName)
 	case Table:
 		m, err := s.metaRegistry.Get(tab.entity)
 		if err != nil {
 			return err
 		}
-		s.quote(m.TableName)
+		s.quoteTable(m.TableName)
 		if tab.alias != "" {
 			_, _ = s.buffer.WriteString(" AS ")
 			s.quote(tab.alias)
 		}
+		if tab.hint != nil {
+			return s.buildIndexHint(tab.hint)
+		}
 	case Join:
 		return s.buildJoin(tab)
 	case Subquery:
 		return s.buildSubquery(tab, true)
+	case CTE:
+		s.quote(tab.name)
+		s.buildAs(tab.alias)
+	case JSONTableRef:
+		return s.buildJSONTable(tab)
 	default:
 		return errs.NewErrUnsupportedExpressionType(tab)
 	}
 	return nil
 }
 
+// buildJSONTable 构造 JSONTable，MySQL 使用 JSON_TABLE，PostgreSQL 使用 jsonb_to_recordset
+func (s *Selector[T]) buildJSONTable(tab JSONTableRef) error {
+	if tab.alias == "" {
+		return errs.ErrJSONTableWithoutAlias
+	}
+	switch s.features().JSONTable {
+	case dialect.JSONTableFunc:
+		s.writeString("JSON_TABLE(")
+		if err := s.builder.buildColumn(tab.col.table, tab.col.name); err != nil {
+			return err
+		}
+		s.comma()
+		s.writeJSONPath(tab.path)
+		s.writeString(" COLUMNS(")
+		for i, c := range tab.columns {
+			if i > 0 {
+				s.comma()
+			}
+			s.quote(c.name)
+			s.space()
+			s.writeString(c.typ)
+			s.writeString(" PATH ")
+			s.writeJSONPath(c.path)
+		}
+		s.writeString("))")
+		s.buildAs(tab.alias)
+	case dialect.JSONTableRecordset:
+		if tab.path != "$[*]" {
+			return errs.NewUnsupportedFeatureError(s.dialect.Name(), "JSONTable 路径 "+tab.path)
+		}
+		s.writeString("jsonb_to_recordset(")
+		if err := s.builder.buildColumn(tab.col.table, tab.col.name); err != nil {
+			return err
+		}
+		s.writeByte(')')
+		s.buildAs(tab.alias)
+		s.writeByte('(')
+		for i, c := range tab.columns {
+			if c.path != "$."+c.name {
+				return errs.NewUnsupportedFeatureError(s.dialect.Name(), "JSONTable 路径 "+c.path)
+			}
+			if i > 0 {
+				s.comma()
+			}
+			s.quote(c.name)
+			s.space()
+			s.writeString(c.typ)
+		}
+		s.writeByte(')')
+	default:
+		return errs.NewUnsupportedFeatureError(s.dialect.Name(), "JSONTable")
+	}
+	return nil
+}
+
+// writeJSONPath 将 JSON 路径作为字符串字面量写入，JSON_TABLE 的路径不能使用占位符
+func (s *Selector[T]) writeJSONPath(path string) {
+	s.writeByte('\'')
+	s.writeString(strings.ReplaceAll(path, "'", "''"))
+	s.writeByte('\'')
+}
+
+// buildIndexHint 构造索引提示，只有 MySQL 支持
+func (s *Selector[T]) buildIndexHint(hint *indexHint) error {
+	if !s.features().IndexHints {
+		return errs.NewUnsupportedFeatureError(s.dialect.Name(), hint.typ)
+	}
+	s.space()
+	s.writeString(hint.typ)
+	s.writeString(" (")
+	for i, idx := range hint.indexes {
+		if i > 0 {
+			s.comma()
+		}
+		s.quote(idx)
+	}
+	s.writeByte(')')
+	return nil
+}
+
 func (s *Selector[T]) buildOrderBy() error {
 	s.writeString(" ORDER BY ")
 	for i, ob := range s.orderBy {
 		if i > 0 {
 			s.comma()
 		}
+		if ob.expr != nil {
+			if ob.nulls != "" && !s.features().NullsOrdering {
+				if err := s.buildIsNullOrder(ob.expr, ob.nulls); err != nil {
+					return err
+				}
+			}
+			if err := s.buildExpr(ob.expr); err != nil {
+				return err
+			}
+			s.space()
+			s.writeString(ob.order)
+			s.buildNullsOrdering(ob.nulls)
+			continue
+		}
+		// 在 OrderBy 上指定的优先，否则 WithNullsOrdering 只作用在单个可以为 NULL 的列上
+		nulls := ob.nulls
+		// MySQL 的改写会作用在每一列上，而 NULLS FIRST/LAST 只作用在最后一列上，两者无法保持一致
+		if nulls != "" && len(ob.fields) > 1 {
+			return errs.ErrNullsOrderingMultipleFields
+		}
 		for _, c := range ob.fields {
-			cMeta, ok := s.meta.FieldMap[c]
-			if !ok {
-				return errs.NewInvalidFieldError(c)
+			// 查询列表中定义的别名，例如 Avg("Age").As("avg_age")，直接使用别名排序
+			if _, ok := s.aliases[c]; ok && len(ob.vals) == 0 {
+				if nulls != "" && !s.features().NullsOrdering {
+					_ = s.buildIsNullOrder(Raw(s.dialect.Quote(c)), nulls)
+				}
+				s.quote(c)
+				continue
+			}
+			cMeta, err := fieldMeta(s.meta, c)
+			if err != nil {
+				return err
+			}
+			if len(ob.vals) > 0 {
+				s.buildOrderByField(cMeta.ColumnName, ob.vals)
+				continue
+			}
+			if nulls == "" && len(ob.fields) == 1 && cMeta.Nullable() {
+				nulls = s.nulls
+			}
+			if nulls != "" && !s.features().NullsOrdering {
+				_ = s.buildIsNullOrder(Raw(s.dialect.Quote(cMeta.ColumnName)), nulls)
 			}
 			s.quote(cMeta.ColumnName)
 		}
 		s.space()
 		s.writeString(ob.order)
+		if len(ob.vals) == 0 {
+			s.buildNullsOrdering(nulls)
+		}
+	}
+	return nil
+}
+
+// buildIsNullOrder 构造 ISNULL(expr) [DESC],
+// MySQL 之类的方言不支持 NULLS FIRST/LAST，NULL 被认为是最小的值，所以先按照 ISNULL(expr) 排序
+func (s *Selector[T]) buildIsNullOrder(expr Expr, nulls NullsOrdering) error {
+	s.writeString("ISNULL(")
+	if err := s.buildExpr(expr); err != nil {
+		return err
+	}
+	s.writeByte(')')
+	if nulls == NullsFirst {
+		s.writeString(" DESC")
 	}
+	s.comma()
 	return nil
 }
 
+// buildNullsOrdering 在支持 NULLS FIRST/LAST 的方言上构造 NULLS FIRST/LAST
+func (s *Selector[T]) buildNullsOrdering(nulls NullsOrdering) {
+	if nulls != "" && s.features().NullsOrdering {
+		s.space()
+		s.writeString(string(nulls))
+	}
+}
+
 func (s *Selector[T]) buildGroupBy() error {
 	s.writeString(" GROUP BY ")
 	for i, gb := range s.groupBy {
-		cMeta, ok := s.meta.FieldMap[gb]
-		if !ok {
-			return errs.NewInvalidFieldError(gb)
+		cMeta, err := fieldMeta(s.meta, gb)
+		if err != nil {
+			return err
 		}
 		if i > 0 {
 			s.comma()
 		}
 		s.quote(cMeta.ColumnName)
 	}
+	for i, e := range s.groupByExprs {
+		if i > 0 || len(s.groupBy) > 0 {
+			s.comma()
+		}
+		if err := s.buildExpr(e); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -228,26 +589,116 @@ func (s *Selector[T]) buildSelectedList() error {
 				return err
 			}
 		case RawExpr:
+			if err := s.checkRawFields(expr); err != nil {
+				return err
+			}
 			s.buildRawExpr(expr)
+		case Subquery:
+			if err := s.selectSubquery(expr); err != nil {
+				return err
+			}
+		case GroupConcatExpr:
+			if err := s.buildGroupConcat(expr); err != nil {
+				return err
+			}
+			if expr.alias != "" {
+				s.aliases[expr.alias] = struct{}{}
+				s.buildAs(expr.alias)
+			}
+		case BoolAggregate:
+			if err := s.buildBoolAggregate(expr); err != nil {
+				return err
+			}
+			if expr.alias != "" {
+				s.aliases[expr.alias] = struct{}{}
+				s.buildAs(expr.alias)
+			}
+		case DateTruncExpr:
+			if err := s.buildDateTrunc(expr); err != nil {
+				return err
+			}
+			if expr.alias != "" {
+				s.aliases[expr.alias] = struct{}{}
+				s.buildAs(expr.alias)
+			}
+		case FuncExpr:
+			if err := s.buildFuncExpr(expr); err != nil {
+				return err
+			}
+			if expr.alias != "" {
+				s.aliases[expr.alias] = struct{}{}
+				s.buildAs(expr.alias)
+			}
+		case WindowFunc:
+			if err := s.buildWindowFunc(expr); err != nil {
+				return err
+			}
+			if expr.alias != "" {
+				s.aliases[expr.alias] = struct{}{}
+				if s.windowAliases == nil {
+					s.windowAliases = make(map[string]struct{}, 1)
+				}
+				s.windowAliases[expr.alias] = struct{}{}
+				s.buildAs(expr.alias)
+			}
+		case aliasedExpr:
+			if err := s.buildExpr(expr.expr); err != nil {
+				return err
+			}
+			s.aliases[expr.alias] = struct{}{}
+			if containsWindow(expr.expr) {
+				if s.windowAliases == nil {
+					s.windowAliases = make(map[string]struct{}, 1)
+				}
+				s.windowAliases[expr.alias] = struct{}{}
+			}
+			s.buildAs(expr.alias)
 		}
 	}
 	return nil
 
 }
-func (s *Selector[T]) selectAggregate(aggregate Aggregate) error {
-	s.writeString(aggregate.fn)
 
-	s.writeByte('(')
-	if aggregate.distinct {
-		s.writeString("DISTINCT ")
+// checkDistinctOrderBy 检查使用 DISTINCT 的时候，ORDER BY 的列是否都出现在 SELECT 列表中
+// 大多数数据库都有这个要求，例如 PostgreSQL 会返回 "for SELECT DISTINCT, ORDER BY expressions must appear in select list"
+// 必须在 buildSelectedList 之后调用，因为聚合函数等的别名是在那里收集的
+func (s *Selector[T]) checkDistinctOrderBy() error {
+	// SELECT DISTINCT * 包含了所有的列
+	if !s.distinct || len(s.columns) == 0 {
+		return nil
+	}
+	selected := make(map[string]struct{}, len(s.columns))
+	for _, selectable := range s.columns {
+		switch expr := selectable.(type) {
+		case Column:
+			selected[expr.name] = struct{}{}
+			if expr.alias != "" {
+				selected[expr.alias] = struct{}{}
+			}
+		case columns:
+			for _, c := range expr.cs {
+				selected[c] = struct{}{}
+			}
+		}
+	}
+	for _, ob := range s.orderBy {
+		for _, f := range ob.fields {
+			if _, ok := selected[f]; ok {
+				continue
+			}
+			if _, ok := s.aliases[f]; ok {
+				continue
+			}
+			return errs.NewDistinctSelectOrderByError(f)
+		}
 	}
-	cMeta, ok := s.meta.FieldMap[aggregate.arg]
+	return nil
+}
+func (s *Selector[T]) selectAggregate(aggregate Aggregate) error {
 	s.aliases[aggregate.alias] = struct{}{}
-	if !ok {
-		return errs.NewInvalidFieldError(aggregate.arg)
+	if err := s.buildAggregate(aggregate); err != nil {
+		return err
 	}
-	s.quote(cMeta.ColumnName)
-	s.writeByte(')')
 	if aggregate.alias != "" {
 		if _, ok := s.aliases[aggregate.alias]; ok {
 			s.writeString(" AS ")
@@ -257,10 +708,43 @@ func (s *Selector[T]) selectAggregate(aggregate Aggregate) error {
 	return nil
 }
 
+// selectSubquery 将标量子查询作为一列，子查询的参数排在外层 WHERE 的参数之前
+// 通过 FromRawQuery 构造的子查询无法知道列数，不会校验
+func (s *Selector[T]) selectSubquery(sub Subquery) error {
+	if _, ok := sub.q.(rawQueryBuilder); !ok {
+		cnt := 0
+		// 没有指定列的时候，子查询会返回模型的所有列
+		if tab, ok := sub.entity.(Table); ok && len(sub.columns) == 0 {
+			m, err := s.metaRegistry.Get(tab.entity)
+			if err != nil {
+				return err
+			}
+			cnt = len(m.Columns)
+		}
+		for _, c := range sub.columns {
+			if cs, ok := c.(columns); ok {
+				cnt += len(cs.cs)
+				continue
+			}
+			cnt++
+		}
+		if cnt != 1 {
+			return errs.NewScalarSubqueryColumnsError(cnt)
+		}
+	}
+	if err := s.buildSubquery(sub, true); err != nil {
+		return err
+	}
+	if sub.alias != "" {
+		s.aliases[sub.alias] = struct{}{}
+	}
+	return nil
+}
+
 func (s *Selector[T]) buildColumn(field, alias string) error {
-	cMeta, ok := s.meta.FieldMap[field]
-	if !ok {
-		return errs.NewInvalidFieldError(field)
+	cMeta, err := fieldMeta(s.meta, field)
+	if err != nil {
+		return err
 	}
 	s.quote(cMeta.ColumnName)
 	if alias != "" {
@@ -272,12 +756,44 @@ func (s *Selector[T]) buildColumn(field, alias string) error {
 }
 
 func (s *Selector[T]) buildJoin(tab Join) error {
+	if tab.typ == "CROSS JOIN" && (len(tab.on) > 0 || len(tab.using) > 0) {
+		return errs.ErrCrossJoinWithCondition
+	}
+	if len(tab.on) > 0 && len(tab.using) > 0 {
+		return errs.ErrJoinWithOnAndUsing
+	}
+	if (tab.typ == "FULL OUTER JOIN" && !s.features().FullOuterJoin) ||
+		(tab.typ == "LEFT JOIN LATERAL" && !s.features().Lateral) {
+		return errs.NewUnsupportedJoinError(tab.typ, s.dialect.Name())
+	}
+	if tab.typ == "CROSS APPLY" || tab.typ == "OUTER APPLY" {
+		var err error
+		if tab, err = s.translateApply(tab); err != nil {
+			return err
+		}
+	}
+	p, ok, err := s.softDeleteFilter(tab.right, true)
+	if err != nil {
+		return err
+	}
+	if ok {
+		// USING 不能和 ON 一起使用，CROSS JOIN 不能有条件，只能放到 WHERE 里面
+		if len(tab.using) > 0 || tab.typ == "CROSS JOIN" {
+			s.softDeleteWhere = append(s.softDeleteWhere, p)
+		} else {
+			tab.on = append(append(make([]Predicate, 0, len(tab.on)+1), tab.on...), p)
+		}
+	}
 	_ = s.buffer.WriteByte('(')
 	if err := s.buildTable(tab.left); err != nil {
 		return err
 	}
 	_ = s.buffer.WriteByte(' ')
-	_, _ = s.buffer.WriteString(tab.typ)
+	if tab.typ == "JOIN" && s.explicitInnerJoin {
+		_, _ = s.buffer.WriteString("INNER JOIN")
+	} else {
+		_, _ = s.buffer.WriteString(tab.typ)
+	}
 	_ = s.buffer.WriteByte(' ')
 	if err := s.buildTable(tab.right); err != nil {
 		return err
@@ -304,6 +820,167 @@ func (s *Selector[T]) buildJoin(tab Join) error {
 	return nil
 }
 
+// WithDeleted 不再过滤软删除的数据，对整个查询中所有连接的表都生效
+func (s *Selector[T]) WithDeleted() *Selector[T] {
+	s.withDeleted = true
+	return s
+}
+
+// Unscoped 等价于 WithDeleted，查询结果会包含已经被软删除的数据
+func (s *Selector[T]) Unscoped() *Selector[T] {
+	return s.WithDeleted()
+}
+
+// softDeleteFilter 返回 table 的软删除过滤条件，即 table.deleted_at IS NULL
+// 只有带有 eorm:"soft_delete" 列的 Table 才会返回 true
+// qualify 为 true 的时候，为了避免连接的表有同名的列，没有别名的时候会使用表名来限定列
+func (s *Selector[T]) softDeleteFilter(table TableReference, qualify bool) (Predicate, bool, error) {
+	tab, ok := table.(Table)
+	if !ok || s.withDeleted {
+		return Predicate{}, false, nil
+	}
+	m, err := s.metaRegistry.Get(tab.entity)
+	if err != nil {
+		return Predicate{}, false, err
+	}
+	if m.SoftDeleteColumn == nil {
+		return Predicate{}, false, nil
+	}
+	qualifier := tab.alias
+	if qualifier == "" && qualify {
+		qualifier = s.tablePrefix + m.TableName
+	}
+	return s.isNullPredicate(qualifier, m.SoftDeleteColumn.ColumnName), true, nil
+}
+
+// whereWithSoftDelete 返回加上了软删除过滤条件的 WHERE
+// 主表的过滤条件放在 WHERE 里面，连接查询中最左边的表被认为是主表，这样 LEFT JOIN 的语义才是正确的
+func (s *Selector[T]) whereWithSoftDelete() ([]Predicate, error) {
+	var (
+		main    TableReference
+		qualify bool
+	)
+	switch tab := s.table.(type) {
+	case nil:
+		main = TableOf(new(T))
+	case Table:
+		main = tab
+	case Join:
+		main, qualify = tab.left, true
+		for l, isJoin := main.(Join); isJoin; l, isJoin = main.(Join) {
+			main = l.left
+		}
+	}
+	p, has, err := s.softDeleteFilter(main, qualify)
+	if err != nil {
+		return nil, err
+	}
+	if !has && len(s.softDeleteWhere) == 0 {
+		return s.where, nil
+	}
+	where := make([]Predicate, 0, len(s.where)+len(s.softDeleteWhere)+1)
+	where = append(where, s.where...)
+	if has {
+		where = append(where, p)
+	}
+	return append(where, s.softDeleteWhere...), nil
+}
+
+// translateApply 在不支持 APPLY 的方言上将其改写为 LATERAL 连接
+func (s *Selector[T]) translateApply(tab Join) (Join, error) {
+	if dialect.SupportsApply(s.dialect) {
+		return tab, nil
+	}
+	if !s.features().Lateral {
+		return tab, errs.NewUnsupportedJoinError(tab.typ, s.dialect.Name())
+	}
+	if tab.typ == "CROSS APPLY" {
+		tab.typ = "JOIN LATERAL"
+	} else {
+		tab.typ = "LEFT JOIN LATERAL"
+	}
+	tab.on = []Predicate{Raw("TRUE").AsPredicate()}
+	return tab, nil
+}
+
+// With 定义一个公共表表达式，即 WITH name (cols) AS (q)
+// 多次调用会按照调用顺序定义多个公共表表达式，之后可以通过 CTEOf(name) 在 From 或者 Join 中引用
+func (s *Selector[T]) With(name string, q QueryBuilder, cols ...string) *Selector[T] {
+	s.ctes = append(s.ctes, cte{name: name, q: q, columns: cols})
+	return s
+}
+
+// Materialized 给最近一次通过 With 定义的公共表表达式加上 MATERIALIZED 提示，
+// 即 WITH name AS MATERIALIZED (q)。只有 PostgreSQL 支持，其余方言会忽略
+func (s *Selector[T]) Materialized() *Selector[T] {
+	return s.materialize("MATERIALIZED")
+}
+
+// NotMaterialized 给最近一次通过 With 定义的公共表表达式加上 NOT MATERIALIZED 提示，
+// 只有 PostgreSQL 支持，其余方言会忽略
+func (s *Selector[T]) NotMaterialized() *Selector[T] {
+	return s.materialize("NOT MATERIALIZED")
+}
+
+func (s *Selector[T]) materialize(hint string) *Selector[T] {
+	if l := len(s.ctes); l > 0 {
+		s.ctes[l-1].materialized = hint
+	}
+	return s
+}
+
+// WithTotalWindow 在查询的列后面加上 COUNT(*) OVER() AS alias，
+// 这样在分页查询的时候，一次查询就能同时拿到当页的数据和总数。
+// 一般来说，T 需要有对应 alias 的字段来接收总数
+// 注意它不能和 Distinct 一起使用
+func (s *Selector[T]) WithTotalWindow(alias string) *Selector[T] {
+	s.totalAlias = alias
+	return s
+}
+
+// WithNullsOrdering 指定 ORDER BY 中 NULL 值的位置
+// 它只会作用在可以为 NULL 的列上，例如指针类型或者 sql.NullString，其余列保持原样。
+// MySQL 不支持 NULLS FIRST/LAST，会改写为先按照 ISNULL(col) 排序
+func (s *Selector[T]) WithNullsOrdering(nulls NullsOrdering) *Selector[T] {
+	s.nulls = nulls
+	return s
+}
+
+// CalcFoundRows 加上 MySQL 的 SQL_CALC_FOUND_ROWS，
+// 之后可以通过 FoundRows 拿到忽略 LIMIT 之后的总行数
+func (s *Selector[T]) CalcFoundRows() *Selector[T] {
+	s.calcFoundRows = true
+	return s
+}
+
+// FoundRows 执行 SELECT FOUND_ROWS()，返回上一个 SQL_CALC_FOUND_ROWS 查询忽略 LIMIT 之后的总行数
+// 它必须和查询使用同一个连接，所以 Selector 需要创建在 Conn 或者 Tx 上
+func (s *Selector[T]) FoundRows(ctx context.Context) (int64, error) {
+	if _, ok := s.session.(*DB); ok {
+		return 0, errs.ErrFoundRowsWithoutConn
+	}
+	cnt, err := RawQuery[int64](s.session, "SELECT FOUND_ROWS();").Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return *cnt, nil
+}
+
+// ForUpdate 加上 FOR UPDATE，对选中的行加排它锁
+// 它不能和 ForShare 一起使用
+func (s *Selector[T]) ForUpdate() *Selector[T] {
+	s.forUpdate = true
+	return s
+}
+
+// ForShare 对选中的行加共享锁，
+// 在 PostgreSQL 中是 FOR SHARE，在 MySQL 中是 LOCK IN SHARE MODE
+// 它不能和 ForUpdate 一起使用
+func (s *Selector[T]) ForShare() *Selector[T] {
+	s.forShare = true
+	return s
+}
+
 // Select 指定查询的列。
 // 列可以是物理列，也可以是聚合函数，或者 RawExpr
 func (s *Selector[T]) Select(columns ...Selectable) *Selector[T] {
@@ -323,6 +1000,12 @@ func (s *Selector[T]) Where(predicates ...Predicate) *Selector[T] {
 	return s
 }
 
+// TablePrefix 覆盖 DBWithTablePrefix 指定的表名前缀，只对当前查询生效，传入空字符串表示不使用前缀
+func (s *Selector[T]) TablePrefix(prefix string) *Selector[T] {
+	s.tablePrefix = prefix
+	return s
+}
+
 // Distinct indicates using keyword DISTINCT
 func (s *Selector[T]) Distinct() *Selector[T] {
 	s.distinct = true
@@ -330,17 +1013,45 @@ func (s *Selector[T]) Distinct() *Selector[T] {
 }
 
 // Having accepts predicates
+// 它会覆盖之前设置的 HAVING 条件，需要逐步构造条件的时候使用 AndHaving 或者 OrHaving
+// 聚合函数无法表达的条件可以使用 RawPredicate，例如 Having(RawPredicate("COUNT(*) > ?", 5))，参数会保持参数化
 func (s *Selector[T]) Having(predicates ...Predicate) *Selector[T] {
 	s.having = predicates
 	return s
 }
 
+// AndHaving 在已有的 HAVING 条件上追加 predicates，所有条件之间使用 AND 连接
+// 例如 Having(a).AndHaving(b).AndHaving(c) 等价于 Having(a, b, c)
+func (s *Selector[T]) AndHaving(predicates ...Predicate) *Selector[T] {
+	s.having = append(s.having, predicates...)
+	return s
+}
+
+// OrHaving 将已有的 HAVING 条件作为一个整体，和 predicates 使用 OR 连接，predicates 之间使用 AND 连接
+// 例如 Having(a, b).OrHaving(c, d) 会生成 ((a) AND (b)) OR ((c) AND (d))
+// 没有已有条件的时候，它等价于 AndHaving
+func (s *Selector[T]) OrHaving(predicates ...Predicate) *Selector[T] {
+	if len(s.having) == 0 || len(predicates) == 0 {
+		return s.AndHaving(predicates...)
+	}
+	s.having = []Predicate{and(s.having).Or(and(predicates))}
+	return s
+}
+
 // GroupBy means "GROUP BY"
 func (s *Selector[T]) GroupBy(columns ...string) *Selector[T] {
 	s.groupBy = columns
 	return s
 }
 
+// GroupByExpr 按照表达式分组，例如 DateTrunc，它们排在 GroupBy 指定的列之后
+// 也可以使用 RawExpr，例如 GroupByExpr(Raw("DATE(`create_time`)"))，RawExpr 的参数按照出现的顺序加入参数列表
+// 在连接查询中可以传入带有表的列，例如 GroupByExpr(t1.C("Id")) 会生成 GROUP BY `t1`.`id`
+func (s *Selector[T]) GroupByExpr(exprs ...Expr) *Selector[T] {
+	s.groupByExprs = exprs
+	return s
+}
+
 // OrderBy means "ORDER BY"
 func (s *Selector[T]) OrderBy(orderBys ...OrderBy) *Selector[T] {
 	s.orderBy = orderBys
@@ -361,8 +1072,11 @@ func (s *Selector[T]) Offset(offset int) *Selector[T] {
 
 func (s *Selector[T]) AsSubquery(alias string) Subquery {
 	var table TableReference
-	if s.table == nil {
+	switch tab := s.table.(type) {
+	case nil:
 		table = TableOf(new(T))
+	case Table:
+		table = tab
 	}
 	return Subquery{
 		entity:  table,
@@ -372,6 +1086,16 @@ func (s *Selector[T]) AsSubquery(alias string) Subquery {
 	}
 }
 
+// Union 返回一个使用 UNION 连接当前 Selector 和 q 的 Combinator
+func (s *Selector[T]) Union(q QueryBuilder) *Combinator[T] {
+	return NewCombinator[T](s.session, s).Union(q)
+}
+
+// UnionAll 返回一个使用 UNION ALL 连接当前 Selector 和 q 的 Combinator
+func (s *Selector[T]) UnionAll(q QueryBuilder) *Combinator[T] {
+	return NewCombinator[T](s.session, s).UnionAll(q)
+}
+
 // Get 方法会执行查询，并且返回一条数据
 // 注意，在不同的数据库情况下，第一条数据可能是按照不同的列来排序的
 // 而且要注意，这个方法会强制设置 Limit 1
@@ -384,10 +1108,122 @@ func (s *Selector[T]) Get(ctx context.Context) (*T, error) {
 	return newQuerier[T](s.session, query, s.meta, SELECT).Get(ctx)
 }
 
+// GetForUpdate 会加上 FOR UPDATE 并且和 Get 一样只读取一行数据
+// 如果在事务中遇到了 MySQL 的锁等待超时，那么会按照 DBWithRetryPolicy 设置的重试策略重试。
+// 在默认的 innodb_rollback_on_timeout=OFF 下，MySQL 只会回滚超时的语句，所以可以在同一个事务中重试；
+// 死锁以及 PostgreSQL 的错误会导致整个事务失效，此时会直接返回错误，需要使用 DB.DoTx 重新执行整个事务
+func (s *Selector[T]) GetForUpdate(ctx context.Context) (*T, error) {
+	query, err := s.ForUpdate().Limit(1).Build()
+	if err != nil {
+		return nil, err
+	}
+	q := newQuerier[T](s.session, query, s.meta, SELECT)
+	res, err := q.Get(ctx)
+	p := s.retryPolicy
+	if _, ok := s.session.(*Tx); !ok || p == nil {
+		return res, err
+	}
+	for attempt := 1; attempt < p.MaxAttempts && isStatementRetryable(err) && p.retryable(err); attempt++ {
+		if werr := p.wait(ctx, attempt); werr != nil {
+			return nil, werr
+		}
+		res, err = q.Get(ctx)
+	}
+	return res, err
+}
+
+// GetInto 和 Get 一样会强制设置 Limit 1，但是会将结果写入到 dst 中，而不是创建一个新的 T
+// 适合在循环或者对象池中复用 dst，以减少内存分配
+// 在没有查找到数据的情况下，会返回 ErrNoRows，此时 dst 不会被修改
+func (s *Selector[T]) GetInto(ctx context.Context, dst *T) error {
+	if dst == nil {
+		return errs.ErrNilDestination
+	}
+	query, err := s.Limit(1).Build()
+	if err != nil {
+		return err
+	}
+	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		return getIntoHandler[T](ctx, s.session, s.core, qc, dst)
+	}
+	ms := s.ms
+	for i := len(ms) - 1; i >= 0; i-- {
+		handler = ms[i](handler)
+	}
+	return handler(ctx, &QueryContext{q: query, meta: s.meta, Type: SELECT}).Err
+}
+
+// GetMapOptions 控制 GetMap 的行为
+type GetMapOptions struct {
+	// OmitNull 为 true 的时候，值为 NULL 的列不会出现在结果中，
+	// 默认情况下，这些列会以 nil 的形式出现在结果中
+	OmitNull bool
+}
+
+// GetMap 和 Get 一样会强制设置 Limit 1，但是会将结果按照列名转换为一个 map，值的类型由驱动决定
+// 在没有查找到数据的情况下，会返回 ErrNoRows
+func (s *Selector[T]) GetMap(ctx context.Context, opts GetMapOptions) (map[string]any, error) {
+	query, err := s.Limit(1).Build()
+	if err != nil {
+		return nil, err
+	}
+	var res map[string]any
+	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		return getIntoHandler[map[string]any](ctx, s.session, s.core, qc, &res)
+	}
+	ms := s.ms
+	for i := len(ms) - 1; i >= 0; i-- {
+		handler = ms[i](handler)
+	}
+	if err = handler(ctx, &QueryContext{q: query, meta: s.meta, Type: SELECT}).Err; err != nil {
+		return nil, err
+	}
+	if opts.OmitNull {
+		for k, v := range res {
+			if v == nil {
+				delete(res, k)
+			}
+		}
+	}
+	return res, nil
+}
+
+// NullsOrdering 代表 NULL 值在排序中的位置
+type NullsOrdering string
+
+const (
+	// NullsFirst 代表 NULLS FIRST
+	NullsFirst NullsOrdering = "NULLS FIRST"
+	// NullsLast 代表 NULLS LAST
+	NullsLast NullsOrdering = "NULLS LAST"
+)
+
 // OrderBy specify fields and ASC
 type OrderBy struct {
 	fields []string
 	order  string
+	// vals 是 OrderByField 指定的自定义顺序
+	vals []any
+	// expr 不为 nil 的时候按照表达式排序，此时 fields 为空
+	expr Expr
+	// nulls 是 NullsFirst 或者 NullsLast 指定的 NULL 值的位置，优先于 WithNullsOrdering
+	nulls NullsOrdering
+}
+
+// NullsFirst 将 NULL 值排在最前面，即 NULLS FIRST
+// MySQL 不支持 NULLS FIRST，会改写为 ISNULL(col) DESC, col
+// 它只能用在单个列或者表达式上，例如 ASC("Age").NullsFirst()，多个列需要分别指定
+func (o OrderBy) NullsFirst() OrderBy {
+	o.nulls = NullsFirst
+	return o
+}
+
+// NullsLast 将 NULL 值排在最后面，即 NULLS LAST
+// MySQL 不支持 NULLS LAST，会改写为 ISNULL(col), col
+// 它只能用在单个列或者表达式上，多个列需要分别指定
+func (o OrderBy) NullsLast() OrderBy {
+	o.nulls = NullsLast
+	return o
 }
 
 // ASC means ORDER BY fields ASC
@@ -406,6 +1242,44 @@ func DESC(fields ...string) OrderBy {
 	}
 }
 
+// ASCExpr means ORDER BY expr ASC，例如 ASCExpr(DateTrunc("day", "CreateTime"))
+func ASCExpr(expr Expr) OrderBy {
+	return OrderBy{
+		order: "ASC",
+		expr:  expr,
+	}
+}
+
+// DESCExpr means ORDER BY expr DESC
+func DESCExpr(expr Expr) OrderBy {
+	return OrderBy{
+		order: "DESC",
+		expr:  expr,
+	}
+}
+
+// ASCColumn means ORDER BY col ASC，col 可以带有表，
+// 例如在连接查询中 ASCColumn(t1.C("Id")) 会生成 `t1`.`id` ASC，避免列名有歧义
+func ASCColumn(col Column) OrderBy {
+	return ASCExpr(col)
+}
+
+// DESCColumn means ORDER BY col DESC，参考 ASCColumn
+func DESCColumn(col Column) OrderBy {
+	return DESCExpr(col)
+}
+
+// OrderByField 按照 vals 指定的顺序对 field 排序，不在 vals 中的数据排在最前面。
+// 在 MySQL 上会被翻译为 FIELD(col,?,?...)，在其它方言上会被翻译为 CASE col WHEN ? THEN 1 ... ELSE 0 END。
+// 如果 vals 为空，那么等价于 ASC(field)
+func OrderByField(field string, vals ...any) OrderBy {
+	return OrderBy{
+		fields: []string{field},
+		order:  "ASC",
+		vals:   vals,
+	}
+}
+
 // Selectable is a tag interface which represents SELECT XXX
 type Selectable interface {
 	fieldName() string
@@ -413,10 +1287,285 @@ type Selectable interface {
 	selectedAlias() string
 }
 
+// NoLimit 表示该查询不使用 DBWithDefaultSelectLimit 配置的默认 LIMIT
+func (s *Selector[T]) NoLimit() *Selector[T] {
+	s.noLimit = true
+	return s
+}
+
+// withDefaultLimit 在没有设置 Limit 的时候返回一个使用 DB 上配置的默认值的复制品，
+// 这样默认的 LIMIT 不会被写回到当前 Selector 上，否则直接返回当前 Selector
+func (s *Selector[T]) withDefaultLimit() *Selector[T] {
+	if s.limit != 0 || s.noLimit || s.defaultSelectLimit <= 0 {
+		return s
+	}
+	c := s.clone()
+	c.limit = s.defaultSelectLimit
+	return c
+}
+
 func (s *Selector[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	s = s.withDefaultLimit()
 	query, err := s.Build()
 	if err != nil {
 		return nil, err
 	}
 	return newQuerier[T](s.session, query, s.meta, SELECT).GetMulti(ctx)
 }
+
+// Rows 执行查询，并且返回一个以流的形式读取结果的 Iterator
+// 和 GetMulti 不同，它不会一次性将所有的数据读取到内存中，适合用于导出大量数据的场景
+func (s *Selector[T]) Rows(ctx context.Context) (*Iterator[T], error) {
+	s = s.withDefaultLimit()
+	query, err := s.Build()
+	if err != nil {
+		return nil, err
+	}
+	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		rows, err := s.session.queryContext(ctx, qc.q.SQL, qc.q.Args...)
+		if err != nil {
+			return &QueryResult{Err: err}
+		}
+		return &QueryResult{Result: rows}
+	}
+	ms := s.ms
+	for i := len(ms) - 1; i >= 0; i-- {
+		handler = ms[i](handler)
+	}
+	qr := handler(ctx, &QueryContext{q: query, meta: s.meta, Type: SELECT})
+	if qr.Err != nil {
+		return nil, qr.Err
+	}
+	return &Iterator[T]{
+		rows:       qr.Result.(*sql.Rows),
+		meta:       s.meta,
+		valCreator: s.valCreator,
+	}, nil
+}
+
+// Exists 判断是否存在满足条件的数据
+// 它会构造 SELECT EXISTS(SELECT 1 FROM ... WHERE ...)，不会读取具体的数据，也不会修改当前 Selector
+func (s *Selector[T]) Exists(ctx context.Context) (bool, error) {
+	sub := s.clone()
+	sub.columns = []Selectable{Raw("1")}
+	q, err := sub.Build()
+	if err != nil {
+		return false, err
+	}
+	query := &Query{
+		SQL:  "SELECT EXISTS(" + q.SQL[:len(q.SQL)-1] + ");",
+		Args: q.Args,
+	}
+	res, err := newQuerier[bool](s.session, query, sub.meta, SELECT).Get(ctx)
+	if err != nil {
+		return false, err
+	}
+	return *res, nil
+}
+
+// Page 使用游标（keyset）分页，避免 OFFSET 较大时的性能问题
+// 它会按照 field 升序排列，查询 field 大于 after 的 size 条数据，
+// after 为 nil 的时候查询第一页。
+// 返回的 next 是当页最后一条数据的 field 值，用作下一页的 after；
+// 当返回的数据不足 size 条时，说明已经没有下一页了，next 为 nil。
+// size 必须大于 0，否则返回 errs.ErrInvalidPageSize。
+// 它不会修改当前 Selector
+func (s *Selector[T]) Page(ctx context.Context, field string, after any, size int) (res []*T, next any, err error) {
+	if size <= 0 {
+		return nil, nil, errs.ErrInvalidPageSize
+	}
+	c := s.clone()
+	if after != nil {
+		where := make([]Predicate, 0, len(c.where)+1)
+		where = append(where, c.where...)
+		c.where = append(where, C(field).GT(after))
+	}
+	c.orderBy = []OrderBy{ASC(field)}
+	c.limit = size
+	res, err = c.GetMulti(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(res) < size {
+		return res, nil, nil
+	}
+	next, err = c.valCreator.NewBasicTypeValue(res[len(res)-1], c.meta).Field(field)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, next, nil
+}
+
+// MapMulti 执行查询，并且在遍历结果集的时候直接用 fn 将每一行数据转化为 R，
+// 避免先拿到 []*T 再遍历一遍
+func MapMulti[T, R any](ctx context.Context, s *Selector[T], fn func(t *T) R) ([]R, error) {
+	s = s.withDefaultLimit()
+	query, err := s.Build()
+	if err != nil {
+		return nil, err
+	}
+	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		res := make([]R, 0, 16)
+		err := forEach[T](ctx, s.session, s.core, qc, func(t *T) error {
+			res = append(res, fn(t))
+			return nil
+		})
+		if err != nil {
+			return &QueryResult{Err: err}
+		}
+		return &QueryResult{Result: res}
+	}
+	ms := s.ms
+	for i := len(ms) - 1; i >= 0; i-- {
+		handler = ms[i](handler)
+	}
+	qr := handler(ctx, &QueryContext{q: query, meta: s.meta, Type: SELECT})
+	if qr.Err != nil {
+		return nil, qr.Err
+	}
+	return qr.Result.([]R), nil
+}
+
+// GetMultiVal 和 GetMulti 一样，但是返回的是 []T 而不是 []*T，
+// 数据会直接扫描到切片的元素上，避免为每一行单独分配一个 *T
+func (s *Selector[T]) GetMultiVal(ctx context.Context) ([]T, error) {
+	s = s.withDefaultLimit()
+	query, err := s.Build()
+	if err != nil {
+		return nil, err
+	}
+	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		rows, err := s.session.queryContext(ctx, qc.q.SQL, qc.q.Args...)
+		if err != nil {
+			return &QueryResult{Err: err}
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+		// LIMIT 只是上限，实际返回的行数可能很少，所以预分配的容量不超过 maxPreallocRows
+		initCap := 16
+		if s.limit > 0 {
+			initCap = s.limit
+		}
+		if initCap > maxPreallocRows {
+			initCap = maxPreallocRows
+		}
+		res := make([]T, 0, initCap)
+		var zero T
+		for rows.Next() {
+			res = append(res, zero)
+			val := s.valCreator.NewBasicTypeValue(&res[len(res)-1], qc.meta)
+			if err = val.SetColumns(rows); err != nil {
+				return &QueryResult{Err: err}
+			}
+		}
+		if err = rows.Err(); err != nil {
+			return &QueryResult{Err: err}
+		}
+		return &QueryResult{Result: res}
+	}
+	ms := s.ms
+	for i := len(ms) - 1; i >= 0; i-- {
+		handler = ms[i](handler)
+	}
+	qr := handler(ctx, &QueryContext{q: query, meta: s.meta, Type: SELECT})
+	if qr.Err != nil {
+		return nil, qr.Err
+	}
+	return qr.Result.([]T), nil
+}
+
+// GetMultiPositional 执行查询，并且按照列的位置而不是列名把结果写入到 dst 中：
+// 第 N 列写入到 T 按照声明顺序的第 N 个字段（组合的字段会被展开）。
+// 它不需要根据列名查找字段，所以性能更好，也适合列名重复或者有歧义的查询，例如 JOIN 之后两边都有 id 列。
+//
+// 需要注意的是，这种方式非常脆弱：SELECT 的列的顺序必须和字段的声明顺序严格一致，
+// 调整字段的顺序、增删字段或者改变查询的列，都可能导致数据被写到错误的字段上而不会有任何报错，
+// 只有列的数量和字段的数量不一致的时候才会返回错误。除非确实需要，否则应该使用 GetMulti
+func (s *Selector[T]) GetMultiPositional(ctx context.Context, dst *[]T) error {
+	s = s.withDefaultLimit()
+	query, err := s.Build()
+	if err != nil {
+		return err
+	}
+	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		var zero T
+		// 只有 T 就是查询的模型的时候，字段的声明顺序才有意义
+		if qc.meta == nil || qc.meta.Typ.Elem() != reflect.TypeOf(zero) {
+			return &QueryResult{Err: errs.NewUnsupportedTypeError(reflect.TypeOf(zero))}
+		}
+		rows, err := s.session.queryContext(ctx, qc.q.SQL, qc.q.Args...)
+		if err != nil {
+			return &QueryResult{Err: err}
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+		cs, err := rows.Columns()
+		if err != nil {
+			return &QueryResult{Err: err}
+		}
+		if len(cs) != len(qc.meta.Columns) {
+			return &QueryResult{Err: errs.NewColumnCountMismatchError(len(cs), len(qc.meta.Columns))}
+		}
+		res := make([]T, 0, 16)
+		dest := make([]any, len(cs))
+		for rows.Next() {
+			res = append(res, zero)
+			val := reflect.ValueOf(&res[len(res)-1]).Elem()
+			for i, c := range qc.meta.Columns {
+				dest[i] = val.FieldByIndex(c.FieldIndexes).Addr().Interface()
+			}
+			if err = rows.Scan(dest...); err != nil {
+				return &QueryResult{Err: err}
+			}
+		}
+		if err = rows.Err(); err != nil {
+			return &QueryResult{Err: err}
+		}
+		return &QueryResult{Result: res}
+	}
+	ms := s.ms
+	for i := len(ms) - 1; i >= 0; i-- {
+		handler = ms[i](handler)
+	}
+	qr := handler(ctx, &QueryContext{q: query, meta: s.meta, Type: SELECT})
+	if qr.Err != nil {
+		return qr.Err
+	}
+	*dst = qr.Result.([]T)
+	return nil
+}
+
+// GetMultiLimit 执行查询，并且强制只返回最多 n 条数据
+// 它会忽略 Selector 上已经设置的 Limit，并且不会修改当前 Selector，
+// 所以适合在共享的 Selector 上"先看前 N 条"。
+// n 必须大于 0，否则返回 errs.ErrInvalidMultiLimit，而不是退化为不限制或者使用默认的 LIMIT
+func (s *Selector[T]) GetMultiLimit(ctx context.Context, n int) ([]*T, error) {
+	if n <= 0 {
+		return nil, errs.ErrInvalidMultiLimit
+	}
+	return s.clone().Limit(n).GetMulti(ctx)
+}
+
+// topOne 返回一个按照 orderBy 排序并且只取一行的复制品，不会修改当前 Selector
+func (s *Selector[T]) topOne(orderBy []OrderBy) QueryBuilder {
+	c := s.clone()
+	c.orderBy = orderBy
+	c.limit = 1
+	return c
+}
+
+// clone 复制一个 Selector，复制品使用自己的 buffer，
+// 并且重置所有构造 SQL 过程中产生的状态，所以构造的时候不会影响到原本的 Selector
+func (s *Selector[T]) clone() *Selector[T] {
+	c := *s
+	c.buffer = bytebufferpool.Get()
+	c.meta = nil
+	c.args = nil
+	c.aliases = nil
+	c.qualifyColumns = false
+	c.windowAliases = nil
+	c.softDeleteWhere = nil
+	return &c
+}
@@ -17,12 +17,21 @@ package eorm
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/gotomicro/eorm/internal/dialect"
 	"github.com/gotomicro/eorm/internal/errs"
 	"github.com/stretchr/testify/assert"
 )
@@ -263,6 +272,25 @@ func TestRawQuery_GetMulti_baseType(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "res map",
+			queryRes: func(t *testing.T) any {
+				queryer := RawQuery[map[string]any](db, "SELECT `id`,`first_name` FROM `test_model`;")
+				result, err := queryer.GetMulti(context.Background())
+				require.NoError(t, err)
+				return result
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				rows := mock.NewRows([]string{"id", "first_name"}).
+					AddRow(int64(1), []byte("Tom")).AddRow(int64(2), nil)
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model`;").
+					WillReturnRows(rows)
+			},
+			wantVal: []*map[string]any{
+				{"id": int64(1), "first_name": []byte("Tom")},
+				{"id": int64(2), "first_name": nil},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -758,447 +786,909 @@ func TestSelector_GetMulti_baseType(t *testing.T) {
 	}
 }
 
-func TestSelectable(t *testing.T) {
+func TestSelector_clone(t *testing.T) {
 	db := memoryDB()
-	type TestModel2 struct {
-		UserId int64
-		Phone  int64
+	s := NewSelector[TestModel](db).Select(C("Id"), RowNumber().Over(Partition("Age")).As("rn"))
+	q, err := s.Build()
+	require.NoError(t, err)
+	wantSql := "SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `age`) AS `rn` FROM `test_model`;"
+	assert.Equal(t, wantSql, q.SQL)
+	assert.Equal(t, map[string]struct{}{"rn": {}}, s.windowAliases)
+
+	// 复制品使用别的窗口函数别名，不能修改原本的 Selector
+	c := s.clone()
+	c.columns = []Selectable{C("Id"), Rank().Over(DESC("Age")).As("rk")}
+	q, err = c.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `id`,RANK() OVER (ORDER BY `age` DESC) AS `rk` FROM `test_model`;", q.SQL)
+	assert.Equal(t, map[string]struct{}{"rn": {}}, s.windowAliases)
+	assert.Equal(t, map[string]struct{}{"rk": {}}, c.windowAliases)
+
+	// 并发构造复制品
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q, err := s.clone().Build()
+			assert.NoError(t, err)
+			assert.Equal(t, wantSql, q.SQL)
+		}()
 	}
-	type TestModel3 struct {
-		Id int64
+	wg.Wait()
+
+	// USING 的软删除条件是构造的时候收集的，复制品不能重复使用
+	type User struct {
+		Id        int64
+		DeletedAt *time.Time `eorm:"soft_delete"`
 	}
-	testCases := []CommonTestCase{
+	type Order struct {
+		Id        int64
+		DeletedAt *time.Time `eorm:"soft_delete"`
+	}
+	u := TableOf(&User{}).As("u")
+	o := TableOf(&Order{}).As("o")
+	us := NewSelector[User](db).Select(u.C("Id")).From(u.Join(o).Using("Id"))
+	q, err = us.Build()
+	require.NoError(t, err)
+	wantSql = "SELECT `u`.`id` FROM (`user` AS `u` JOIN `order` AS `o` USING (`id`)) " +
+		"WHERE (`u`.`deleted_at` IS NULL) AND (`o`.`deleted_at` IS NULL);"
+	assert.Equal(t, wantSql, q.SQL)
+	q, err = us.clone().Build()
+	require.NoError(t, err)
+	assert.Equal(t, wantSql, q.SQL)
+}
+
+func TestSelector_GetMultiLimit(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSelector[TestModel](db).Select(C("FirstName"), C("Age")).
+		Where(C("Age").GT(10)).Limit(100)
+	rows := mock.NewRows([]string{"first_name", "age"}).
+		AddRow("Da", 18).AddRow("Xiao", 16)
+	mock.ExpectQuery("SELECT `first_name`,`age` FROM `test_model` WHERE `age`>? LIMIT ?;").
+		WithArgs(10, 2).
+		WillReturnRows(rows)
+	res, err := s.GetMultiLimit(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*TestModel{
 		{
-			name:    "simple",
-			builder: NewSelector[TestModel](db),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;",
+			FirstName: "Da",
+			Age:       18,
 		},
 		{
-			name:    "columns",
-			builder: NewSelector[TestModel](db).Select(Columns("Id", "FirstName")),
-			wantSql: "SELECT `id`,`first_name` FROM `test_model`;",
+			FirstName: "Xiao",
+			Age:       16,
 		},
+	}, res)
+	// 原本的 Selector 不会被修改
+	assert.Equal(t, 100, s.limit)
+	query, err := s.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `first_name`,`age` FROM `test_model` WHERE `age`>? LIMIT ?;", query.SQL)
+	assert.Equal(t, []any{10, 100}, query.Args)
+
+	// n 不合法的时候不会发起查询
+	for _, n := range []int{0, -1} {
+		res, err = s.GetMultiLimit(context.Background(), n)
+		assert.Equal(t, errs.ErrInvalidMultiLimit, err)
+		assert.Nil(t, res)
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_GetInto(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		mockRows func()
+		dst      *TestModel
+		wantErr  error
+		wantRes  *TestModel
+	}{
 		{
-			name:    "alias",
-			builder: NewSelector[TestModel](db).Select(Columns("Id"), C("FirstName").As("name")),
-			wantSql: "SELECT `id`,`first_name` AS `name` FROM `test_model`;",
+			name:     "nil dst",
+			mockRows: func() {},
+			wantErr:  errs.ErrNilDestination,
 		},
 		{
-			name:    "aggregate",
-			builder: NewSelector[TestModel](db).Select(Columns("Id"), Avg("Age").As("avg_age")),
-			wantSql: "SELECT `id`,AVG(`age`) AS `avg_age` FROM `test_model`;",
+			name: "query error",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnError(errors.New("mock error"))
+			},
+			dst:     &TestModel{},
+			wantErr: errors.New("mock error"),
+			wantRes: &TestModel{},
 		},
 		{
-			name:    "raw",
-			builder: NewSelector[TestModel](db).Select(Columns("Id"), Raw("AVG(DISTINCT `age`)")),
-			wantSql: "SELECT `id`,AVG(DISTINCT `age`) FROM `test_model`;",
+			name: "no rows",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}))
+			},
+			dst:     &TestModel{Id: 3, FirstName: "Kate"},
+			wantErr: errs.ErrNoRows,
+			wantRes: &TestModel{Id: 3, FirstName: "Kate"},
 		},
 		{
-			name:    "invalid columns",
-			builder: NewSelector[TestModel](db).Select(Columns("Invalid"), Raw("AVG(DISTINCT `age`)")),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name: "reuse dst",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).
+					AddRow(1, "Tom").AddRow(2, "Jerry"))
+			},
+			dst:     &TestModel{Id: 3, FirstName: "Kate", Age: 18},
+			wantRes: &TestModel{Id: 1, FirstName: "Tom", Age: 18},
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRows()
+			err := NewSelector[TestModel](db).Select(C("Id"), C("FirstName")).
+				GetInto(context.Background(), tc.dst)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, tc.dst)
+		})
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_GetMap(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		mockRows func()
+		opts     GetMapOptions
+		wantErr  error
+		wantRes  map[string]any
+	}{
 		{
-			name:    "order by",
-			builder: NewSelector[TestModel](db).OrderBy(ASC("Age"), DESC("Id")),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC;",
+			name: "query error",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`last_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnError(errors.New("mock error"))
+			},
+			wantErr: errors.New("mock error"),
 		},
 		{
-			name:    "order by invalid column",
-			builder: NewSelector[TestModel](db).OrderBy(ASC("Invalid"), DESC("Id")),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name: "no rows",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`last_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name"}))
+			},
+			wantErr: errs.ErrNoRows,
 		},
 		{
-			name:    "group by",
-			builder: NewSelector[TestModel](db).GroupBy("Age", "Id"),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `age`,`id`;",
+			name: "include null",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`last_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name"}).
+					AddRow(int64(1), []byte("Tom"), nil))
+			},
+			wantRes: map[string]any{
+				"id":         int64(1),
+				"first_name": []byte("Tom"),
+				"last_name":  nil,
+			},
 		},
 		{
-			name:    "group by invalid column",
-			builder: NewSelector[TestModel](db).GroupBy("Invalid", "Id"),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name: "omit null",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`last_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name"}).
+					AddRow(int64(1), nil, nil))
+			},
+			opts: GetMapOptions{OmitNull: true},
+			wantRes: map[string]any{
+				"id": int64(1),
+			},
 		},
 		{
-			name:     "offset",
-			builder:  NewSelector[TestModel](db).OrderBy(ASC("Age"), DESC("Id")).Offset(10),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC OFFSET ?;",
-			wantArgs: []interface{}{10},
+			name: "omit null without null",
+			mockRows: func() {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`last_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name"}).
+					AddRow(int64(1), []byte("Tom"), []byte("Green")))
+			},
+			opts: GetMapOptions{OmitNull: true},
+			wantRes: map[string]any{
+				"id":         int64(1),
+				"first_name": []byte("Tom"),
+				"last_name":  []byte("Green"),
+			},
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockRows()
+			res, err := NewSelector[TestModel](db).Select(C("Id"), C("FirstName"), C("LastName")).
+				GetMap(context.Background(), tc.opts)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_Exists(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		s         *Selector[TestModel]
+		mockOrder func(mock sqlmock.Sqlmock)
+		wantErr   error
+		wantVal   bool
+	}{
 		{
-			name:     "limit",
-			builder:  NewSelector[TestModel](db).OrderBy(ASC("Age"), DESC("Id")).Offset(10).Limit(100),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC OFFSET ? LIMIT ?;",
-			wantArgs: []interface{}{10, 100},
+			name: "exists",
+			s:    NewSelector[TestModel](db).Where(C("Age").GT(18)),
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM `test_model` WHERE `age`>?);").
+					WithArgs(18).WillReturnRows(sqlmock.NewRows([]string{"EXISTS"}).AddRow(1))
+			},
+			wantVal: true,
 		},
 		{
-			name:     "where",
-			builder:  NewSelector[TestModel](db).Where(C("Id").EQ(10)),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `id`=?;",
-			wantArgs: []interface{}{10},
+			name: "not exists",
+			s:    NewSelector[TestModel](db).Select(C("Id"), C("FirstName")).Where(C("FirstName").EQ("Tom")),
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM `test_model` WHERE `first_name`=?);").
+					WithArgs("Tom").WillReturnRows(sqlmock.NewRows([]string{"EXISTS"}).AddRow(0))
+			},
+			wantVal: false,
 		},
 		{
-			name:    "no where",
-			builder: NewSelector[TestModel](db).Where(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;",
+			name: "query error",
+			s:    NewSelector[TestModel](db).Where(C("Age").GT(18)),
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM `test_model` WHERE `age`>?);").
+					WithArgs(18).WillReturnError(errors.New("mock error"))
+			},
+			wantErr: errors.New("mock error"),
 		},
 		{
-			name:     "having",
-			builder:  NewSelector[TestModel](db).GroupBy("FirstName").Having(Avg("Age").EQ(18)),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING AVG(`age`)=?;",
-			wantArgs: []interface{}{18},
+			name:      "invalid field",
+			s:         NewSelector[TestModel](db).Where(C("Invalid").GT(18)),
+			mockOrder: func(mock sqlmock.Sqlmock) {},
+			wantErr:   errs.NewInvalidFieldError("Invalid"),
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockOrder(mock)
+			res, err := tc.s.Exists(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantVal, res)
+		})
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_GetMultiVal(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		queryRes  func() (any, error)
+		mockOrder func(mock sqlmock.Sqlmock)
+		wantErr   error
+		wantVal   any
+	}{
 		{
-			name:    "no having",
-			builder: NewSelector[TestModel](db).GroupBy("FirstName").Having(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name`;",
+			name: "struct",
+			queryRes: func() (any, error) {
+				return NewSelector[TestModel](db).Select(C("Id"), C("FirstName")).
+					Where(C("Age").GT(18)).Limit(10).GetMultiVal(context.Background())
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` WHERE `age`>? LIMIT ?;").
+					WithArgs(18, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(1, "Tom").AddRow(2, "Jerry"))
+			},
+			wantVal: []TestModel{{Id: 1, FirstName: "Tom"}, {Id: 2, FirstName: "Jerry"}},
 		},
 		{
-			name:     "alias in having",
-			builder:  NewSelector[TestModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("avg_age").LT(20)),
-			wantSql:  "SELECT `id`,`first_name`,AVG(`age`) AS `avg_age` FROM `test_model` GROUP BY `first_name` HAVING `avg_age`<?;",
-			wantArgs: []interface{}{20},
+			name: "base type",
+			queryRes: func() (any, error) {
+				return NewSelector[int](db).Select(C("Age")).From(TableOf(&TestModel{})).
+					GetMultiVal(context.Background())
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `age` FROM `test_model`;").
+					WillReturnRows(sqlmock.NewRows([]string{"age"}).AddRow(10).AddRow(18).AddRow(22))
+			},
+			wantVal: []int{10, 18, 22},
 		},
 		{
-			name:    "invalid alias in having",
-			builder: NewSelector[TestModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("Invalid").LT(20)),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name: "no row",
+			queryRes: func() (any, error) {
+				return NewSelector[TestModel](db).Select(C("Id")).GetMultiVal(context.Background())
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id` FROM `test_model`;").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			wantVal: []TestModel{},
 		},
 		{
-			name:     "in",
-			builder:  NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In(1, 2, 3)),
-			wantSql:  "SELECT `id` FROM `test_model` WHERE `id` IN (?,?,?);",
-			wantArgs: []interface{}{1, 2, 3},
+			name: "too many columns",
+			queryRes: func() (any, error) {
+				return NewSelector[TestModel](db).Select(C("Id")).GetMultiVal(context.Background())
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id` FROM `test_model`;").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "age", "last_name", "extra_column"}).
+						AddRow(1, "Tom", 18, "Jerry", "nothing"))
+			},
+			wantErr: errs.ErrTooManyColumns,
 		},
 		{
-			name:     "not in",
-			builder:  NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").NotIn(1, 2, 3)),
-			wantSql:  "SELECT `id` FROM `test_model` WHERE `id` NOT IN (?,?,?);",
-			wantArgs: []interface{}{1, 2, 3},
+			// 读取到一半出错的时候不能返回不完整的数据
+			name: "row error",
+			queryRes: func() (any, error) {
+				return NewSelector[TestModel](db).Select(C("Id")).GetMultiVal(context.Background())
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id` FROM `test_model`;").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).
+						RowError(1, errors.New("row error")))
+			},
+			wantErr: errors.New("row error"),
 		},
 		{
-			// 传入的参数为切片
-			name:     "slice in",
-			builder:  NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In([]int{1, 2, 3})),
-			wantSql:  "SELECT `id` FROM `test_model` WHERE `id` IN (?);",
-			wantArgs: []interface{}{[]int{1, 2, 3}},
+			name: "query error",
+			queryRes: func() (any, error) {
+				return NewSelector[TestModel](db).Select(C("Id")).GetMultiVal(context.Background())
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id` FROM `test_model`;").WillReturnError(errors.New("mock error"))
+			},
+			wantErr: errors.New("mock error"),
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockOrder(mock)
+			res, err := tc.queryRes()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantVal, res)
+		})
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// go test -bench=BenchmarkSelector_GetMultiVal -benchmem
+// 1000 行数据：
+// BenchmarkSelector_GetMultiVal/GetMulti                    676     1785624 ns/op    297070 B/op    9789 allocs/op
+// BenchmarkSelector_GetMultiVal/GetMultiVal                 699     1907012 ns/op    320679 B/op    8789 allocs/op
+// BenchmarkSelector_GetMultiVal/GetMultiVal_large_limit   68750       17691 ns/op      5488 B/op      75 allocs/op
+// GetMultiVal 每一行少了一次分配，但是没有 LIMIT 的时候切片扩容需要复制整个 T，
+// 所以读取大量数据的时候 B/op 反而略高；LIMIT 很大的时候最多只预分配 64 行
+func BenchmarkSelector_GetMultiVal(b *testing.B) {
+	orm := memoryDBWithDB("benchmarkSelectorGetMultiVal")
+	defer func() {
+		_ = orm.Close()
+	}()
+	_ = RawQuery[any](orm, TestModel{}.CreateSQL()).Exec(context.Background())
+	vals := make([]*TestModel, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		vals = append(vals, &TestModel{
+			Id:        int64(i),
+			FirstName: "Deng",
+			Age:       18,
+			LastName:  &sql.NullString{String: "Ming", Valid: true},
+		})
+	}
+	res := NewInserter[TestModel](orm).Values(vals...).Exec(context.Background())
+	if res.Err() != nil {
+		b.Fatal(res.Err())
+	}
+
+	b.Run("GetMulti", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := NewSelector[TestModel](orm).GetMulti(context.Background())
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("GetMultiVal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := NewSelector[TestModel](orm).GetMultiVal(context.Background())
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	// LIMIT 很大但是只返回少量数据的时候，不会按照 LIMIT 预分配
+	b.Run("GetMultiVal large limit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := NewSelector[TestModel](orm).Where(C("Id").LTEQ(3)).Limit(100000).GetMultiVal(context.Background())
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestSelector_Page(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		after     any
+		mockOrder func(mock sqlmock.Sqlmock)
+		wantErr   error
+		wantVal   []*TestModel
+		wantNext  any
+	}{
 		{
-			// in 后面没有值
-			name:    "no in",
-			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In()),
-			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+			name: "first page",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` WHERE `age`>? ORDER BY `id` ASC LIMIT ?;").
+					WithArgs(18, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(1, "Tom").AddRow(3, "Jerry"))
+			},
+			wantVal:  []*TestModel{{Id: 1, FirstName: "Tom"}, {Id: 3, FirstName: "Jerry"}},
+			wantNext: int64(3),
 		},
 		{
-			// Notin 后面没有值
-			name:    "no in",
-			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").NotIn()),
-			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+			name:  "next page",
+			after: int64(3),
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` WHERE (`age`>?) AND (`id`>?) ORDER BY `id` ASC LIMIT ?;").
+					WithArgs(18, int64(3), 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(4, "Da").AddRow(7, "Xiao"))
+			},
+			wantVal:  []*TestModel{{Id: 4, FirstName: "Da"}, {Id: 7, FirstName: "Xiao"}},
+			wantNext: int64(7),
 		},
 		{
-			name:    "in empty slice",
-			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In([]any{}...)),
-			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+			name:  "last page",
+			after: int64(7),
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` WHERE (`age`>?) AND (`id`>?) ORDER BY `id` ASC LIMIT ?;").
+					WithArgs(18, int64(7), 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(9, "Ming"))
+			},
+			wantVal: []*TestModel{{Id: 9, FirstName: "Ming"}},
 		},
 		{
-			name:    "NOT In empty slice",
-			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").NotIn([]any{}...)),
-			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+			name:  "query error",
+			after: int64(7),
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` WHERE (`age`>?) AND (`id`>?) ORDER BY `id` ASC LIMIT ?;").
+					WithArgs(18, int64(7), 2).WillReturnError(errors.New("mock error"))
+			},
+			wantErr: errors.New("mock error"),
 		},
-		// 模糊查询
+	}
+
+	s := NewSelector[TestModel](db).Select(C("Id"), C("FirstName")).Where(C("Age").GT(18))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockOrder(mock)
+			res, next, err := s.Page(context.Background(), "Id", tc.after, 2)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantVal, res)
+			assert.Equal(t, tc.wantNext, next)
+		})
+	}
+
+	// size 不合法的时候不会发起查询
+	for _, size := range []int{0, -1} {
+		res, next, err := s.Page(context.Background(), "Id", nil, size)
+		assert.Equal(t, errs.ErrInvalidPageSize, err)
+		assert.Nil(t, res)
+		assert.Nil(t, next)
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestMapMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type UserDTO struct {
+		Name  string
+		Adult bool
+	}
+	toDTO := func(t *TestModel) UserDTO {
+		return UserDTO{Name: t.FirstName + " " + t.LastName.String, Adult: t.Age >= 18}
+	}
+
+	testCases := []struct {
+		name      string
+		mockOrder func(mock sqlmock.Sqlmock)
+		wantErr   error
+		wantVal   []UserDTO
+	}{
 		{
-			name:    "NOT In empty slice",
-			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").NotIn([]any{}...)),
-			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+			name: "query error",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>?;").
+					WithArgs(10).WillReturnError(errors.New("invalid query"))
+			},
+			wantErr: errors.New("invalid query"),
 		},
 		{
-			name:     "where not like %",
-			builder:  NewSelector[TestModel](db).Where(C("FirstName").NotLike("%ming")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` NOT LIKE ?;",
-			wantArgs: []interface{}{"%ming"},
+			name: "no row",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>?;").
+					WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"}))
+			},
+			wantVal: []UserDTO{},
 		},
 		{
-			name:     "where like %",
-			builder:  NewSelector[TestModel](db).Where(C("FirstName").Like("zhang%")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` LIKE ?;",
-			wantArgs: []interface{}{"zhang%"},
+			name: "multiple rows",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"}).
+					AddRow(1, "Da", 18, "Ming").AddRow(2, "Xiao", 16, "Ming")
+				mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>?;").
+					WithArgs(10).WillReturnRows(rows)
+			},
+			wantVal: []UserDTO{
+				{Name: "Da Ming", Adult: true},
+				{Name: "Xiao Ming", Adult: false},
+			},
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockOrder(mock)
+			res, err := MapMulti[TestModel, UserDTO](context.Background(),
+				NewSelector[TestModel](db).Where(C("Age").GT(10)), toDTO)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantVal, res)
+		})
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_With(t *testing.T) {
+	db := memoryDB()
+	testCases := []CommonTestCase{
 		{
-			name:     "where not like _",
-			builder:  NewSelector[TestModel](db).Where(C("FirstName").NotLike("_三_")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` NOT LIKE ?;",
-			wantArgs: []interface{}{"_三_"},
+			name: "simple",
+			builder: NewSelector[TestModel](db).
+				With("adult", NewSelector[TestModel](db).Where(C("Age").GTEQ(18))).
+				From(CTEOf("adult")).Where(C("FirstName").EQ("Tom")),
+			wantSql:  "WITH `adult` AS (SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>=?) SELECT `id`,`first_name`,`age`,`last_name` FROM `adult` WHERE `first_name`=?;",
+			wantArgs: []interface{}{18, "Tom"},
 		},
 		{
-			name:     "where like _",
-			builder:  NewSelector[TestModel](db).Where(C("FirstName").Like("_三_")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` LIKE ?;",
-			wantArgs: []interface{}{"_三_"},
+			name: "columns",
+			builder: NewSelector[TestModel](db).
+				With("adult", NewSelector[TestModel](db).Select(C("Id"), C("Age")).Where(C("Age").GTEQ(18)), "id", "age").
+				From(CTEOf("adult")).Select(C("Id")).Limit(10),
+			wantSql:  "WITH `adult` (`id`,`age`) AS (SELECT `id`,`age` FROM `test_model` WHERE `age`>=?) SELECT `id` FROM `adult` LIMIT ?;",
+			wantArgs: []interface{}{18, 10},
 		},
 		{
-			name:     "where not like []",
-			builder:  NewSelector[TestModel](db).Where(C("FirstName").NotLike("老[1-9]")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` NOT LIKE ?;",
-			wantArgs: []interface{}{"老[1-9]"},
+			name: "multiple",
+			builder: NewSelector[TestModel](db).
+				With("adult", NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GTEQ(18))).
+				With("tom", NewSelector[TestModel](db).Select(C("Id")).Where(C("FirstName").EQ("Tom"))).
+				From(CTEOf("adult").Join(CTEOf("tom")).Using("Id")).Select(CTEOf("adult").C("Id")),
+			wantSql:  "WITH `adult` AS (SELECT `id` FROM `test_model` WHERE `age`>=?),`tom` AS (SELECT `id` FROM `test_model` WHERE `first_name`=?) SELECT `adult`.`id` FROM (`adult` JOIN `tom` USING (`id`));",
+			wantArgs: []interface{}{18, "Tom"},
 		},
 		{
-			name:     "where like []",
-			builder:  NewSelector[TestModel](db).Where(C("FirstName").Like("老[1-9]")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` LIKE ?;",
-			wantArgs: []interface{}{"老[1-9]"},
+			name: "alias",
+			builder: NewSelector[TestModel](db).
+				With("adult", NewSelector[TestModel](db).Where(C("Age").GTEQ(18))).
+				From(CTEOf("adult").As("a")).Select(CTEOf("adult").As("a").C("Age")),
+			wantSql:  "WITH `adult` AS (SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>=?) SELECT `a`.`age` FROM `adult` AS `a`;",
+			wantArgs: []interface{}{18},
 		},
 		{
-			name:     "where not like [^ ]",
-			builder:  NewSelector[TestModel](db).Where(C("FirstName").NotLike("老[^1-4]")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` NOT LIKE ?;",
-			wantArgs: []interface{}{"老[^1-4]"},
+			name: "postgres",
+			builder: NewSelector[TestModel](postgresDB()).
+				With("adult", NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").GTEQ(18))).
+				From(CTEOf("adult")).Select(C("Id")).Where(C("Id").GT(10)).Limit(5),
+			wantSql:  `WITH "adult" AS (SELECT "id" FROM "test_model" WHERE "age">=$1) SELECT "id" FROM "adult" WHERE "id">$2 LIMIT $3;`,
+			wantArgs: []interface{}{18, 10, 5},
 		},
 		{
-			name:     "where like [^ ]",
-			builder:  NewSelector[TestModel](db).Where(C("FirstName").Like("老[^1-4]")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` LIKE ?;",
-			wantArgs: []interface{}{"老[^1-4]"},
+			name: "postgres materialized",
+			builder: NewSelector[TestModel](postgresDB()).
+				With("adult", NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").GTEQ(18))).Materialized().
+				With("tom", NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("FirstName").EQ("Tom"))).NotMaterialized().
+				From(CTEOf("adult").Join(CTEOf("tom")).Using("Id")).Select(CTEOf("adult").C("Id")),
+			wantSql:  `WITH "adult" AS MATERIALIZED (SELECT "id" FROM "test_model" WHERE "age">=$1),"tom" AS NOT MATERIALIZED (SELECT "id" FROM "test_model" WHERE "first_name"=$2) SELECT "adult"."id" FROM ("adult" JOIN "tom" USING ("id"));`,
+			wantArgs: []interface{}{18, "Tom"},
 		},
-
 		{
-			name:     "where not like int",
-			builder:  NewSelector[TestModel](db).Where(C("Age").NotLike(18)),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age` NOT LIKE ?;",
+			name: "mysql ignore materialized",
+			builder: NewSelector[TestModel](db).
+				With("adult", NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GTEQ(18))).Materialized().
+				From(CTEOf("adult")).Select(C("Id")),
+			wantSql:  "WITH `adult` AS (SELECT `id` FROM `test_model` WHERE `age`>=?) SELECT `id` FROM `adult`;",
 			wantArgs: []interface{}{18},
 		},
 		{
-			name:     "where like int",
-			builder:  NewSelector[TestModel](db).Where(C("Age").Like(22)),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age` LIKE ?;",
-			wantArgs: []interface{}{22},
+			name:     "materialized without cte",
+			builder:  NewSelector[TestModel](postgresDB()).Materialized().Select(C("Id")),
+			wantSql:  `SELECT "id" FROM "test_model";`,
+			wantArgs: nil,
 		},
 		{
-			name:     "having like %",
-			builder:  NewSelector[TestModel](db).GroupBy("FirstName").Having(C("LastName").Like("%li")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING `last_name` LIKE ?;",
-			wantArgs: []interface{}{"%li"},
+			name: "invalid column",
+			builder: NewSelector[TestModel](db).
+				With("adult", NewSelector[TestModel](db).Where(C("Age").GTEQ(18))).
+				From(CTEOf("adult")).Select(CTEOf("adult").C("Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
 		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_WithTotalWindow(t *testing.T) {
+	db := memoryDB()
+	type UserPage struct {
+		Id        int64
+		FirstName string
+		Total     int64
+	}
+	testCases := []CommonTestCase{
 		{
-			name:     "having no like %",
-			builder:  NewSelector[TestModel](db).GroupBy("FirstName").Having(C("LastName").NotLike("%yy%")),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING `last_name` NOT LIKE ?;",
-			wantArgs: []interface{}{"%yy%"},
+			name:     "all columns",
+			builder:  NewSelector[TestModel](db).WithTotalWindow("total").Where(C("Age").GT(18)).Offset(20).Limit(10),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name`,COUNT(*) OVER() AS `total` FROM `test_model` WHERE `age`>? LIMIT ? OFFSET ?;",
+			wantArgs: []interface{}{18, 10, 20},
 		},
 		{
-			name:    "distinct single row",
-			builder: NewSelector[TestModel](db).Distinct().Select(C("FirstName")),
-			wantSql: "SELECT DISTINCT `first_name` FROM `test_model`;",
+			name: "projection",
+			builder: NewSelector[UserPage](db).From(TableOf(&TestModel{})).
+				Select(C("Id"), C("FirstName")).WithTotalWindow("total").OrderBy(ASC("Id")).Limit(10),
+			wantSql:  "SELECT `id`,`first_name`,COUNT(*) OVER() AS `total` FROM `test_model` ORDER BY `id` ASC LIMIT ?;",
+			wantArgs: []interface{}{10},
 		},
 		{
-			name:    "count distinct",
-			builder: NewSelector[TestModel](db).Select(CountDistinct("FirstName")),
-			wantSql: "SELECT COUNT(DISTINCT `first_name`) FROM `test_model`;",
+			name:     "postgres",
+			builder:  NewSelector[TestModel](postgresDB()).Select(C("Id")).WithTotalWindow("total").Limit(10),
+			wantSql:  `SELECT "id",COUNT(*) OVER() AS "total" FROM "test_model" LIMIT $1;`,
+			wantArgs: []interface{}{10},
 		},
 		{
-			name:     "having count distinct",
-			builder:  NewSelector[TestModel](db).Select(C("FirstName")).GroupBy("FirstName").Having(CountDistinct("FirstName").EQ("jack")),
-			wantSql:  "SELECT `first_name` FROM `test_model` GROUP BY `first_name` HAVING COUNT(DISTINCT `first_name`)=?;",
-			wantArgs: []interface{}{"jack"},
+			name:    "distinct",
+			builder: NewSelector[TestModel](db).Distinct().Select(C("FirstName")).WithTotalWindow("total"),
+			wantErr: errs.ErrTotalWindowWithDistinct,
 		},
-		// 子查詢
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_WithNullsOrdering(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
 		{
-			name: "from",
-			builder: func() QueryBuilder {
-				sub := NewSelector[TestModel2](db).AsSubquery("sub")
-				return NewSelector[TestModel](db).From(sub)
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub`;",
+			name:    "nulls last",
+			builder: NewSelector[TestModel](memoryDB()).OrderBy(ASC("Age"), ASC("LastName")).WithNullsOrdering(NullsLast),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`last_name` ASC NULLS LAST;",
 		},
 		{
-			name: "in",
-			builder: func() QueryBuilder {
-				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In(sub))
-			}(),
-			wantSql: "SELECT `id` FROM `test_model` WHERE `id` IN (SELECT `user_id` FROM `test_model2`);",
+			name:    "nulls first",
+			builder: NewSelector[TestModel](memoryDB()).OrderBy(DESC("LastName"), DESC("Id")).WithNullsOrdering(NullsFirst),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `last_name` DESC NULLS FIRST,`id` DESC;",
 		},
 		{
-			name: "all",
-			builder: func() QueryBuilder {
-				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
-				return NewSelector[TestModel](db).Where(C("Id").GT(All(sub)))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `id`>ALL (SELECT `user_id` FROM `test_model2`);",
+			name:    "postgres",
+			builder: NewSelector[TestModel](postgresDB()).OrderBy(ASC("Age"), ASC("LastName")).WithNullsOrdering(NullsLast),
+			wantSql: `SELECT "id","first_name","age","last_name" FROM "test_model" ORDER BY "age" ASC,"last_name" ASC NULLS LAST;`,
 		},
 		{
-			name: "some and any",
-			builder: func() QueryBuilder {
-				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
-				return NewSelector[TestModel](db).Where(C("Id").GT(Some(sub)), C("Id").LT(Any(sub)))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE (`id`>SOME (SELECT `user_id` FROM `test_model2`)) AND (`id`<ANY (SELECT `user_id` FROM `test_model2`));",
+			name:    "mysql nulls last",
+			builder: NewSelector[TestModel](mysqlDB).OrderBy(ASC("Age"), ASC("LastName")).WithNullsOrdering(NullsLast),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,ISNULL(`last_name`),`last_name` ASC;",
 		},
 		{
-			name: "exist",
-			builder: func() QueryBuilder {
-				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
-				return NewSelector[TestModel](db).Where(Exist(sub))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE EXIST (SELECT `user_id` FROM `test_model2`);",
+			name:    "mysql nulls first",
+			builder: NewSelector[TestModel](mysqlDB).OrderBy(ASC("Age"), DESC("LastName")).WithNullsOrdering(NullsFirst),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,ISNULL(`last_name`) DESC,`last_name` DESC;",
 		},
 		{
-			name: "aggregate",
-			builder: func() QueryBuilder {
-				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(Max("Id")).Where(Exist(sub))
-			}(),
-			wantSql: "SELECT MAX(`id`) FROM `test_model` WHERE EXIST (SELECT `user_id` FROM `test_model2`);",
+			name:    "no policy",
+			builder: NewSelector[TestModel](memoryDB()).OrderBy(ASC("Age"), ASC("LastName")),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`last_name` ASC;",
 		},
 		{
-			name: "invalid column",
-			builder: func() QueryBuilder {
-				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(Max("invalid")).Where(Exist(sub))
-			}(),
-			wantErr: errs.NewInvalidFieldError("invalid"),
+			// 在 OrderBy 上指定的时候，不要求列可以为 NULL
+			name:    "order by nulls last",
+			builder: NewSelector[TestModel](memoryDB()).OrderBy(ASC("LastName").NullsLast(), DESC("Age").NullsFirst()),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `last_name` ASC NULLS LAST,`age` DESC NULLS FIRST;",
 		},
 		{
-			name: "not exist",
-			builder: func() QueryBuilder {
-				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
-				return NewSelector[TestModel](db).Where(Not(Exist(sub)))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE NOT (EXIST (SELECT `user_id` FROM `test_model2`));",
+			name: "order by overrides policy",
+			builder: NewSelector[TestModel](postgresDB()).OrderBy(ASC("LastName").NullsFirst(), DESC("Id")).
+				WithNullsOrdering(NullsLast),
+			wantSql: `SELECT "id","first_name","age","last_name" FROM "test_model" ORDER BY "last_name" ASC NULLS FIRST,"id" DESC;`,
 		},
-		// join 查詢
 		{
-			name: "join",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{}).As("t1")
-				t2 := TableOf(&TestModel2{})
-				return NewSelector[TestModel](db).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId"))))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM (`test_model` AS `t1` JOIN `test_model2` ON `t1`.`id`=`user_id`);",
+			name:    "mysql order by nulls last",
+			builder: NewSelector[TestModel](mysqlDB).OrderBy(ASC("LastName").NullsLast(), DESC("Age").NullsFirst()),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY ISNULL(`last_name`),`last_name` ASC,ISNULL(`age`) DESC,`age` DESC;",
 		},
 		{
-			name: "multiple join",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{}).As("t1")
-				t2 := TableOf(&TestModel2{}).As("t2")
-				t3 := TableOf(&TestModel3{}).As("t3")
-				return NewSelector[TestModel](db).
-					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId"))).Join(t3).On(t2.C("UserId").EQ(t3.C("Id"))))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM ((`test_model` AS `t1` JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`) JOIN `test_model3` AS `t3` ON `t2`.`user_id`=`t3`.`id`);",
+			name:     "expr nulls last",
+			builder:  NewSelector[TestModel](postgresDB()).OrderBy(DESCExpr(C("Age").Add(1)).NullsLast()),
+			wantSql:  `SELECT "id","first_name","age","last_name" FROM "test_model" ORDER BY "age"+$1 DESC NULLS LAST;`,
+			wantArgs: []interface{}{1},
 		},
 		{
-			name: "left multiple join",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{}).As("t1")
-				t2 := TableOf(&TestModel2{}).As("t2")
-				t3 := TableOf(&TestModel3{}).As("t3")
-				return NewSelector[TestModel](db).
-					From(t1.LeftJoin(t2).On(t1.C("Id").EQ(t2.C("UserId"))).LeftJoin(t3).On(t2.C("UserId").EQ(t3.C("Id"))))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM ((`test_model` AS `t1` LEFT JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`) LEFT JOIN `test_model3` AS `t3` ON `t2`.`user_id`=`t3`.`id`);",
+			name:     "mysql expr nulls first",
+			builder:  NewSelector[TestModel](mysqlDB).OrderBy(ASCExpr(C("Age").Add(1)).NullsFirst()),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY ISNULL(`age`+?) DESC,`age`+? ASC;",
+			wantArgs: []interface{}{1, 1},
 		},
 		{
-			name: "right multiple join",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{}).As("t1")
-				t2 := TableOf(&TestModel2{}).As("t2")
-				t3 := TableOf(&TestModel3{}).As("t3")
-				return NewSelector[TestModel](db).
-					From(t1.RightJoin(t2).On(t1.C("Id").EQ(t2.C("UserId"))).RightJoin(t3).On(t2.C("UserId").EQ(t3.C("Id"))))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM ((`test_model` AS `t1` RIGHT JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`) RIGHT JOIN `test_model3` AS `t3` ON `t2`.`user_id`=`t3`.`id`);",
+			name:    "mysql multiple fields",
+			builder: NewSelector[TestModel](mysqlDB).OrderBy(ASC("LastName", "Age").NullsFirst()),
+			wantErr: errs.ErrNullsOrderingMultipleFields,
 		},
 		{
-			name: "join & using",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{}).As("t1")
-				t2 := TableOf(&TestModel2{})
-				return NewSelector[TestModel](db).
-					From(t1.Join(t2).Using("FirstName", "LastName"))
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM (`test_model` AS `t1` JOIN `test_model2` USING (`first_name`,`last_name`));",
-		},
-		// Join 與 Subquery 一起使用測試
-		{
-			name: "join & subquery",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{})
-				sub := NewSelector[TestModel2](db).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(sub.C("UserId")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
-			}(),
-			wantSql: "SELECT `sub`.`user_id` FROM (`test_model` JOIN (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub` ON `id`=`sub`.`user_id`);",
-		},
-		{
-			name: "left join & subquery",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{})
-				sub := NewSelector[TestModel2](db).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(sub.C("UserId")).From(t1.LeftJoin(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
-			}(),
-			wantSql: "SELECT `sub`.`user_id` FROM (`test_model` LEFT JOIN (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub` ON `id`=`sub`.`user_id`);",
-		},
-		{
-			name: "right join & subquery",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{})
-				sub := NewSelector[TestModel2](db).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(sub.C("UserId")).From(t1.RightJoin(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
-			}(),
-			wantSql: "SELECT `sub`.`user_id` FROM (`test_model` RIGHT JOIN (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub` ON `id`=`sub`.`user_id`);",
+			name:    "postgres multiple fields",
+			builder: NewSelector[TestModel](postgresDB()).OrderBy(DESC("LastName", "Age").NullsLast()),
+			wantErr: errs.ErrNullsOrderingMultipleFields,
 		},
 		{
-			name: "right join & subquery & using",
-			builder: func() QueryBuilder {
-				sub1 := NewSelector[TestModel2](db).AsSubquery("sub1")
-				sub2 := NewSelector[TestModel2](db).AsSubquery("sub2")
-				return NewSelector[TestModel](db).Select(sub1.C("UserId")).From(sub1.RightJoin(sub2).Using("Id")).Where()
-			}(),
-			wantSql: "SELECT `sub1`.`user_id` FROM ((SELECT `user_id`,`phone` FROM `test_model2`) AS `sub1` RIGHT JOIN (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub2` USING (`id`));",
+			name: "postgres multiple orders",
+			builder: NewSelector[TestModel](postgresDB()).Select(C("Id")).
+				OrderBy(ASC("LastName").NullsFirst(), ASC("Age").NullsFirst()),
+			wantSql: `SELECT "id" FROM "test_model" ORDER BY "last_name" ASC NULLS FIRST,"age" ASC NULLS FIRST;`,
 		},
 		{
-			name: "join & subquery & using",
-			builder: func() QueryBuilder {
-				sub1 := NewSelector[TestModel2](db).AsSubquery("sub1")
-				sub2 := NewSelector[TestModel2](db).From(sub1).AsSubquery("sub2")
-				t1 := TableOf(&TestModel{}).As("o1")
-				return NewSelector[TestModel](db).From(sub2.Join(t1).Using("Id")).Where()
-			}(),
-			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM ((SELECT `user_id`,`phone` FROM (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub1`) AS `sub2` JOIN `test_model` AS `o1` USING (`id`));",
+			name: "mysql multiple orders",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				OrderBy(ASC("LastName").NullsFirst(), ASC("Age").NullsFirst()),
+			wantSql: "SELECT `id` FROM `test_model` ORDER BY ISNULL(`last_name`) DESC,`last_name` ASC,ISNULL(`age`) DESC,`age` ASC;",
 		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_FullJoin(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	type TestModel2 struct {
+		UserId int64
+		Phone  int64
+	}
+	t1 := TableOf(&TestModel{}).As("t1")
+	t2 := TableOf(&TestModel2{}).As("t2")
+	testCases := []CommonTestCase{
 		{
-			name: "invalid field",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{})
-				sub := NewSelector[TestModel2](db).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(sub.C("Invalid")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
-			}(),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name: "on",
+			builder: NewSelector[TestModel](pg).Select(t1.C("Id"), t2.C("Phone")).
+				From(t1.FullJoin(t2).On(t1.C("Id").EQ(t2.C("UserId")))).Where(t1.C("Age").GT(18)),
+			wantSql:  `SELECT "t1"."id","t2"."phone" FROM ("test_model" AS "t1" FULL OUTER JOIN "test_model2" AS "t2" ON "t1"."id"="t2"."user_id") WHERE "t1"."age">$1;`,
+			wantArgs: []interface{}{18},
 		},
 		{
-			name: "invalid field in predicates",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{})
-				sub := NewSelector[TestModel2](db).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(sub.C("ItemId")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("Invalid")))).Where()
-			}(),
-			wantErr: errs.NewInvalidFieldError("ItemId"),
+			name:    "using",
+			builder: NewSelector[TestModel](pg).Select(t1.C("Id")).From(t1.FullJoin(t2).Using("Id")),
+			wantSql: `SELECT "t1"."id" FROM ("test_model" AS "t1" FULL OUTER JOIN "test_model2" AS "t2" USING ("id"));`,
 		},
 		{
-			name: "invalid field in predicates with columns",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{})
-				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(sub.C("ItemId")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
-			}(),
-			wantErr: errs.NewInvalidFieldError("ItemId"),
+			name: "join then full join",
+			builder: NewSelector[TestModel](pg).Select(t1.C("Id")).
+				From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId"))).FullJoin(TableOf(&TestModel{}).As("t3")).Using("Id")),
+			wantSql: `SELECT "t1"."id" FROM (("test_model" AS "t1" JOIN "test_model2" AS "t2" ON "t1"."id"="t2"."user_id") FULL OUTER JOIN "test_model" AS "t3" USING ("id"));`,
 		},
 		{
-			name: "invalid field in aggregate function",
-			builder: func() QueryBuilder {
-				t1 := TableOf(&TestModel{})
-				sub := NewSelector[TestModel2](db).AsSubquery("sub")
-				return NewSelector[TestModel](db).Select(Max("Invalid")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
-			}(),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name:    "mysql",
+			builder: NewSelector[TestModel](mysqlDB).From(t1.FullJoin(t2).On(t1.C("Id").EQ(t2.C("UserId")))),
+			wantErr: errs.NewUnsupportedJoinError("FULL OUTER JOIN", "MySQL"),
 		},
 	}
 
@@ -1216,103 +1706,299 @@ func TestSelectable(t *testing.T) {
 	}
 }
 
-func TestSelectableCombination(t *testing.T) {
+func TestFromRawQuery(t *testing.T) {
 	db := memoryDB()
+	pg := postgresDB()
+	built, err := NewSelector[TestModel](db).Select(C("Id"), C("Age")).Where(C("Age").GT(18)).Build()
+	require.NoError(t, err)
+	raw := FromRawQuery(&Query{
+		SQL:  "SELECT `id`,`first_name` FROM `test_model` WHERE `first_name` LIKE ? ",
+		Args: []any{"T%"},
+	}, "sub")
 	testCases := []CommonTestCase{
 		{
-			name:    "simple",
-			builder: NewSelector[TestCombinedModel](db),
-			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model`;",
+			name: "built query",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				From(FromRawQuery(built, "sub")).Where(C("Age").LT(60)),
+			wantSql:  "SELECT `id` FROM (SELECT `id`,`age` FROM `test_model` WHERE `age`>?) AS `sub` WHERE `age`<?;",
+			wantArgs: []interface{}{18, 60},
 		},
 		{
-			name:    "columns",
-			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id", "FirstName", "CreateTime")),
-			wantSql: "SELECT `id`,`first_name`,`create_time` FROM `test_combined_model`;",
+			name:     "hand-written query",
+			builder:  NewSelector[TestModel](db).Select(raw.C("FirstName")).From(raw),
+			wantSql:  "SELECT `sub`.`first_name` FROM (SELECT `id`,`first_name` FROM `test_model` WHERE `first_name` LIKE ?) AS `sub`;",
+			wantArgs: []interface{}{"T%"},
 		},
 		{
-			name:    "alias",
-			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id"), C("CreateTime").As("creation")),
-			wantSql: "SELECT `id`,`create_time` AS `creation` FROM `test_combined_model`;",
+			name: "join",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				From(TableOf(&TestModel{}).Join(FromRawQuery(built, "sub")).Using("Id")).Where(C("FirstName").EQ("Tom")),
+			wantSql:  "SELECT `id` FROM (`test_model` JOIN (SELECT `id`,`age` FROM `test_model` WHERE `age`>?) AS `sub` USING (`id`)) WHERE `first_name`=?;",
+			wantArgs: []interface{}{18, "Tom"},
 		},
 		{
-			name:    "aggregate",
-			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id"), Max("CreateTime").As("max_time")),
-			wantSql: "SELECT `id`,MAX(`create_time`) AS `max_time` FROM `test_combined_model`;",
+			name: "postgres",
+			builder: NewSelector[TestModel](pg).Select(C("Id")).Where(C("Age").LT(60)).
+				From(FromRawQuery(&Query{
+					SQL:  `SELECT "id","age" FROM "test_model" WHERE "age">$1 AND "first_name"=$2;`,
+					Args: []any{18, "Tom"},
+				}, "sub")),
+			wantSql:  `SELECT "id" FROM (SELECT "id","age" FROM "test_model" WHERE "age">$1 AND "first_name"=$2) AS "sub" WHERE "age"<$3;`,
+			wantArgs: []interface{}{18, "Tom", 60},
 		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_ExplicitInnerJoin(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB, DBWithExplicitInnerJoin())
+	require.NoError(t, err)
+	type TestModel2 struct {
+		UserId int64
+		Phone  int64
+	}
+	t1 := TableOf(&TestModel{}).As("t1")
+	t2 := TableOf(&TestModel2{}).As("t2")
+	testCases := []CommonTestCase{
 		{
-			name:    "raw",
-			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id"), Raw("AVG(DISTINCT `create_time`)")),
-			wantSql: "SELECT `id`,AVG(DISTINCT `create_time`) FROM `test_combined_model`;",
+			name: "join",
+			builder: NewSelector[TestModel](db).Select(t1.C("Id"), t2.C("Phone")).
+				From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId")))),
+			wantSql: "SELECT `t1`.`id`,`t2`.`phone` FROM (`test_model` AS `t1` INNER JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`);",
 		},
 		{
-			name:    "invalid columns",
-			builder: NewSelector[TestCombinedModel](db).Select(Columns("Invalid"), Raw("AVG(DISTINCT `age`)")),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name: "left join",
+			builder: NewSelector[TestModel](db).Select(t1.C("Id")).
+				From(t1.LeftJoin(t2).On(t1.C("Id").EQ(t2.C("UserId")))),
+			wantSql: "SELECT `t1`.`id` FROM (`test_model` AS `t1` LEFT JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`);",
 		},
 		{
-			name:    "order by",
-			builder: NewSelector[TestCombinedModel](db).OrderBy(ASC("Age"), DESC("CreateTime")),
-			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC;",
+			name: "join then right join",
+			builder: NewSelector[TestModel](db).Select(t1.C("Id")).
+				From(t1.Join(t2).Using("Id").RightJoin(TableOf(&TestModel{}).As("t3")).Using("Id")),
+			wantSql: "SELECT `t1`.`id` FROM ((`test_model` AS `t1` INNER JOIN `test_model2` AS `t2` USING (`id`)) RIGHT JOIN `test_model` AS `t3` USING (`id`));",
 		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_CalcFoundRows(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	// 两个语句必须在同一个连接上执行
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	mock.ExpectQuery("SELECT SQL_CALC_FOUND_ROWS `id`,`first_name` FROM `test_model` WHERE `age`>? LIMIT ?;").
+		WithArgs(18, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(1, "Tom").AddRow(2, "Jerry"))
+	mock.ExpectQuery("SELECT FOUND_ROWS();").
+		WillReturnRows(sqlmock.NewRows([]string{"FOUND_ROWS()"}).AddRow(10))
+
+	s := NewSelector[TestModel](conn).Select(C("Id"), C("FirstName")).
+		Where(C("Age").GT(18)).Limit(2).CalcFoundRows()
+	res, err := s.GetMulti(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*TestModel{{Id: 1, FirstName: "Tom"}, {Id: 2, FirstName: "Jerry"}}, res)
+	total, err := s.FoundRows(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), total)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// 不在固定的连接上
+	_, err = NewSelector[TestModel](db).CalcFoundRows().FoundRows(context.Background())
+	assert.Equal(t, errs.ErrFoundRowsWithoutConn, err)
+
+	// 只有 MySQL 支持
+	_, err = NewSelector[TestModel](postgresDB()).CalcFoundRows().Build()
+	assert.Equal(t, errs.NewUnsupportedFeatureError("PostgreSQL", "SQL_CALC_FOUND_ROWS"), err)
+}
+
+func TestTable_LeftJoinLateralTop1(t *testing.T) {
+	pg := postgresDB()
+	type Order struct {
+		Id         int64
+		UserId     int64
+		Amount     int64
+		CreateTime int64
+	}
+	u := TableOf(&TestModel{}).As("u")
+	o := TableOf(&Order{})
+	latest := NewSelector[Order](pg).Select(C("Id"), C("Amount")).
+		Where(C("UserId").EQ(u.C("Id")), C("Amount").GT(100)).AsSubquery("latest")
+	testCases := []CommonTestCase{
 		{
-			name:    "order by invalid column",
-			builder: NewSelector[TestCombinedModel](db).OrderBy(ASC("Invalid"), DESC("Id")),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name: "latest order",
+			builder: NewSelector[TestModel](pg).Select(u.C("Id"), latest.C("Amount")).
+				From(u.LeftJoinLateralTop1(latest, []OrderBy{DESC("CreateTime")})).Where(u.C("Age").GT(18)),
+			wantSql: `SELECT "u"."id","latest"."amount" FROM ("test_model" AS "u" LEFT JOIN LATERAL ` +
+				`(SELECT "id","amount" FROM "order" WHERE ("user_id"="u"."id") AND ("amount">$1) ORDER BY "create_time" DESC LIMIT $2) AS "latest" ON TRUE) ` +
+				`WHERE "u"."age">$3;`,
+			wantArgs: []interface{}{100, 1, 18},
 		},
 		{
-			name:    "group by",
-			builder: NewSelector[TestCombinedModel](db).GroupBy("CreateTime", "Id"),
-			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` GROUP BY `create_time`,`id`;",
+			name: "after join",
+			builder: NewSelector[TestModel](pg).Select(u.C("Id")).
+				From(u.Join(o).On(u.C("Id").EQ(o.C("UserId"))).LeftJoinLateralTop1(latest, []OrderBy{DESC("CreateTime"), ASC("Id")})),
+			wantSql: `SELECT "u"."id" FROM (("test_model" AS "u" JOIN "order" ON "u"."id"="user_id") LEFT JOIN LATERAL ` +
+				`(SELECT "id","amount" FROM "order" WHERE ("user_id"="u"."id") AND ("amount">$1) ORDER BY "create_time" DESC,"id" ASC LIMIT $2) AS "latest" ON TRUE);`,
+			wantArgs: []interface{}{100, 1},
 		},
 		{
-			name:    "group by invalid column",
-			builder: NewSelector[TestCombinedModel](db).GroupBy("Invalid", "Id"),
+			name: "invalid order by",
+			builder: NewSelector[TestModel](pg).Select(u.C("Id")).
+				From(u.LeftJoinLateralTop1(latest, []OrderBy{DESC("Invalid")})),
 			wantErr: errs.NewInvalidFieldError("Invalid"),
 		},
 		{
-			name:     "offset",
-			builder:  NewSelector[TestCombinedModel](db).OrderBy(ASC("Age"), DESC("CreateTime")).Offset(10),
-			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC OFFSET ?;",
-			wantArgs: []interface{}{10},
+			name: "sqlite",
+			builder: NewSelector[TestModel](memoryDB()).Select(u.C("Id")).
+				From(u.LeftJoinLateralTop1(latest, []OrderBy{DESC("CreateTime")})),
+			wantErr: errs.NewUnsupportedJoinError("LEFT JOIN LATERAL", "SQLite"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_DialectFeatures(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	ti, err := openDB("mysql", mockDB, DBWithDialect(tidb{Dialect: dialect.MySQL}))
+	require.NoError(t, err)
+	ss, err := openDB("sqlserver", mockDB, DBWithDialect(sqlServer{}))
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			// 名字不是 MySQL，但是声明了和 MySQL 一样的特性
+			name: "custom dialect with features",
+			builder: NewSelector[TestModel](ti).From(TableOf(&TestModel{}).UseIndex("idx_age")).
+				OrderBy(ASC("Age").NullsFirst()),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` USE INDEX (`idx_age`) ORDER BY ISNULL(`age`) DESC,`age` ASC;",
 		},
 		{
-			name:     "limit",
-			builder:  NewSelector[TestCombinedModel](db).OrderBy(ASC("Age"), DESC("CreateTime")).Offset(10).Limit(100),
-			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC OFFSET ? LIMIT ?;",
-			wantArgs: []interface{}{10, 100},
+			// 没有声明特性的方言只使用标准 SQL 的写法
+			name:    "custom dialect without features",
+			builder: NewSelector[TestModel](ss).OrderBy(ASC("Age").NullsFirst()),
+			wantSql: "SELECT [id],[first_name],[age],[last_name] FROM [test_model] ORDER BY [age] ASC NULLS FIRST;",
 		},
 		{
-			name:     "where",
-			builder:  NewSelector[TestCombinedModel](db).Where(C("Id").EQ(10).And(C("CreateTime").EQ(10))),
-			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` WHERE (`id`=?) AND (`create_time`=?);",
-			wantArgs: []interface{}{10, 10},
+			name:    "custom dialect without index hints",
+			builder: NewSelector[TestModel](ss).From(TableOf(&TestModel{}).UseIndex("idx_age")),
+			wantErr: errs.NewUnsupportedFeatureError("SQLServer", "USE INDEX"),
 		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestTable_Apply(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	ss, err := openDB("sqlserver", mockDB, DBWithDialect(sqlServer{}))
+	require.NoError(t, err)
+	pg := postgresDB()
+	type Order struct {
+		Id     int64
+		UserId int64
+		Amount int64
+	}
+	u := TableOf(&TestModel{}).As("u")
+	o := TableOf(&Order{}).As("o")
+	sub := func(db *DB) Subquery {
+		return NewSelector[Order](db).Select(C("Id"), C("Amount")).
+			Where(C("UserId").EQ(u.C("Id")), C("Amount").GT(100)).AsSubquery("big")
+	}
+	testCases := []CommonTestCase{
 		{
-			name:    "no where",
-			builder: NewSelector[TestCombinedModel](db).Where(),
-			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model`;",
+			name: "sql server cross apply",
+			builder: NewSelector[TestModel](ss).Select(u.C("Id"), sub(ss).C("Amount")).
+				From(u.CrossApply(sub(ss))).Where(u.C("Age").GT(18)),
+			wantSql: "SELECT [u].[id],[big].[amount] FROM ([test_model] AS [u] CROSS APPLY " +
+				"(SELECT [id],[amount] FROM [order] WHERE ([user_id]=[u].[id]) AND ([amount]>@p1)) AS [big]) WHERE [u].[age]>@p2;",
+			wantArgs: []interface{}{100, 18},
 		},
 		{
-			name:     "having",
-			builder:  NewSelector[TestCombinedModel](db).GroupBy("FirstName").Having(Max("CreateTime").EQ(18)),
-			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` GROUP BY `first_name` HAVING MAX(`create_time`)=?;",
-			wantArgs: []interface{}{18},
+			name: "sql server outer apply after join",
+			builder: NewSelector[TestModel](ss).Select(u.C("Id")).
+				From(u.Join(o).On(u.C("Id").EQ(o.C("UserId"))).OuterApply(sub(ss))),
+			wantSql: "SELECT [u].[id] FROM (([test_model] AS [u] JOIN [order] AS [o] ON [u].[id]=[o].[user_id]) OUTER APPLY " +
+				"(SELECT [id],[amount] FROM [order] WHERE ([user_id]=[u].[id]) AND ([amount]>@p1)) AS [big]);",
+			wantArgs: []interface{}{100},
 		},
 		{
-			name:    "no having",
-			builder: NewSelector[TestCombinedModel](db).GroupBy("CreateTime").Having(),
-			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` GROUP BY `create_time`;",
+			name: "postgres cross apply",
+			builder: NewSelector[TestModel](pg).Select(u.C("Id"), sub(pg).C("Amount")).
+				From(u.CrossApply(sub(pg))).Where(u.C("Age").GT(18)),
+			wantSql: `SELECT "u"."id","big"."amount" FROM ("test_model" AS "u" JOIN LATERAL ` +
+				`(SELECT "id","amount" FROM "order" WHERE ("user_id"="u"."id") AND ("amount">$1)) AS "big" ON TRUE) WHERE "u"."age">$2;`,
+			wantArgs: []interface{}{100, 18},
 		},
 		{
-			name:     "alias in having",
-			builder:  NewSelector[TestCombinedModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("CreateTime").As("create")).GroupBy("FirstName").Having(C("create").LT(20)),
-			wantSql:  "SELECT `id`,`first_name`,AVG(`create_time`) AS `create` FROM `test_combined_model` GROUP BY `first_name` HAVING `create`<?;",
-			wantArgs: []interface{}{20},
+			name: "postgres outer apply",
+			builder: NewSelector[TestModel](pg).Select(u.C("Id")).
+				From(u.OuterApply(sub(pg))),
+			wantSql: `SELECT "u"."id" FROM ("test_model" AS "u" LEFT JOIN LATERAL ` +
+				`(SELECT "id","amount" FROM "order" WHERE ("user_id"="u"."id") AND ("amount">$1)) AS "big" ON TRUE);`,
+			wantArgs: []interface{}{100},
 		},
 		{
-			name:    "invalid alias in having",
-			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("Invalid").LT(20)),
-			wantErr: errs.NewInvalidFieldError("Invalid"),
+			name: "sqlite",
+			builder: NewSelector[TestModel](memoryDB()).Select(u.C("Id")).
+				From(u.CrossApply(sub(memoryDB()))),
+			wantErr: errs.NewUnsupportedJoinError("CROSS APPLY", "SQLite"),
 		},
 	}
 
@@ -1330,23 +2016,1063 @@ func TestSelectableCombination(t *testing.T) {
 	}
 }
 
-type BaseEntity struct {
-	CreateTime uint64
-	UpdateTime uint64
-}
-
-type TestCombinedModel struct {
-	BaseEntity
-	Id        int64 `eorm:"auto_increment,primary_key"`
-	FirstName string
-	Age       int8
-	LastName  *string
-}
-
-func ExampleSelector_OrderBy() {
-	db := memoryDB()
-	query, _ := NewSelector[TestModel](db).OrderBy(ASC("Age")).Build()
-	fmt.Printf("case1\n%s", query.string())
+func TestSelector_Lock(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "for update",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(C("Id").EQ(1)).ForUpdate(),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `id`=? FOR UPDATE;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "for share",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(C("Id").EQ(1)).Offset(10).Limit(5).ForShare(),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `id`=? LIMIT ? OFFSET ? LOCK IN SHARE MODE;",
+			wantArgs: []interface{}{1, 5, 10},
+		},
+		{
+			name:     "postgres for update",
+			builder:  NewSelector[TestModel](pg).Select(C("Id")).Where(C("Id").EQ(1)).Limit(1).ForUpdate(),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "id"=$1 LIMIT $2 FOR UPDATE;`,
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name:     "postgres for share",
+			builder:  NewSelector[TestModel](pg).Select(C("Id")).Where(C("Id").EQ(1)).ForShare(),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "id"=$1 FOR SHARE;`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "conflict",
+			builder: NewSelector[TestModel](mysqlDB).ForShare().ForUpdate(),
+			wantErr: errs.ErrLockModeConflict,
+		},
+		{
+			name:    "for update with group by",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Age")).GroupBy("Age").ForUpdate(),
+			wantErr: errs.ErrLockWithAggregation,
+		},
+		{
+			name:    "for update with group by expr",
+			builder: NewSelector[TestModel](pg).Select(C("Age")).GroupByExpr(Raw("DATE(`last_name`)")).ForUpdate(),
+			wantErr: errs.ErrLockWithAggregation,
+		},
+		{
+			name:    "for share with distinct",
+			builder: NewSelector[TestModel](pg).Select(C("Age")).Distinct().ForShare(),
+			wantErr: errs.ErrLockWithAggregation,
+		},
+		{
+			name:    "for update with aggregate",
+			builder: NewSelector[TestModel](mysqlDB).Select(Max("Age")).Where(C("Id").GT(1)).ForUpdate(),
+			wantErr: errs.ErrLockWithAggregation,
+		},
+		{
+			name:    "for update with group concat",
+			builder: NewSelector[TestModel](mysqlDB).Select(GroupConcat("FirstName")).ForUpdate(),
+			wantErr: errs.ErrLockWithAggregation,
+		},
+		{
+			name:     "for update with plain columns",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id"), C("Age")).Where(C("Age").GT(18)).ForUpdate(),
+			wantSql:  "SELECT `id`,`age` FROM `test_model` WHERE `age`>? FOR UPDATE;",
+			wantArgs: []interface{}{18},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_MaxParams(t *testing.T) {
+	db := postgresDB()
+	db.dialect = limitedDialect{Dialect: db.dialect, maxParams: 3}
+	testCases := []CommonTestCase{
+		{
+			name:     "under limit",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(C("Id").In(1, 2, 3)),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "id" IN ($1,$2,$3);`,
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name:    "exceed limit",
+			builder: NewSelector[TestModel](db).Select(C("Id")).Where(C("Id").In(1, 2, 3)).Limit(10),
+			wantErr: errs.NewTooManyParametersError("PostgreSQL", 4, 3),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_OrderByField(t *testing.T) {
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:     "mysql",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).OrderBy(OrderByField("Id", 3, 1, 2)),
+			wantSql:  "SELECT `id` FROM `test_model` ORDER BY FIELD(`id`,?,?,?) ASC;",
+			wantArgs: []interface{}{3, 1, 2},
+		},
+		{
+			name: "mysql with where and limit",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(C("Age").GT(18)).
+				OrderBy(OrderByField("Id", 3, 1), DESC("Age")).Limit(10),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `age`>? ORDER BY FIELD(`id`,?,?) ASC,`age` DESC LIMIT ?;",
+			wantArgs: []interface{}{18, 3, 1, 10},
+		},
+		{
+			name:     "postgres",
+			builder:  NewSelector[TestModel](pg).Select(C("Id")).OrderBy(OrderByField("Id", 3, 1, 2)),
+			wantSql:  `SELECT "id" FROM "test_model" ORDER BY CASE "id" WHEN $1 THEN 1 WHEN $2 THEN 2 WHEN $3 THEN 3 ELSE 0 END ASC;`,
+			wantArgs: []interface{}{3, 1, 2},
+		},
+		{
+			name: "postgres with where and limit",
+			builder: NewSelector[TestModel](pg).Select(C("Id")).Where(C("Age").GT(18)).
+				OrderBy(OrderByField("Id", 3, 1)).Limit(10),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "age">$1 ORDER BY CASE "id" WHEN $2 THEN 1 WHEN $3 THEN 2 ELSE 0 END ASC LIMIT $4;`,
+			wantArgs: []interface{}{18, 3, 1, 10},
+		},
+		{
+			name:    "no values",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).OrderBy(OrderByField("Id")),
+			wantSql: "SELECT `id` FROM `test_model` ORDER BY `id` ASC;",
+		},
+		{
+			name:    "invalid field",
+			builder: NewSelector[TestModel](mysqlDB).OrderBy(OrderByField("Invalid", 1)),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+// wideModel 有很多列，用于测试构造大的查询
+type wideModel struct {
+	Id         int64
+	FirstName  string
+	LastName   string
+	MiddleName string
+	Age        int
+	Height     int
+	Weight     int
+	Score      int
+	Level      int
+	Email      string
+	Phone      string
+	Address    string
+	City       string
+	Country    string
+	ZipCode    string
+	Company    string
+	Title      string
+	Department string
+	CreateTime int64
+	UpdateTime int64
+}
+
+func wideSelector(db *DB) *Selector[wideModel] {
+	fields := []string{"Id", "FirstName", "LastName", "MiddleName", "Age", "Height", "Weight",
+		"Score", "Level", "Email", "Phone", "Address", "City", "Country", "ZipCode", "Company",
+		"Title", "Department", "CreateTime", "UpdateTime"}
+	ps := make([]Predicate, 0, len(fields))
+	for i, f := range fields {
+		ps = append(ps, C(f).EQ(i))
+	}
+	return NewSelector[wideModel](db).Where(ps...).Limit(10)
+}
+
+func TestSelector_Build_wide(t *testing.T) {
+	db := memoryDB()
+	// 预先扩容不会影响构造出来的 SQL
+	for i := 0; i < 3; i++ {
+		query, err := wideSelector(db).Build()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT `id`,`first_name`,`last_name`,`middle_name`,`age`,`height`,`weight`,`score`,`level`,`email`,"+
+			"`phone`,`address`,`city`,`country`,`zip_code`,`company`,`title`,`department`,`create_time`,`update_time` "+
+			"FROM `wide_model` WHERE (((((((((((((((((((`id`=?) AND (`first_name`=?)) AND (`last_name`=?)) AND (`middle_name`=?)) "+
+			"AND (`age`=?)) AND (`height`=?)) AND (`weight`=?)) AND (`score`=?)) AND (`level`=?)) AND (`email`=?)) "+
+			"AND (`phone`=?)) AND (`address`=?)) AND (`city`=?)) AND (`country`=?)) AND (`zip_code`=?)) AND (`company`=?)) "+
+			"AND (`title`=?)) AND (`department`=?)) AND (`create_time`=?)) AND (`update_time`=?) LIMIT ?;", query.SQL)
+		assert.Equal(t, []any{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 10}, query.Args)
+	}
+}
+
+// go test -bench=BenchmarkSelector_Build_wide -benchmem
+// 预先扩容 buffer 和 args 之前：
+// BenchmarkSelector_Build_wide             164458              7486 ns/op            7472 B/op         88 allocs/op
+// 预先扩容 buffer 和 args 之后：
+// BenchmarkSelector_Build_wide             195991              6242 ns/op            6864 B/op         85 allocs/op
+func BenchmarkSelector_Build_wide(b *testing.B) {
+	db := memoryDB()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = wideSelector(db).Build()
+	}
+}
+
+func TestSelectable(t *testing.T) {
+	db := memoryDB()
+	type TestModel2 struct {
+		UserId int64
+		Phone  int64
+	}
+	type TestModel3 struct {
+		Id int64
+	}
+	testCases := []CommonTestCase{
+		{
+			name:    "simple",
+			builder: NewSelector[TestModel](db),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;",
+		},
+		{
+			name:    "columns",
+			builder: NewSelector[TestModel](db).Select(Columns("Id", "FirstName")),
+			wantSql: "SELECT `id`,`first_name` FROM `test_model`;",
+		},
+		{
+			name:    "view",
+			builder: NewSelector[TestModelView](db),
+			wantSql: "SELECT `id`,`first_name` FROM `test_model_view`;",
+		},
+		{
+			name:    "alias",
+			builder: NewSelector[TestModel](db).Select(Columns("Id"), C("FirstName").As("name")),
+			wantSql: "SELECT `id`,`first_name` AS `name` FROM `test_model`;",
+		},
+		{
+			name:    "aggregate",
+			builder: NewSelector[TestModel](db).Select(Columns("Id"), Avg("Age").As("avg_age")),
+			wantSql: "SELECT `id`,AVG(`age`) AS `avg_age` FROM `test_model`;",
+		},
+		{
+			name:    "raw",
+			builder: NewSelector[TestModel](db).Select(Columns("Id"), Raw("AVG(DISTINCT `age`)")),
+			wantSql: "SELECT `id`,AVG(DISTINCT `age`) FROM `test_model`;",
+		},
+		{
+			name:    "invalid columns",
+			builder: NewSelector[TestModel](db).Select(Columns("Invalid"), Raw("AVG(DISTINCT `age`)")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "order by",
+			builder: NewSelector[TestModel](db).OrderBy(ASC("Age"), DESC("Id")),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC;",
+		},
+		{
+			name:    "order by invalid column",
+			builder: NewSelector[TestModel](db).OrderBy(ASC("Invalid"), DESC("Id")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name: "order by aggregate alias",
+			builder: NewSelector[TestModel](db).Select(C("FirstName"), Avg("Age").As("avg_age")).
+				GroupBy("FirstName").OrderBy(DESC("avg_age")),
+			wantSql: "SELECT `first_name`,AVG(`age`) AS `avg_age` FROM `test_model` GROUP BY `first_name` ORDER BY `avg_age` DESC;",
+		},
+		{
+			name: "order by aggregate alias and column",
+			builder: NewSelector[TestModel](db).Select(C("FirstName"), Max("Age").As("max_age")).
+				GroupBy("FirstName").OrderBy(DESC("max_age"), ASC("FirstName")),
+			wantSql: "SELECT `first_name`,MAX(`age`) AS `max_age` FROM `test_model` GROUP BY `first_name` ORDER BY `max_age` DESC,`first_name` ASC;",
+		},
+		{
+			name:    "order by unknown alias",
+			builder: NewSelector[TestModel](db).Select(Avg("Age").As("avg_age")).OrderBy(DESC("max_age")),
+			wantErr: errs.NewInvalidFieldError("max_age"),
+		},
+		{
+			name:    "group by",
+			builder: NewSelector[TestModel](db).GroupBy("Age", "Id"),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `age`,`id`;",
+		},
+		{
+			name:    "group by invalid column",
+			builder: NewSelector[TestModel](db).GroupBy("Invalid", "Id"),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:     "offset",
+			builder:  NewSelector[TestModel](db).OrderBy(ASC("Age"), DESC("Id")).Offset(10),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC LIMIT -1 OFFSET ?;",
+			wantArgs: []interface{}{10},
+		},
+		{
+			name:     "limit",
+			builder:  NewSelector[TestModel](db).OrderBy(ASC("Age"), DESC("Id")).Offset(10).Limit(100),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC LIMIT ? OFFSET ?;",
+			wantArgs: []interface{}{100, 10},
+		},
+		{
+			name:     "where",
+			builder:  NewSelector[TestModel](db).Where(C("Id").EQ(10)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `id`=?;",
+			wantArgs: []interface{}{10},
+		},
+		{
+			name:    "no where",
+			builder: NewSelector[TestModel](db).Where(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;",
+		},
+		{
+			name:     "having",
+			builder:  NewSelector[TestModel](db).GroupBy("FirstName").Having(Avg("Age").EQ(18)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING AVG(`age`)=?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:    "no having",
+			builder: NewSelector[TestModel](db).GroupBy("FirstName").Having(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name`;",
+		},
+		{
+			name:     "alias in having",
+			builder:  NewSelector[TestModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("avg_age").LT(20)),
+			wantSql:  "SELECT `id`,`first_name`,AVG(`age`) AS `avg_age` FROM `test_model` GROUP BY `first_name` HAVING `avg_age`<?;",
+			wantArgs: []interface{}{20},
+		},
+		{
+			name:    "invalid alias in having",
+			builder: NewSelector[TestModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("Invalid").LT(20)),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name: "group by raw",
+			builder: NewSelector[TestModel](db).Select(Raw("SUBSTR(`first_name`,1,?)", 1), CountAll()).
+				Where(C("Age").GT(18)).GroupByExpr(Raw("SUBSTR(`first_name`,1,?)", 1)).Having(CountAll().GT(2)),
+			wantSql: "SELECT SUBSTR(`first_name`,1,?),COUNT(*) FROM `test_model` WHERE `age`>? " +
+				"GROUP BY SUBSTR(`first_name`,1,?) HAVING COUNT(*)>?;",
+			wantArgs: []interface{}{1, 18, 1, 2},
+		},
+		{
+			name:    "group by column and raw",
+			builder: NewSelector[TestModel](db).Select(C("Age")).GroupBy("Age").GroupByExpr(Raw("DATE(`last_name`)")),
+			wantSql: "SELECT `age` FROM `test_model` GROUP BY `age`,DATE(`last_name`);",
+		},
+		{
+			name:    "group by raw with invalid declared field",
+			builder: NewSelector[TestModel](db).GroupByExpr(Raw("DATE(`create_time`)").Using("CreateTime")),
+			wantErr: errs.NewInvalidFieldError("CreateTime"),
+		},
+		{
+			name: "raw having",
+			builder: NewSelector[TestModel](db).Select(C("FirstName"), CountAll()).GroupBy("FirstName").
+				Having(RawPredicate("COUNT(*) > ?", 5), Raw("MAX(`age`) - MIN(`age`) < ?", 10).AsPredicate()),
+			wantSql: "SELECT `first_name`,COUNT(*) FROM `test_model` GROUP BY `first_name` " +
+				"HAVING (COUNT(*) > ?) AND (MAX(`age`) - MIN(`age`) < ?);",
+			wantArgs: []interface{}{5, 10},
+		},
+		{
+			name: "raw having with aggregate",
+			builder: NewSelector[TestModel](db).GroupBy("FirstName").Where(C("Age").GT(18)).
+				Having(Avg("Age").LT(60)).AndHaving(RawPredicate("COUNT(DISTINCT `last_name`) >= ?", 2).Using("LastName")),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>? GROUP BY `first_name` " +
+				"HAVING (AVG(`age`)<?) AND (COUNT(DISTINCT `last_name`) >= ?);",
+			wantArgs: []interface{}{18, 60, 2},
+		},
+		{
+			name: "postgres raw having",
+			builder: NewSelector[TestModel](postgresDB()).GroupBy("FirstName").Where(C("Age").GT(18)).
+				Having(RawPredicate("COUNT(*) > ?", 5)),
+			wantSql:  `SELECT "id","first_name","age","last_name" FROM "test_model" WHERE "age">$1 GROUP BY "first_name" HAVING COUNT(*) > $2;`,
+			wantArgs: []interface{}{18, 5},
+		},
+		{
+			name: "and having",
+			builder: NewSelector[TestModel](db).GroupBy("FirstName").Having(Avg("Age").GT(18)).
+				AndHaving(Max("Age").LT(60)).AndHaving(Count("Id").GT(1)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING ((AVG(`age`)>?) AND (MAX(`age`)<?)) AND (COUNT(`id`)>?);",
+			wantArgs: []interface{}{18, 60, 1},
+		},
+		{
+			name:     "and having without having",
+			builder:  NewSelector[TestModel](db).GroupBy("FirstName").AndHaving(Avg("Age").GT(18)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING AVG(`age`)>?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "or having",
+			builder: NewSelector[TestModel](db).GroupBy("FirstName").Having(Avg("Age").GT(18), Max("Age").LT(60)).
+				OrHaving(Count("Id").GT(10)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING ((AVG(`age`)>?) AND (MAX(`age`)<?)) OR (COUNT(`id`)>?);",
+			wantArgs: []interface{}{18, 60, 10},
+		},
+		{
+			name: "or having then and having",
+			builder: NewSelector[TestModel](db).GroupBy("FirstName").Having(Avg("Age").GT(18)).
+				OrHaving(Count("Id").GT(10)).AndHaving(Max("Age").LT(60)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING ((AVG(`age`)>?) OR (COUNT(`id`)>?)) AND (MAX(`age`)<?);",
+			wantArgs: []interface{}{18, 10, 60},
+		},
+		{
+			name:     "in",
+			builder:  NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In(1, 2, 3)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `id` IN (?,?,?);",
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name:     "not in",
+			builder:  NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").NotIn(1, 2, 3)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `id` NOT IN (?,?,?);",
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			// 传入的参数为切片
+			name:     "slice in",
+			builder:  NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In([]int{1, 2, 3})),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `id` IN (?);",
+			wantArgs: []interface{}{[]int{1, 2, 3}},
+		},
+		{
+			// in 后面没有值
+			name:    "no in",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In()),
+			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+		},
+		{
+			// Notin 后面没有值
+			name:    "no in",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").NotIn()),
+			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+		},
+		{
+			name:    "in empty slice",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In([]any{}...)),
+			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+		},
+		{
+			name:    "NOT In empty slice",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").NotIn([]any{}...)),
+			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+		},
+		// 模糊查询
+		{
+			name:    "NOT In empty slice",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").NotIn([]any{}...)),
+			wantSql: "SELECT `id` FROM `test_model` WHERE FALSE;",
+		},
+		{
+			name:     "where not like %",
+			builder:  NewSelector[TestModel](db).Where(C("FirstName").NotLike("%ming")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` NOT LIKE ?;",
+			wantArgs: []interface{}{"%ming"},
+		},
+		{
+			name:     "where like %",
+			builder:  NewSelector[TestModel](db).Where(C("FirstName").Like("zhang%")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` LIKE ?;",
+			wantArgs: []interface{}{"zhang%"},
+		},
+		{
+			name:     "where not like _",
+			builder:  NewSelector[TestModel](db).Where(C("FirstName").NotLike("_三_")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` NOT LIKE ?;",
+			wantArgs: []interface{}{"_三_"},
+		},
+		{
+			name:     "where like _",
+			builder:  NewSelector[TestModel](db).Where(C("FirstName").Like("_三_")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` LIKE ?;",
+			wantArgs: []interface{}{"_三_"},
+		},
+		{
+			name:     "where not like []",
+			builder:  NewSelector[TestModel](db).Where(C("FirstName").NotLike("老[1-9]")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` NOT LIKE ?;",
+			wantArgs: []interface{}{"老[1-9]"},
+		},
+		{
+			name:     "where like []",
+			builder:  NewSelector[TestModel](db).Where(C("FirstName").Like("老[1-9]")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` LIKE ?;",
+			wantArgs: []interface{}{"老[1-9]"},
+		},
+		{
+			name:     "where not like [^ ]",
+			builder:  NewSelector[TestModel](db).Where(C("FirstName").NotLike("老[^1-4]")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` NOT LIKE ?;",
+			wantArgs: []interface{}{"老[^1-4]"},
+		},
+		{
+			name:     "where like [^ ]",
+			builder:  NewSelector[TestModel](db).Where(C("FirstName").Like("老[^1-4]")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `first_name` LIKE ?;",
+			wantArgs: []interface{}{"老[^1-4]"},
+		},
+
+		{
+			name:     "where not like int",
+			builder:  NewSelector[TestModel](db).Where(C("Age").NotLike(18)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age` NOT LIKE ?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:     "where like int",
+			builder:  NewSelector[TestModel](db).Where(C("Age").Like(22)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age` LIKE ?;",
+			wantArgs: []interface{}{22},
+		},
+		{
+			name:     "having like %",
+			builder:  NewSelector[TestModel](db).GroupBy("FirstName").Having(C("LastName").Like("%li")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING `last_name` LIKE ?;",
+			wantArgs: []interface{}{"%li"},
+		},
+		{
+			name:     "having no like %",
+			builder:  NewSelector[TestModel](db).GroupBy("FirstName").Having(C("LastName").NotLike("%yy%")),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` GROUP BY `first_name` HAVING `last_name` NOT LIKE ?;",
+			wantArgs: []interface{}{"%yy%"},
+		},
+		{
+			name:    "distinct single row",
+			builder: NewSelector[TestModel](db).Distinct().Select(C("FirstName")),
+			wantSql: "SELECT DISTINCT `first_name` FROM `test_model`;",
+		},
+		{
+			name:    "distinct order by selected column",
+			builder: NewSelector[TestModel](db).Distinct().Select(C("FirstName"), C("Age")).OrderBy(DESC("Age"), ASC("FirstName")),
+			wantSql: "SELECT DISTINCT `first_name`,`age` FROM `test_model` ORDER BY `age` DESC,`first_name` ASC;",
+		},
+		{
+			name:    "distinct order by all columns",
+			builder: NewSelector[TestModel](db).Distinct().OrderBy(ASC("Age")),
+			wantSql: "SELECT DISTINCT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC;",
+		},
+		{
+			name:    "distinct order by unselected column",
+			builder: NewSelector[TestModel](db).Distinct().Select(C("FirstName")).OrderBy(ASC("Age")),
+			wantErr: errs.NewDistinctSelectOrderByError("Age"),
+		},
+		{
+			name:    "count distinct",
+			builder: NewSelector[TestModel](db).Select(CountDistinct("FirstName")),
+			wantSql: "SELECT COUNT(DISTINCT `first_name`) FROM `test_model`;",
+		},
+		{
+			name:     "having count distinct",
+			builder:  NewSelector[TestModel](db).Select(C("FirstName")).GroupBy("FirstName").Having(CountDistinct("FirstName").EQ("jack")),
+			wantSql:  "SELECT `first_name` FROM `test_model` GROUP BY `first_name` HAVING COUNT(DISTINCT `first_name`)=?;",
+			wantArgs: []interface{}{"jack"},
+		},
+		{
+			name: "count distinct alias in having",
+			builder: NewSelector[TestModel](db).Select(C("FirstName"), CountDistinct("LastName").As("n")).
+				GroupBy("FirstName").Having(C("n").GT(1)),
+			wantSql:  "SELECT `first_name`,COUNT(DISTINCT `last_name`) AS `n` FROM `test_model` GROUP BY `first_name` HAVING `n`>?;",
+			wantArgs: []interface{}{1},
+		},
+		// 子查詢
+		{
+			name: "from",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel2](db).AsSubquery("sub")
+				return NewSelector[TestModel](db).From(sub)
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub`;",
+		},
+		{
+			name: "in",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(Columns("Id")).Where(C("Id").In(sub))
+			}(),
+			wantSql: "SELECT `id` FROM `test_model` WHERE `id` IN (SELECT `user_id` FROM `test_model2`);",
+		},
+		{
+			name: "all",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Where(C("Id").GT(All(sub)))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `id`>ALL (SELECT `user_id` FROM `test_model2`);",
+		},
+		{
+			name: "some and any",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Where(C("Id").GT(Some(sub)), C("Id").LT(Any(sub)))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE (`id`>SOME (SELECT `user_id` FROM `test_model2`)) AND (`id`<ANY (SELECT `user_id` FROM `test_model2`));",
+		},
+		{
+			name: "exist",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Where(Exist(sub))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE EXISTS (SELECT `user_id` FROM `test_model2`);",
+		},
+		{
+			name: "aggregate",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(Max("Id")).Where(Exist(sub))
+			}(),
+			wantSql: "SELECT MAX(`id`) FROM `test_model` WHERE EXISTS (SELECT `user_id` FROM `test_model2`);",
+		},
+		{
+			name: "invalid column",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(Max("invalid")).Where(Exist(sub))
+			}(),
+			wantErr: errs.NewInvalidFieldError("invalid"),
+		},
+		{
+			name: "not exist",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Where(Not(Exist(sub)))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE NOT (EXISTS (SELECT `user_id` FROM `test_model2`));",
+		},
+		// 關聯子查詢引用外層表的別名
+		{
+			name: "correlated exist with aliased outer table",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).
+					Where(C("UserId").EQ(t1.C("Id")), C("Phone").GT(100)).AsSubquery("sub")
+				return NewSelector[TestModel](db).From(t1).Where(Exist(sub), t1.C("Age").GT(18))
+			}(),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` AS `t1` WHERE (EXISTS (SELECT `user_id` FROM `test_model2` WHERE (`user_id`=`t1`.`id`) AND (`phone`>?))) AND (`t1`.`age`>?);",
+			wantArgs: []interface{}{100, 18},
+		},
+		{
+			name: "correlated exist with both tables aliased",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				// FirstName 不是 TestModel2 的字段，它應該按照外層表 t1 的元數據校驗
+				sub := NewSelector[TestModel2](db).From(t2).Select(C("UserId")).
+					Where(t2.C("UserId").EQ(t1.C("Id")).And(t1.C("FirstName").NEQ("Tom"))).AsSubquery("sub")
+				return NewSelector[TestModel](db).From(t1).Where(Not(Exist(sub)))
+			}(),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` AS `t1` WHERE NOT (EXISTS (SELECT `user_id` FROM `test_model2` AS `t2` WHERE (`t2`.`user_id`=`t1`.`id`) AND (`t1`.`first_name`!=?)));",
+			wantArgs: []interface{}{"Tom"},
+		},
+		{
+			name: "correlated exist with invalid outer field",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).
+					Where(C("UserId").EQ(t1.C("Phone"))).AsSubquery("sub")
+				return NewSelector[TestModel](db).From(t1).Where(Exist(sub))
+			}(),
+			wantErr: errs.NewInvalidFieldError("Phone"),
+		},
+		// join 查詢
+		{
+			name: "join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{})
+				return NewSelector[TestModel](db).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId"))))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM (`test_model` AS `t1` JOIN `test_model2` ON `t1`.`id`=`user_id`);",
+		},
+		{
+			name: "multiple join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				t3 := TableOf(&TestModel3{}).As("t3")
+				return NewSelector[TestModel](db).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId"))).Join(t3).On(t2.C("UserId").EQ(t3.C("Id"))))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM ((`test_model` AS `t1` JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`) JOIN `test_model3` AS `t3` ON `t2`.`user_id`=`t3`.`id`);",
+		},
+		{
+			name: "left multiple join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				t3 := TableOf(&TestModel3{}).As("t3")
+				return NewSelector[TestModel](db).
+					From(t1.LeftJoin(t2).On(t1.C("Id").EQ(t2.C("UserId"))).LeftJoin(t3).On(t2.C("UserId").EQ(t3.C("Id"))))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM ((`test_model` AS `t1` LEFT JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`) LEFT JOIN `test_model3` AS `t3` ON `t2`.`user_id`=`t3`.`id`);",
+		},
+		{
+			name: "right multiple join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				t3 := TableOf(&TestModel3{}).As("t3")
+				return NewSelector[TestModel](db).
+					From(t1.RightJoin(t2).On(t1.C("Id").EQ(t2.C("UserId"))).RightJoin(t3).On(t2.C("UserId").EQ(t3.C("Id"))))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM ((`test_model` AS `t1` RIGHT JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`) RIGHT JOIN `test_model3` AS `t3` ON `t2`.`user_id`=`t3`.`id`);",
+		},
+		{
+			name: "join & using",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{})
+				return NewSelector[TestModel](db).
+					From(t1.Join(t2).Using("FirstName", "LastName"))
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM (`test_model` AS `t1` JOIN `test_model2` USING (`first_name`,`last_name`));",
+		},
+		{
+			name: "cross join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				return NewSelector[TestModel](db).Select(t1.C("Id"), t2.C("Phone")).
+					From(t1.CrossJoin(t2)).Where(t1.C("Age").GT(18))
+			}(),
+			wantSql:  "SELECT `t1`.`id`,`t2`.`phone` FROM (`test_model` AS `t1` CROSS JOIN `test_model2` AS `t2`) WHERE `t1`.`age`>?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "multiple cross join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				t3 := TableOf(&TestModel3{}).As("t3")
+				return NewSelector[TestModel](db).Select(t1.C("Id")).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId"))).CrossJoin(t3))
+			}(),
+			wantSql: "SELECT `t1`.`id` FROM ((`test_model` AS `t1` JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`) CROSS JOIN `test_model3` AS `t3`);",
+		},
+		{
+			name: "cross join subquery",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{})
+				sub := NewSelector[TestModel2](db).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(sub.C("UserId")).From(sub.CrossJoin(t1))
+			}(),
+			wantSql: "SELECT `sub`.`user_id` FROM ((SELECT `user_id`,`phone` FROM `test_model2`) AS `sub` CROSS JOIN `test_model`);",
+		},
+		{
+			name: "order by and group by qualified columns",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				return NewSelector[TestModel](db).Select(t1.C("Id"), Count("Age")).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId")))).
+					GroupByExpr(t1.C("Id")).OrderBy(DESCColumn(t1.C("Id")), ASCColumn(t2.C("UserId")))
+			}(),
+			wantSql: "SELECT `t1`.`id`,COUNT(`age`) FROM (`test_model` AS `t1` JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`) " +
+				"GROUP BY `t1`.`id` ORDER BY `t1`.`id` DESC,`t2`.`user_id` ASC;",
+		},
+		{
+			name: "order by qualified invalid column",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				return NewSelector[TestModel](db).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId")))).
+					OrderBy(ASCColumn(t2.C("Age")))
+			}(),
+			wantErr: errs.NewInvalidFieldError("Age"),
+		},
+		{
+			name: "cross join with on",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				j := t1.CrossJoin(t2)
+				j.on = []Predicate{t1.C("Id").EQ(t2.C("UserId"))}
+				return NewSelector[TestModel](db).From(j)
+			}(),
+			wantErr: errs.ErrCrossJoinWithCondition,
+		},
+		{
+			name: "join with on and using",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				j := t1.Join(t2).Using("FirstName")
+				j.on = []Predicate{t1.C("Id").EQ(t2.C("UserId"))}
+				return NewSelector[TestModel](db).From(j)
+			}(),
+			wantErr: errs.ErrJoinWithOnAndUsing,
+		},
+		{
+			name: "full join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				return NewSelector[TestModel](db).From(t1.FullJoin(t2).On(t1.C("Id").EQ(t2.C("UserId"))))
+			}(),
+			wantErr: errs.NewUnsupportedJoinError("FULL OUTER JOIN", "SQLite"),
+		},
+		// Join 與 Subquery 一起使用測試
+		{
+			name: "join & subquery",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{})
+				sub := NewSelector[TestModel2](db).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(sub.C("UserId")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
+			}(),
+			wantSql: "SELECT `sub`.`user_id` FROM (`test_model` JOIN (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub` ON `id`=`sub`.`user_id`);",
+		},
+		{
+			name: "left join & subquery",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{})
+				sub := NewSelector[TestModel2](db).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(sub.C("UserId")).From(t1.LeftJoin(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
+			}(),
+			wantSql: "SELECT `sub`.`user_id` FROM (`test_model` LEFT JOIN (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub` ON `id`=`sub`.`user_id`);",
+		},
+		{
+			name: "right join & subquery",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{})
+				sub := NewSelector[TestModel2](db).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(sub.C("UserId")).From(t1.RightJoin(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
+			}(),
+			wantSql: "SELECT `sub`.`user_id` FROM (`test_model` RIGHT JOIN (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub` ON `id`=`sub`.`user_id`);",
+		},
+		{
+			name: "right join & subquery & using",
+			builder: func() QueryBuilder {
+				sub1 := NewSelector[TestModel2](db).AsSubquery("sub1")
+				sub2 := NewSelector[TestModel2](db).AsSubquery("sub2")
+				return NewSelector[TestModel](db).Select(sub1.C("UserId")).From(sub1.RightJoin(sub2).Using("Id")).Where()
+			}(),
+			wantSql: "SELECT `sub1`.`user_id` FROM ((SELECT `user_id`,`phone` FROM `test_model2`) AS `sub1` RIGHT JOIN (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub2` USING (`id`));",
+		},
+		{
+			name: "join & subquery & using",
+			builder: func() QueryBuilder {
+				sub1 := NewSelector[TestModel2](db).AsSubquery("sub1")
+				sub2 := NewSelector[TestModel2](db).From(sub1).AsSubquery("sub2")
+				t1 := TableOf(&TestModel{}).As("o1")
+				return NewSelector[TestModel](db).From(sub2.Join(t1).Using("Id")).Where()
+			}(),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM ((SELECT `user_id`,`phone` FROM (SELECT `user_id`,`phone` FROM `test_model2`) AS `sub1`) AS `sub2` JOIN `test_model` AS `o1` USING (`id`));",
+		},
+		{
+			name: "invalid field",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{})
+				sub := NewSelector[TestModel2](db).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(sub.C("Invalid")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
+			}(),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name: "invalid field in predicates",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{})
+				sub := NewSelector[TestModel2](db).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(sub.C("ItemId")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("Invalid")))).Where()
+			}(),
+			wantErr: errs.NewInvalidFieldError("ItemId"),
+		},
+		{
+			name: "invalid field in predicates with columns",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{})
+				sub := NewSelector[TestModel2](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(sub.C("ItemId")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
+			}(),
+			wantErr: errs.NewInvalidFieldError("ItemId"),
+		},
+		{
+			name: "invalid field in aggregate function",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{})
+				sub := NewSelector[TestModel2](db).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(Max("Invalid")).From(t1.Join(sub).On(t1.C("Id").EQ(sub.C("UserId")))).Where()
+			}(),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelectableCombination(t *testing.T) {
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name:    "simple",
+			builder: NewSelector[TestCombinedModel](db),
+			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model`;",
+		},
+		{
+			name:    "columns",
+			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id", "FirstName", "CreateTime")),
+			wantSql: "SELECT `id`,`first_name`,`create_time` FROM `test_combined_model`;",
+		},
+		{
+			name:    "alias",
+			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id"), C("CreateTime").As("creation")),
+			wantSql: "SELECT `id`,`create_time` AS `creation` FROM `test_combined_model`;",
+		},
+		{
+			name:    "aggregate",
+			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id"), Max("CreateTime").As("max_time")),
+			wantSql: "SELECT `id`,MAX(`create_time`) AS `max_time` FROM `test_combined_model`;",
+		},
+		{
+			name:    "raw",
+			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id"), Raw("AVG(DISTINCT `create_time`)")),
+			wantSql: "SELECT `id`,AVG(DISTINCT `create_time`) FROM `test_combined_model`;",
+		},
+		{
+			name:    "invalid columns",
+			builder: NewSelector[TestCombinedModel](db).Select(Columns("Invalid"), Raw("AVG(DISTINCT `age`)")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "order by",
+			builder: NewSelector[TestCombinedModel](db).OrderBy(ASC("Age"), DESC("CreateTime")),
+			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC;",
+		},
+		{
+			name:    "order by invalid column",
+			builder: NewSelector[TestCombinedModel](db).OrderBy(ASC("Invalid"), DESC("Id")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "group by",
+			builder: NewSelector[TestCombinedModel](db).GroupBy("CreateTime", "Id"),
+			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` GROUP BY `create_time`,`id`;",
+		},
+		{
+			name:    "group by invalid column",
+			builder: NewSelector[TestCombinedModel](db).GroupBy("Invalid", "Id"),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:     "offset",
+			builder:  NewSelector[TestCombinedModel](db).OrderBy(ASC("Age"), DESC("CreateTime")).Offset(10),
+			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC LIMIT -1 OFFSET ?;",
+			wantArgs: []interface{}{10},
+		},
+		{
+			name:     "limit",
+			builder:  NewSelector[TestCombinedModel](db).OrderBy(ASC("Age"), DESC("CreateTime")).Offset(10).Limit(100),
+			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC LIMIT ? OFFSET ?;",
+			wantArgs: []interface{}{100, 10},
+		},
+		{
+			name:     "where",
+			builder:  NewSelector[TestCombinedModel](db).Where(C("Id").EQ(10).And(C("CreateTime").EQ(10))),
+			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` WHERE (`id`=?) AND (`create_time`=?);",
+			wantArgs: []interface{}{10, 10},
+		},
+		{
+			name:    "no where",
+			builder: NewSelector[TestCombinedModel](db).Where(),
+			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model`;",
+		},
+		{
+			name:     "having",
+			builder:  NewSelector[TestCombinedModel](db).GroupBy("FirstName").Having(Max("CreateTime").EQ(18)),
+			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` GROUP BY `first_name` HAVING MAX(`create_time`)=?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:    "no having",
+			builder: NewSelector[TestCombinedModel](db).GroupBy("CreateTime").Having(),
+			wantSql: "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` GROUP BY `create_time`;",
+		},
+		{
+			name:     "alias in having",
+			builder:  NewSelector[TestCombinedModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("CreateTime").As("create")).GroupBy("FirstName").Having(C("create").LT(20)),
+			wantSql:  "SELECT `id`,`first_name`,AVG(`create_time`) AS `create` FROM `test_combined_model` GROUP BY `first_name` HAVING `create`<?;",
+			wantArgs: []interface{}{20},
+		},
+		{
+			name:    "invalid alias in having",
+			builder: NewSelector[TestCombinedModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("Invalid").LT(20)),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+type BaseEntity struct {
+	CreateTime uint64
+	UpdateTime uint64
+}
+
+type TestCombinedModel struct {
+	BaseEntity
+	Id        int64 `eorm:"auto_increment,primary_key"`
+	FirstName string
+	Age       int8
+	LastName  *string
+}
+
+func ExampleSelector_OrderBy() {
+	db := memoryDB()
+	query, _ := NewSelector[TestModel](db).OrderBy(ASC("Age")).Build()
+	fmt.Printf("case1\n%s", query.string())
 	query, _ = NewSelector[TestModel](db).OrderBy(ASC("Age", "Id")).Build()
 	fmt.Printf("case2\n%s", query.string())
 	query, _ = NewSelector[TestModel](db).OrderBy(ASC("Age"), ASC("Id")).Build()
@@ -1368,82 +3094,1298 @@ func ExampleSelector_OrderBy() {
 	// Args: []interface {}(nil)
 }
 
-func ExampleSelector_Having() {
-	db := memoryDB()
-	query, _ := NewSelector[TestModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("avg_age").LT(20)).Build()
-	fmt.Printf("case1\n%s", query.string())
-	query, err := NewSelector[TestModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("Invalid").LT(20)).Build()
-	fmt.Printf("case2\n%s", err)
-	// Output:
-	// case1
-	// SQL: SELECT `id`,`first_name`,AVG(`age`) AS `avg_age` FROM `test_model` GROUP BY `first_name` HAVING `avg_age`<?;
-	// Args: []interface {}{20}
-	// case2
-	// eorm: 未知字段 Invalid
+func ExampleSelector_Having() {
+	db := memoryDB()
+	query, _ := NewSelector[TestModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("avg_age").LT(20)).Build()
+	fmt.Printf("case1\n%s", query.string())
+	query, err := NewSelector[TestModel](db).Select(Columns("Id"), Columns("FirstName"), Avg("Age").As("avg_age")).GroupBy("FirstName").Having(C("Invalid").LT(20)).Build()
+	fmt.Printf("case2\n%s", err)
+	// Output:
+	// case1
+	// SQL: SELECT `id`,`first_name`,AVG(`age`) AS `avg_age` FROM `test_model` GROUP BY `first_name` HAVING `avg_age`<?;
+	// Args: []interface {}{20}
+	// case2
+	// eorm: 未知字段 Invalid
+}
+
+func ExampleSelector_Select() {
+	db := memoryDB()
+	tm := TableOf(&TestModel{})
+	cases := []*Selector[TestModel]{
+		// case0: all columns are included
+		NewSelector[TestModel](db).From(tm),
+		// case1: only query specific columns
+		NewSelector[TestModel](db).Select(Columns("Id", "Age")).From(tm),
+		// case2: using alias
+		NewSelector[TestModel](db).Select(C("Id").As("my_id")).From(tm),
+		// case3: using aggregation function and alias
+		NewSelector[TestModel](db).Select(Avg("Age").As("avg_age")).From(tm),
+		// case4: using raw expression
+		NewSelector[TestModel](db).Select(Raw("COUNT(DISTINCT `age`) AS `age_cnt`")).From(tm),
+	}
+
+	for index, tc := range cases {
+		query, _ := tc.Build()
+		fmt.Printf("case%d:\n%s", index, query.string())
+	}
+	// Output:
+	// case0:
+	// SQL: SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;
+	// Args: []interface {}(nil)
+	// case1:
+	// SQL: SELECT `id`,`age` FROM `test_model`;
+	// Args: []interface {}(nil)
+	// case2:
+	// SQL: SELECT `id` AS `my_id` FROM `test_model`;
+	// Args: []interface {}(nil)
+	// case3:
+	// SQL: SELECT AVG(`age`) AS `avg_age` FROM `test_model`;
+	// Args: []interface {}(nil)
+	// case4:
+	// SQL: SELECT COUNT(DISTINCT `age`) AS `age_cnt` FROM `test_model`;
+	// Args: []interface {}(nil)
+}
+
+func ExampleSelector_Distinct() {
+	db := memoryDB()
+	tm := TableOf(&TestModel{})
+	cases := []*Selector[TestModel]{
+		// case0: disinct column
+		NewSelector[TestModel](db).From(tm).Distinct().Select(C("FirstName")),
+		// case1: aggregation function using distinct
+		NewSelector[TestModel](db).From(tm).Select(CountDistinct("FirstName")),
+		// case2: having using distinct
+		NewSelector[TestModel](db).From(tm).Select(C("FirstName")).GroupBy("FirstName").Having(CountDistinct("FirstName").EQ("jack")),
+	}
+
+	for index, tc := range cases {
+		query, _ := tc.Build()
+		fmt.Printf("case%d:\n%s", index, query.string())
+	}
+	// Output:
+	// case0:
+	// SQL: SELECT DISTINCT `first_name` FROM `test_model`;
+	// Args: []interface {}(nil)
+	// case1:
+	// SQL: SELECT COUNT(DISTINCT `first_name`) FROM `test_model`;
+	// Args: []interface {}(nil)
+	// case2:
+	// SQL: SELECT `first_name` FROM `test_model` GROUP BY `first_name` HAVING COUNT(DISTINCT `first_name`)=?;
+	// Args: []interface {}{"jack"}
+}
+
+func TestSelector_DefaultSelectLimit(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB, DBWithDefaultSelectLimit(100))
+	require.NoError(t, err)
+	noDefault, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		s        *Selector[TestModel]
+		mockSql  string
+		mockArgs []driver.Value
+	}{
+		{
+			name:     "default limit",
+			s:        NewSelector[TestModel](db),
+			mockSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` LIMIT ?;",
+			mockArgs: []driver.Value{100},
+		},
+		{
+			name:     "explicit limit",
+			s:        NewSelector[TestModel](db).Limit(5),
+			mockSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` LIMIT ?;",
+			mockArgs: []driver.Value{5},
+		},
+		{
+			name:    "no limit",
+			s:       NewSelector[TestModel](db).NoLimit(),
+			mockSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;",
+		},
+		{
+			name:    "without default limit",
+			s:       NewSelector[TestModel](noDefault),
+			mockSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+			eq := mock.ExpectQuery(tc.mockSql)
+			if len(tc.mockArgs) > 0 {
+				eq = eq.WithArgs(tc.mockArgs...)
+			}
+			eq.WillReturnRows(rows)
+			res, err := tc.s.GetMulti(context.Background())
+			require.NoError(t, err)
+			assert.Len(t, res, 1)
+		})
+	}
+
+	// 默认的 LIMIT 不会被写回到 Selector 上，之后仍然可以使用 NoLimit
+	s := NewSelector[TestModel](db)
+	mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` LIMIT ?;").
+		WithArgs(100).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	_, err = s.GetMulti(context.Background())
+	require.NoError(t, err)
+	query, err := s.NoLimit().Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;", query.SQL)
+	assert.Empty(t, query.Args)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_JoinSoftDelete(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+	type User struct {
+		Id        int64
+		Name      string
+		DeletedAt *time.Time `eorm:"soft_delete"`
+	}
+	type Order struct {
+		Id        int64
+		UserId    int64
+		DeletedAt *time.Time `eorm:"soft_delete"`
+	}
+	u := TableOf(&User{}).As("u")
+	o := TableOf(&Order{}).As("o")
+	testCases := []CommonTestCase{
+		{
+			name: "join",
+			builder: NewSelector[User](db).Select(u.C("Name"), o.C("Id")).
+				From(u.Join(o).On(u.C("Id").EQ(o.C("UserId")))).Where(u.C("Id").GT(10)),
+			wantSql: "SELECT `u`.`name`,`o`.`id` FROM (`user` AS `u` JOIN `order` AS `o` ON (`u`.`id`=`o`.`user_id`) AND (`o`.`deleted_at` IS NULL)) " +
+				"WHERE (`u`.`id`>?) AND (`u`.`deleted_at` IS NULL);",
+			wantArgs: []interface{}{10},
+		},
+		{
+			name: "left join without alias",
+			builder: NewSelector[User](db).Select(C("Name")).
+				From(TableOf(&User{}).LeftJoin(TableOf(&Order{})).On(C("Id").EQ(TableOf(&Order{}).C("UserId")))),
+			wantSql: "SELECT `name` FROM (`user` LEFT JOIN `order` ON (`id`=`user_id`) AND (`order`.`deleted_at` IS NULL)) " +
+				"WHERE `user`.`deleted_at` IS NULL;",
+		},
+		{
+			name: "using",
+			builder: NewSelector[User](db).Select(u.C("Name")).
+				From(u.Join(o).Using("Id")),
+			wantSql: "SELECT `u`.`name` FROM (`user` AS `u` JOIN `order` AS `o` USING (`id`)) " +
+				"WHERE (`u`.`deleted_at` IS NULL) AND (`o`.`deleted_at` IS NULL);",
+		},
+		{
+			name: "join table without soft delete",
+			builder: NewSelector[User](db).Select(u.C("Name")).
+				From(u.Join(TableOf(&TestModel{}).As("t")).On(u.C("Id").EQ(TableOf(&TestModel{}).As("t").C("Id")))),
+			wantSql: "SELECT `u`.`name` FROM (`user` AS `u` JOIN `test_model` AS `t` ON `u`.`id`=`t`.`id`) " +
+				"WHERE `u`.`deleted_at` IS NULL;",
+		},
+		{
+			name: "with deleted",
+			builder: NewSelector[User](db).Select(u.C("Name")).
+				From(u.Join(o).On(u.C("Id").EQ(o.C("UserId")))).WithDeleted(),
+			wantSql: "SELECT `u`.`name` FROM (`user` AS `u` JOIN `order` AS `o` ON `u`.`id`=`o`.`user_id`);",
+		},
+		{
+			name: "postgres",
+			builder: NewSelector[User](postgresDB()).Select(u.C("Name")).
+				From(u.Join(o).On(u.C("Id").EQ(o.C("UserId")))),
+			wantSql: `SELECT "u"."name" FROM ("user" AS "u" JOIN "order" AS "o" ON ("u"."id"="o"."user_id") AND ("o"."deleted_at" IS NULL)) ` +
+				`WHERE "u"."deleted_at" IS NULL;`,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_GeneratedColumn(t *testing.T) {
+	type Product struct {
+		Id       int64
+		Price    int64
+		Quantity int64
+		Total    int64 `eorm:"generated"`
+	}
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `id`,`price`,`quantity`,`total` FROM `product` WHERE `id`=? LIMIT ?;").WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price", "quantity", "total"}).AddRow(1, 10, 3, 30))
+	res, err := NewSelector[Product](db).Where(C("Id").EQ(1)).Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &Product{Id: 1, Price: 10, Quantity: 3, Total: 30}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_SoftDelete(t *testing.T) {
+	type User struct {
+		Id        int64
+		Name      string
+		DeletedAt *time.Time `eorm:"soft_delete"`
+	}
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name:    "no where",
+			builder: NewSelector[User](pg),
+			wantSql: `SELECT "id","name","deleted_at" FROM "user" WHERE "deleted_at" IS NULL;`,
+		},
+		{
+			name:     "where",
+			builder:  NewSelector[User](pg).Select(C("Name")).Where(C("Id").EQ(1)),
+			wantSql:  `SELECT "name" FROM "user" WHERE ("id"=$1) AND ("deleted_at" IS NULL);`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "alias",
+			builder: NewSelector[User](pg).Select(C("Name")).From(TableOf(&User{}).As("u")),
+			wantSql: `SELECT "name" FROM "user" AS "u" WHERE "u"."deleted_at" IS NULL;`,
+		},
+		{
+			name:     "unscoped",
+			builder:  NewSelector[User](pg).Select(C("Name")).Where(C("Id").EQ(1)).Unscoped(),
+			wantSql:  `SELECT "name" FROM "user" WHERE "id"=$1;`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "without soft delete column",
+			builder: NewSelector[TestModel](pg).Select(C("Id")),
+			wantSql: `SELECT "id" FROM "test_model";`,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_MaxJoinDepth(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB, DBWithMaxJoinDepth(3))
+	require.NoError(t, err)
+	tables := make([]Table, 6)
+	for i := range tables {
+		tables[i] = TableOf(&TestModel{}).As(fmt.Sprintf("t%d", i))
+	}
+	// join 按照 t0 JOIN t1 JOIN ... 的方式连接 n 层
+	join := func(n int) TableReference {
+		var res TableReference = tables[0]
+		for i := 1; i <= n; i++ {
+			on := tables[i-1].C("Id").EQ(tables[i].C("Id"))
+			switch l := res.(type) {
+			case Table:
+				res = l.Join(tables[i]).On(on)
+			case Join:
+				res = l.Join(tables[i]).On(on)
+			}
+		}
+		return res
+	}
+	testCases := []CommonTestCase{
+		{
+			name:    "5 deep",
+			builder: NewSelector[TestModel](db).Select(tables[0].C("Id")).From(join(5)),
+			wantErr: errs.NewJoinTooDeepError(5, 3),
+		},
+		{
+			name:    "nested right",
+			builder: NewSelector[TestModel](db).Select(tables[0].C("Id")).From(tables[5].Join(join(3)).Using("Id")),
+			wantErr: errs.NewJoinTooDeepError(4, 3),
+		},
+		{
+			name:    "3 deep",
+			builder: NewSelector[TestModel](db).Select(tables[0].C("Id")).From(join(3)),
+			wantSql: "SELECT `t0`.`id` FROM (((`test_model` AS `t0` JOIN `test_model` AS `t1` ON `t0`.`id`=`t1`.`id`) " +
+				"JOIN `test_model` AS `t2` ON `t1`.`id`=`t2`.`id`) JOIN `test_model` AS `t3` ON `t2`.`id`=`t3`.`id`);",
+		},
+		{
+			name:    "no limit",
+			builder: NewSelector[TestModel](memoryDB()).Select(tables[0].C("Id")).From(join(5)),
+			wantSql: "SELECT `t0`.`id` FROM (((((`test_model` AS `t0` JOIN `test_model` AS `t1` ON `t0`.`id`=`t1`.`id`) " +
+				"JOIN `test_model` AS `t2` ON `t1`.`id`=`t2`.`id`) JOIN `test_model` AS `t3` ON `t2`.`id`=`t3`.`id`) " +
+				"JOIN `test_model` AS `t4` ON `t3`.`id`=`t4`.`id`) JOIN `test_model` AS `t5` ON `t4`.`id`=`t5`.`id`);",
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+		})
+	}
+}
+
+func TestSelector_OffsetWithoutLimit(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name:     "mysql",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(C("Age").GT(18)).OrderBy(ASC("Id")).Offset(10),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `age`>? ORDER BY `id` ASC LIMIT 18446744073709551615 OFFSET ?;",
+			wantArgs: []interface{}{18, 10},
+		},
+		{
+			name:     "mysql with limit",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Offset(10).Limit(5),
+			wantSql:  "SELECT `id` FROM `test_model` LIMIT ? OFFSET ?;",
+			wantArgs: []interface{}{5, 10},
+		},
+		{
+			name:     "postgres",
+			builder:  NewSelector[TestModel](postgresDB()).Select(C("Id")).Where(C("Age").GT(18)).OrderBy(ASC("Id")).Offset(10),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "age">$1 ORDER BY "id" ASC OFFSET $2;`,
+			wantArgs: []interface{}{18, 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestSelector_GetMultiPositional(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		query     func(dst *[]TestModel) error
+		mockOrder func(mock sqlmock.Sqlmock)
+		wantErr   error
+		wantVal   []TestModel
+	}{
+		{
+			// 列名重复也不影响，只看位置
+			name: "positional",
+			query: func(dst *[]TestModel) error {
+				return NewSelector[TestModel](db).Where(C("Age").GT(18)).
+					GetMultiPositional(context.Background(), dst)
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>?;").
+					WithArgs(18).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age", "name"}).
+						AddRow(1, "Tom", 20, "Cat").AddRow(2, "Jerry", 19, nil))
+			},
+			wantVal: []TestModel{
+				{Id: 1, FirstName: "Tom", Age: 20, LastName: &sql.NullString{String: "Cat", Valid: true}},
+				{Id: 2, FirstName: "Jerry", Age: 19},
+			},
+		},
+		{
+			name: "column count mismatch",
+			query: func(dst *[]TestModel) error {
+				return NewSelector[TestModel](db).Select(C("Id"), C("FirstName")).
+					GetMultiPositional(context.Background(), dst)
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model`;").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name"}).AddRow(1, "Tom"))
+			},
+			wantErr: errs.NewColumnCountMismatchError(2, 4),
+		},
+		{
+			name: "no row",
+			query: func(dst *[]TestModel) error {
+				return NewSelector[TestModel](db).GetMultiPositional(context.Background(), dst)
+			},
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "age", "last_name"}))
+			},
+			wantVal: []TestModel{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockOrder(mock)
+			var res []TestModel
+			err := tc.query(&res)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantVal, res)
+		})
+	}
+
+	t.Run("not model type", func(t *testing.T) {
+		var res []int
+		err := NewSelector[int](db).Select(C("Age")).From(TableOf(&TestModel{})).
+			GetMultiPositional(context.Background(), &res)
+		assert.Equal(t, errs.NewUnsupportedTypeError(reflect.TypeOf(0)), err)
+	})
+}
+
+func TestSelector_ColumnTag(t *testing.T) {
+	type LegacyUser struct {
+		Id    int64  `eorm:"column=uid"`
+		Email string `eorm:"column=email_address"`
+	}
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name:    "all columns",
+			builder: NewSelector[LegacyUser](db),
+			wantSql: "SELECT `uid`,`email_address` FROM `legacy_user`;",
+		},
+		{
+			name: "predicate and order by",
+			builder: NewSelector[LegacyUser](db).Select(C("Email")).
+				Where(C("Email").EQ("a@b.com").And(C("Id").GT(1))).OrderBy(ASC("Id")),
+			wantSql:  "SELECT `email_address` FROM `legacy_user` WHERE (`email_address`=?) AND (`uid`>?) ORDER BY `uid` ASC;",
+			wantArgs: []interface{}{"a@b.com", 1},
+		},
+		{
+			name:     "postgres",
+			builder:  NewSelector[LegacyUser](postgresDB()).Where(C("Email").EQ("a@b.com")),
+			wantSql:  `SELECT "uid","email_address" FROM "legacy_user" WHERE "email_address"=$1;`,
+			wantArgs: []interface{}{"a@b.com"},
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+
+	t.Run("scan", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func() { _ = mockDB.Close() }()
+		mdb, err := openDB("mysql", mockDB)
+		require.NoError(t, err)
+		mock.ExpectQuery("SELECT .*").WillReturnRows(
+			sqlmock.NewRows([]string{"uid", "email_address"}).AddRow(1, "a@b.com"))
+		u, err := NewSelector[LegacyUser](mdb).Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &LegacyUser{Id: 1, Email: "a@b.com"}, u)
+	})
+}
+
+func TestSelector_IgnoreTag(t *testing.T) {
+	type Order struct {
+		Id       int64
+		Price    int64
+		Quantity int64
+		// Total 在 Go 里面计算，没有对应的列
+		Total int64 `eorm:"-"`
+	}
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name:    "all columns",
+			builder: NewSelector[Order](db),
+			wantSql: "SELECT `id`,`price`,`quantity` FROM `order`;",
+		},
+		{
+			name:    "select ignored field",
+			builder: NewSelector[Order](db).Select(C("Id"), C("Total")),
+			wantErr: errs.NewInvalidFieldError("Total"),
+		},
+		{
+			name:    "where ignored field",
+			builder: NewSelector[Order](db).Where(C("Total").GT(100)),
+			wantErr: errs.NewInvalidFieldError("Total"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestTable_IndexHint(t *testing.T) {
+	type TestModel2 struct {
+		UserId int64
+		Phone  int64
+	}
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name:     "use index",
+			builder:  NewSelector[TestModel](db).From(TableOf(&TestModel{}).UseIndex("idx_age")).Where(C("Age").GT(18)),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` USE INDEX (`idx_age`) WHERE `age`>?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:    "force index with alias",
+			builder: NewSelector[TestModel](db).From(TableOf(&TestModel{}).As("t1").ForceIndex("idx_age", "idx_name")),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` AS `t1` FORCE INDEX (`idx_age`,`idx_name`);",
+		},
+		{
+			name: "ignore index in join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1").IgnoreIndex("idx_age")
+				t2 := TableOf(&TestModel2{}).As("t2")
+				return NewSelector[TestModel](db).Select(t1.C("Id")).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UserId"))))
+			}(),
+			wantSql: "SELECT `t1`.`id` FROM (`test_model` AS `t1` IGNORE INDEX (`idx_age`) JOIN `test_model2` AS `t2` ON `t1`.`id`=`t2`.`user_id`);",
+		},
+		{
+			// 索引提示属于子查询里面的表
+			name: "derived table",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel](db).Select(C("Id"), C("Age")).
+					From(TableOf(&TestModel{}).UseIndex("idx_age")).Where(C("Age").GT(18)).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(sub.C("Id")).From(sub).Where(sub.C("Id").LT(100))
+			}(),
+			wantSql: "SELECT `sub`.`id` FROM (SELECT `id`,`age` FROM `test_model` USE INDEX (`idx_age`) WHERE `age`>?) AS `sub` " +
+				"WHERE `sub`.`id`<?;",
+			wantArgs: []interface{}{18, 100},
+		},
+		{
+			name:    "postgres",
+			builder: NewSelector[TestModel](postgresDB()).From(TableOf(&TestModel{}).UseIndex("idx_age")),
+			wantErr: errs.NewUnsupportedFeatureError("PostgreSQL", "USE INDEX"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestJSONTable(t *testing.T) {
+	type OrderItem struct {
+		Sku string
+		Qty int
+	}
+	type JSONOrder struct {
+		Id    int64
+		Items string
+	}
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB)
+	require.NoError(t, err)
+	o := TableOf(&JSONOrder{}).As("o")
+	items := JSONTable(o.C("Items"), "$[*]",
+		JSONCol("sku", "VARCHAR(32)", "$.sku"), JSONCol("qty", "INT", "$.qty")).As("it")
+	testCases := []CommonTestCase{
+		{
+			name: "cross join",
+			builder: NewSelector[OrderItem](db).Select(o.C("Id"), items.C("sku"), items.C("qty")).
+				From(o.CrossJoin(items)).Where(items.C("qty").GT(1)),
+			wantSql: "SELECT `o`.`id`,`it`.`sku`,`it`.`qty` FROM (`json_order` AS `o` CROSS JOIN " +
+				"JSON_TABLE(`o`.`items`,'$[*]' COLUMNS(`sku` VARCHAR(32) PATH '$.sku',`qty` INT PATH '$.qty')) AS `it`) " +
+				"WHERE `it`.`qty`>?;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "escape path",
+			builder: NewSelector[OrderItem](db).Select(items.C("sku")).
+				From(o.CrossJoin(JSONTable(o.C("Items"), "$.\"it's\"[*]", JSONCol("sku", "TEXT", "$.sku")).As("it"))),
+			wantSql: "SELECT `it`.`sku` FROM (`json_order` AS `o` CROSS JOIN " +
+				"JSON_TABLE(`o`.`items`,'$.\"it''s\"[*]' COLUMNS(`sku` TEXT PATH '$.sku')) AS `it`);",
+		},
+		{
+			name: "postgres",
+			builder: NewSelector[OrderItem](postgresDB()).Select(items.C("sku")).
+				From(o.CrossJoin(items)),
+			wantSql: `SELECT "it"."sku" FROM ("json_order" AS "o" CROSS JOIN ` +
+				`jsonb_to_recordset("o"."items") AS "it"("sku" VARCHAR(32),"qty" INT));`,
+		},
+		{
+			name: "postgres nested path",
+			builder: NewSelector[OrderItem](postgresDB()).
+				From(o.CrossJoin(JSONTable(o.C("Items"), "$.list[*]", JSONCol("sku", "TEXT", "$.sku")).As("it"))),
+			wantErr: errs.NewUnsupportedFeatureError("PostgreSQL", "JSONTable 路径 $.list[*]"),
+		},
+		{
+			name:    "without alias",
+			builder: NewSelector[OrderItem](db).From(o.CrossJoin(JSONTable(o.C("Items"), "$[*]"))),
+			wantErr: errs.ErrJSONTableWithoutAlias,
+		},
+		{
+			name:    "invalid column",
+			builder: NewSelector[OrderItem](db).Select(items.C("price")).From(o.CrossJoin(items)),
+			wantErr: errs.NewInvalidFieldError("price"),
+		},
+		{
+			name:    "sqlite",
+			builder: NewSelector[OrderItem](memoryDB()).From(o.CrossJoin(items)),
+			wantErr: errs.NewUnsupportedFeatureError("SQLite", "JSONTable"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestDBWithTimeLocation(t *testing.T) {
+	type Event struct {
+		Id    int64
+		Ctime time.Time
+		Utime *time.Time
+	}
+	loc := time.FixedZone("UTC+8", 8*3600)
+	utc := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := openDB("mysql", mockDB, DBWithTimeLocation(loc))
+	require.NoError(t, err)
+
+	t.Run("get", func(t *testing.T) {
+		mock.ExpectQuery("SELECT .*").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "ctime", "utime"}).AddRow(1, utc, utc))
+		e, err := NewSelector[Event](db).Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, loc, e.Ctime.Location())
+		assert.Equal(t, loc, e.Utime.Location())
+		assert.True(t, utc.Equal(e.Ctime))
+		assert.Equal(t, 11, e.Ctime.Hour())
+	})
+
+	t.Run("get multi", func(t *testing.T) {
+		mock.ExpectQuery("SELECT .*").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "ctime", "utime"}).AddRow(1, utc, nil).AddRow(2, utc, utc))
+		es, err := NewSelector[Event](db).GetMulti(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, len(es))
+		assert.Equal(t, loc, es[0].Ctime.Location())
+		assert.Nil(t, es[0].Utime)
+		assert.Equal(t, loc, es[1].Ctime.Location())
+		assert.Equal(t, loc, es[1].Utime.Location())
+	})
+
+	t.Run("base type", func(t *testing.T) {
+		mock.ExpectQuery("SELECT .*").WillReturnRows(
+			sqlmock.NewRows([]string{"ctime"}).AddRow(utc))
+		ctime, err := NewSelector[time.Time](db).Select(C("Ctime")).From(TableOf(&Event{})).Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, loc, ctime.Location())
+		assert.Equal(t, utc.In(loc), *ctime)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		mdb, err := openDB("mysql", mockDB)
+		require.NoError(t, err)
+		mock.ExpectQuery("SELECT .*").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "ctime", "utime"}).AddRow(1, utc, nil))
+		e, err := NewSelector[Event](mdb).Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, time.UTC, e.Ctime.Location())
+	})
 }
 
-func ExampleSelector_Select() {
-	db := memoryDB()
-	tm := TableOf(&TestModel{})
-	cases := []*Selector[TestModel]{
-		// case0: all columns are included
-		NewSelector[TestModel](db).From(tm),
-		// case1: only query specific columns
-		NewSelector[TestModel](db).Select(Columns("Id", "Age")).From(tm),
-		// case2: using alias
-		NewSelector[TestModel](db).Select(C("Id").As("my_id")).From(tm),
-		// case3: using aggregation function and alias
-		NewSelector[TestModel](db).Select(Avg("Age").As("avg_age")).From(tm),
-		// case4: using raw expression
-		NewSelector[TestModel](db).Select(Raw("COUNT(DISTINCT `age`) AS `age_cnt`")).From(tm),
+// testDecimal 模拟 shopspring/decimal 的 Decimal：
+// 值接收器实现 driver.Valuer，指针接收器实现 sql.Scanner，驱动返回的是 []byte 或者 string
+type testDecimal struct {
+	val string
+}
+
+func (d *testDecimal) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		d.val = string(v)
+	case string:
+		d.val = v
+	default:
+		return fmt.Errorf("不支持的类型 %T", src)
 	}
+	return nil
+}
 
-	for index, tc := range cases {
-		query, _ := tc.Build()
-		fmt.Printf("case%d:\n%s", index, query.string())
+func (d testDecimal) Value() (driver.Value, error) {
+	return d.val, nil
+}
+
+func TestSelector_Decimal(t *testing.T) {
+	type Account struct {
+		Id      int64
+		Balance testDecimal
+	}
+	testCases := []struct {
+		name string
+		opts []DBOption
+	}{
+		{
+			name: "unsafe",
+		},
+		{
+			name: "reflect",
+			opts: []DBOption{UseReflection()},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB, tc.opts...)
+			require.NoError(t, err)
+
+			mock.ExpectQuery("SELECT .*").WillReturnRows(
+				sqlmock.NewRows([]string{"id", "balance"}).AddRow(1, []byte("12345678901234567.89")))
+			acc, err := NewSelector[Account](db).Get(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, &Account{Id: 1, Balance: testDecimal{val: "12345678901234567.89"}}, acc)
+
+			mock.ExpectQuery("SELECT .*").WillReturnRows(
+				sqlmock.NewRows([]string{"id", "balance"}).AddRow(1, "0.1").AddRow(2, []byte("0.2")))
+			accs, err := NewSelector[Account](db).GetMulti(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []*Account{
+				{Id: 1, Balance: testDecimal{val: "0.1"}},
+				{Id: 2, Balance: testDecimal{val: "0.2"}},
+			}, accs)
+
+			mock.ExpectQuery("SELECT .*").WillReturnRows(
+				sqlmock.NewRows([]string{"balance"}).AddRow([]byte("99.99")))
+			balance, err := NewSelector[testDecimal](db).Select(C("Balance")).
+				From(TableOf(&Account{})).Get(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, &testDecimal{val: "99.99"}, balance)
+
+			mock.ExpectQuery("SELECT .*").WillReturnRows(
+				sqlmock.NewRows([]string{"balance"}).AddRow("0.1").AddRow("0.2"))
+			balances, err := NewSelector[testDecimal](db).Select(C("Balance")).
+				From(TableOf(&Account{})).GetMulti(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []*testDecimal{{val: "0.1"}, {val: "0.2"}}, balances)
+		})
 	}
-	// Output:
-	// case0:
-	// SQL: SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;
-	// Args: []interface {}(nil)
-	// case1:
-	// SQL: SELECT `id`,`age` FROM `test_model`;
-	// Args: []interface {}(nil)
-	// case2:
-	// SQL: SELECT `id` AS `my_id` FROM `test_model`;
-	// Args: []interface {}(nil)
-	// case3:
-	// SQL: SELECT AVG(`age`) AS `avg_age` FROM `test_model`;
-	// Args: []interface {}(nil)
-	// case4:
-	// SQL: SELECT COUNT(DISTINCT `age`) AS `age_cnt` FROM `test_model`;
-	// Args: []interface {}(nil)
 }
 
-func ExampleSelector_Distinct() {
+func TestSelector_FieldPath(t *testing.T) {
 	db := memoryDB()
-	tm := TableOf(&TestModel{})
-	cases := []*Selector[TestModel]{
-		// case0: disinct column
-		NewSelector[TestModel](db).From(tm).Distinct().Select(C("FirstName")),
-		// case1: aggregation function using distinct
-		NewSelector[TestModel](db).From(tm).Select(CountDistinct("FirstName")),
-		// case2: having using distinct
-		NewSelector[TestModel](db).From(tm).Select(C("FirstName")).GroupBy("FirstName").Having(CountDistinct("FirstName").EQ("jack")),
+	testCases := []CommonTestCase{
+		{
+			name:     "embedded field path",
+			builder:  NewSelector[TestCombinedModel](db).Select(C("BaseEntity.CreateTime"), C("Id")).Where(C("BaseEntity.UpdateTime").GT(1)),
+			wantSql:  "SELECT `create_time`,`id` FROM `test_combined_model` WHERE `update_time`>?;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "invalid embedded field path",
+			builder: NewSelector[TestCombinedModel](db).Where(C("BaseEntity.CreateTimeX").GT(1)),
+			wantErr: errs.NewInvalidFieldPathError("TestCombinedModel", "BaseEntity.CreateTimeX"),
+		},
+		{
+			name:    "invalid embedded field path in order by",
+			builder: NewSelector[TestCombinedModel](db).OrderBy(ASC("BaseEntity.CreateTimeX")),
+			wantErr: errs.NewInvalidFieldPathError("TestCombinedModel", "BaseEntity.CreateTimeX"),
+		},
+		{
+			name:    "invalid flat field",
+			builder: NewSelector[TestCombinedModel](db).Where(C("CreateTimeX").GT(1)),
+			wantErr: errs.NewInvalidFieldError("CreateTimeX"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
 	}
+}
 
-	for index, tc := range cases {
-		query, _ := tc.Build()
-		fmt.Printf("case%d:\n%s", index, query.string())
+// testUUID 模拟 google/uuid 的 UUID：它是一个 [16]byte，
+// 指针接收器实现 sql.Scanner，能够解析文本和 16 字节的二进制格式，值接收器实现 driver.Valuer
+type testUUID [16]byte
+
+func (u *testUUID) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return u.parse(v)
+	case []byte:
+		if len(v) == 16 {
+			copy(u[:], v)
+			return nil
+		}
+		return u.parse(string(v))
+	default:
+		return fmt.Errorf("不支持的类型 %T", src)
+	}
+}
+
+func (u *testUUID) parse(s string) error {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != 16 {
+		return fmt.Errorf("非法的 UUID %s", s)
+	}
+	copy(u[:], b)
+	return nil
+}
+
+func (u testUUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+func (u testUUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+func TestSelector_UUID(t *testing.T) {
+	type Device struct {
+		Id   testUUID `eorm:"primary_key"`
+		Name string
+	}
+	const text = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	var id testUUID
+	require.NoError(t, id.Scan(text))
+
+	t.Run("build", func(t *testing.T) {
+		query, err := NewSelector[Device](memoryDB()).Where(C("Id").EQ(id)).Build()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT `id`,`name` FROM `device` WHERE `id`=?;", query.SQL)
+		assert.Equal(t, []any{id}, query.Args)
+		meta, err := memoryDB().metaRegistry.Get(&Device{})
+		require.NoError(t, err)
+		assert.Equal(t, "Id", meta.PrimaryKeys[0].FieldName)
+	})
+
+	testCases := []struct {
+		name string
+		opts []DBOption
+	}{
+		{
+			name: "unsafe",
+		},
+		{
+			name: "reflect",
+			opts: []DBOption{UseReflection()},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB, tc.opts...)
+			require.NoError(t, err)
+
+			// 参数会使用 driver.Valuer 转化为文本
+			mock.ExpectQuery("SELECT `id`,`name` FROM `device` WHERE `id`=? LIMIT ?;").
+				WithArgs(text, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(text, "phone"))
+			d, err := NewSelector[Device](db).Where(C("Id").EQ(id)).Get(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, &Device{Id: id, Name: "phone"}, d)
+
+			mock.ExpectQuery("SELECT `id`,`name` FROM `device`;").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+					AddRow(text, "phone").AddRow(id[:], "pad"))
+			ds, err := NewSelector[Device](db).GetMulti(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []*Device{{Id: id, Name: "phone"}, {Id: id, Name: "pad"}}, ds)
+
+			mock.ExpectQuery("SELECT `id` FROM `device` LIMIT ?;").WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id[:]))
+			res, err := NewSelector[testUUID](db).Select(C("Id")).From(TableOf(&Device{})).Get(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, id, *res)
+
+			mock.ExpectQuery("SELECT `id` FROM `device`;").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(text).AddRow(id[:]))
+			ids, err := NewSelector[testUUID](db).Select(C("Id")).From(TableOf(&Device{})).GetMulti(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []*testUUID{&id, &id}, ids)
+		})
+	}
+}
+
+func TestSelector_JSON(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Profile struct {
+		Id      int64
+		Meta    map[string]any `eorm:"json"`
+		Tags    []string       `eorm:"json"`
+		Address *Address       `eorm:"json"`
+	}
+	testCases := []struct {
+		name string
+		opts []DBOption
+	}{
+		{
+			name: "unsafe",
+		},
+		{
+			name: "reflect",
+			opts: []DBOption{UseReflection()},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB, tc.opts...)
+			require.NoError(t, err)
+
+			mock.ExpectQuery("SELECT `id`,`meta`,`tags`,`address` FROM `profile` LIMIT ?;").
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "meta", "tags", "address"}).
+					AddRow(1, []byte(`{"age":18}`), `["a","b"]`, `{"city":"深圳"}`))
+			p, err := NewSelector[Profile](db).Get(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, &Profile{
+				Id:      1,
+				Meta:    map[string]any{"age": float64(18)},
+				Tags:    []string{"a", "b"},
+				Address: &Address{City: "深圳"},
+			}, p)
+
+			// NULL 保持零值
+			mock.ExpectQuery("SELECT `id`,`meta`,`tags`,`address` FROM `profile`;").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "meta", "tags", "address"}).
+					AddRow(1, nil, nil, nil).AddRow(2, `{}`, `[]`, nil))
+			ps, err := NewSelector[Profile](db).GetMulti(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []*Profile{
+				{Id: 1},
+				{Id: 2, Meta: map[string]any{}, Tags: []string{}},
+			}, ps)
+
+			mock.ExpectQuery("SELECT `id`,`meta`,`tags`,`address` FROM `profile` LIMIT ?;").
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "meta", "tags", "address"}).
+					AddRow(1, `{"age":`, nil, nil))
+			_, err = NewSelector[Profile](db).Get(context.Background())
+			require.Error(t, err)
+			assert.True(t, strings.Contains(err.Error(), "eorm: 列 meta 不是合法的 JSON"))
+			var syntaxErr *json.SyntaxError
+			assert.True(t, errors.As(err, &syntaxErr))
+		})
+	}
+}
+
+func TestSelector_DefaultTag(t *testing.T) {
+	type Profile struct {
+		Id       int64
+		Age      int8    `eorm:"default=18"`
+		Nickname string  `eorm:"default=unknown"`
+		Score    float64 `eorm:"default=0"`
+	}
+	testCases := []struct {
+		name string
+		opts []DBOption
+	}{
+		{
+			name: "unsafe",
+		},
+		{
+			name: "reflect",
+			opts: []DBOption{UseReflection()},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB, tc.opts...)
+			require.NoError(t, err)
+
+			mock.ExpectQuery("SELECT `id`,`age`,`nickname`,`score` FROM `profile`;").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "age", "nickname", "score"}).
+					AddRow(1, nil, nil, nil).
+					AddRow(2, 20, "Tom", 9.5))
+			res, err := NewSelector[Profile](db).GetMulti(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []*Profile{
+				{Id: 1, Age: 18, Nickname: "unknown"},
+				{Id: 2, Age: 20, Nickname: "Tom", Score: 9.5},
+			}, res)
+		})
+	}
+}
+
+func TestSelector_NullTypes(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name string
+		// val 是非 NULL 的时候返回的值
+		val any
+		// get 和 getMulti 分别使用 Get 和 GetMulti 读取 age 列
+		get      func(db *DB) (any, error)
+		getMulti func(db *DB) (any, error)
+		wantVal  any
+		wantNull any
+	}{
+		{
+			name:     "NullString",
+			val:      "Tom",
+			get:      nullGet[sql.NullString],
+			getMulti: nullGetMulti[sql.NullString],
+			wantVal:  sql.NullString{String: "Tom", Valid: true},
+			wantNull: sql.NullString{},
+		},
+		{
+			name:     "NullInt64",
+			val:      int64(18),
+			get:      nullGet[sql.NullInt64],
+			getMulti: nullGetMulti[sql.NullInt64],
+			wantVal:  sql.NullInt64{Int64: 18, Valid: true},
+			wantNull: sql.NullInt64{},
+		},
+		{
+			name:     "NullInt32",
+			val:      int64(18),
+			get:      nullGet[sql.NullInt32],
+			getMulti: nullGetMulti[sql.NullInt32],
+			wantVal:  sql.NullInt32{Int32: 18, Valid: true},
+			wantNull: sql.NullInt32{},
+		},
+		{
+			name:     "NullInt16",
+			val:      int64(18),
+			get:      nullGet[sql.NullInt16],
+			getMulti: nullGetMulti[sql.NullInt16],
+			wantVal:  sql.NullInt16{Int16: 18, Valid: true},
+			wantNull: sql.NullInt16{},
+		},
+		{
+			name:     "NullByte",
+			val:      int64(18),
+			get:      nullGet[sql.NullByte],
+			getMulti: nullGetMulti[sql.NullByte],
+			wantVal:  sql.NullByte{Byte: 18, Valid: true},
+			wantNull: sql.NullByte{},
+		},
+		{
+			name:     "NullFloat64",
+			val:      18.5,
+			get:      nullGet[sql.NullFloat64],
+			getMulti: nullGetMulti[sql.NullFloat64],
+			wantVal:  sql.NullFloat64{Float64: 18.5, Valid: true},
+			wantNull: sql.NullFloat64{},
+		},
+		{
+			name:     "NullBool",
+			val:      true,
+			get:      nullGet[sql.NullBool],
+			getMulti: nullGetMulti[sql.NullBool],
+			wantVal:  sql.NullBool{Bool: true, Valid: true},
+			wantNull: sql.NullBool{},
+		},
+		{
+			name:     "NullTime",
+			val:      now,
+			get:      nullGet[sql.NullTime],
+			getMulti: nullGetMulti[sql.NullTime],
+			wantVal:  sql.NullTime{Time: now, Valid: true},
+			wantNull: sql.NullTime{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := openDB("mysql", mockDB)
+			require.NoError(t, err)
+
+			mock.ExpectQuery("SELECT `age` FROM `test_model` LIMIT ?;").WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"age"}).AddRow(tc.val))
+			res, err := tc.get(db)
+			require.NoError(t, err)
+			assert.Equal(t, []any{tc.wantVal}, res)
+
+			mock.ExpectQuery("SELECT `age` FROM `test_model` LIMIT ?;").WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"age"}).AddRow(nil))
+			res, err = tc.get(db)
+			require.NoError(t, err)
+			assert.Equal(t, []any{tc.wantNull}, res)
+
+			mock.ExpectQuery("SELECT `age` FROM `test_model`;").
+				WillReturnRows(sqlmock.NewRows([]string{"age"}).AddRow(tc.val).AddRow(nil))
+			res, err = tc.getMulti(db)
+			require.NoError(t, err)
+			assert.Equal(t, []any{tc.wantVal, tc.wantNull}, res)
+		})
+	}
+}
+
+// nullGet 使用 Get 读取 age 列，结果放在切片中方便和 GetMulti 的结果统一比较
+func nullGet[T any](db *DB) (any, error) {
+	res, err := NewSelector[T](db).Select(C("Age")).From(TableOf(&TestModel{})).Get(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return []any{*res}, nil
+}
+
+func nullGetMulti[T any](db *DB) (any, error) {
+	res, err := NewSelector[T](db).Select(C("Age")).From(TableOf(&TestModel{})).GetMulti(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]any, 0, len(res))
+	for _, r := range res {
+		vals = append(vals, *r)
+	}
+	return vals, nil
+}
+
+func TestSelector_ScalarSubquery(t *testing.T) {
+	type ScalarUser struct {
+		Id         int64
+		Age        int
+		OrderCount int64
+	}
+	type ScalarOrder struct {
+		Id     int64
+		UserId int64
+		Status int
+	}
+	u := TableOf(&ScalarUser{}).As("u")
+	orderCount := func(db *DB) Subquery {
+		return NewSelector[ScalarOrder](db).Select(CountAll()).
+			Where(C("UserId").EQ(u.C("Id")), C("Status").EQ(1)).AsSubquery("order_count")
+	}
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name: "correlated",
+			builder: NewSelector[ScalarUser](db).Select(u.C("Id"), orderCount(db)).
+				From(u).Where(u.C("Age").GT(18)),
+			wantSql: "SELECT `u`.`id`,(SELECT COUNT(*) FROM `scalar_order` WHERE (`user_id`=`u`.`id`) AND (`status`=?)) AS `order_count` " +
+				"FROM `scalar_user` AS `u` WHERE `u`.`age`>?;",
+			wantArgs: []interface{}{1, 18},
+		},
+		{
+			name: "as",
+			builder: NewSelector[ScalarUser](db).
+				Select(NewSelector[ScalarOrder](db).Select(Max("Id")).AsSubquery("sub").As("max_order_id")),
+			wantSql: "SELECT (SELECT MAX(`id`) FROM `scalar_order`) AS `max_order_id` FROM `scalar_user`;",
+		},
+		{
+			name: "postgres",
+			builder: NewSelector[ScalarUser](postgresDB()).Select(u.C("Id"), orderCount(postgresDB())).
+				From(u).Where(u.C("Age").GT(18)),
+			wantSql: `SELECT "u"."id",(SELECT COUNT(*) FROM "scalar_order" WHERE ("user_id"="u"."id") AND ("status"=$1)) AS "order_count" ` +
+				`FROM "scalar_user" AS "u" WHERE "u"."age">$2;`,
+			wantArgs: []interface{}{1, 18},
+		},
+		{
+			name: "order by alias",
+			builder: NewSelector[ScalarUser](db).Select(u.C("Id"), orderCount(db)).
+				From(u).OrderBy(DESC("order_count")),
+			wantSql: "SELECT `u`.`id`,(SELECT COUNT(*) FROM `scalar_order` WHERE (`user_id`=`u`.`id`) AND (`status`=?)) AS `order_count` " +
+				"FROM `scalar_user` AS `u` ORDER BY `order_count` DESC;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "multiple columns",
+			builder: NewSelector[ScalarUser](db).
+				Select(NewSelector[ScalarOrder](db).Select(C("Id"), C("Status")).AsSubquery("sub")),
+			wantErr: errs.NewScalarSubqueryColumnsError(2),
+		},
+		{
+			name:    "all columns",
+			builder: NewSelector[ScalarUser](db).Select(NewSelector[ScalarOrder](db).AsSubquery("sub")),
+			wantErr: errs.NewScalarSubqueryColumnsError(3),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
 	}
-	// Output:
-	// case0:
-	// SQL: SELECT DISTINCT `first_name` FROM `test_model`;
-	// Args: []interface {}(nil)
-	// case1:
-	// SQL: SELECT COUNT(DISTINCT `first_name`) FROM `test_model`;
-	// Args: []interface {}(nil)
-	// case2:
-	// SQL: SELECT `first_name` FROM `test_model` GROUP BY `first_name` HAVING COUNT(DISTINCT `first_name`)=?;
-	// Args: []interface {}{"jack"}
 }
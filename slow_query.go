@@ -0,0 +1,71 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Logger 用于输出日志，*log.Logger 实现了该接口
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+type slowQueryConfig struct {
+	redactArgs bool
+}
+
+// SlowQueryOption 是 DBWithSlowQueryThreshold 的选项
+type SlowQueryOption func(cfg *slowQueryConfig)
+
+// SlowQueryWithRedactedArgs 在输出慢查询日志的时候隐藏参数的值，只保留参数的个数
+// 如果参数里面可能有敏感数据，例如密码、手机号，那么应该开启
+func SlowQueryWithRedactedArgs() SlowQueryOption {
+	return func(cfg *slowQueryConfig) {
+		cfg.redactArgs = true
+	}
+}
+
+// DBWithSlowQueryThreshold 在查询耗时超过 threshold 的时候输出一条日志，包含 SQL、参数和耗时
+// logger 为 nil 的时候使用 log.Default()
+// 它是通过 Middleware 实现的，所以需要放在 DBWithMiddleware 之后
+func DBWithSlowQueryThreshold(threshold time.Duration, logger Logger, opts ...SlowQueryOption) DBOption {
+	if logger == nil {
+		logger = log.Default()
+	}
+	cfg := &slowQueryConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	h := Hook{
+		After: func(ctx context.Context, qc *QueryContext, res *QueryResult, duration time.Duration) {
+			if duration < threshold {
+				return
+			}
+			var args any = qc.q.Args
+			if cfg.redactArgs {
+				redacted := make([]string, len(qc.q.Args))
+				for i := range redacted {
+					redacted[i] = "***"
+				}
+				args = redacted
+			}
+			logger.Printf("eorm: 慢查询 耗时 %s SQL: %s 参数: %v", duration, qc.q.SQL, args)
+		},
+	}
+	return DBWithHooks(h)
+}
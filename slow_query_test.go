@@ -0,0 +1,86 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBWithSlowQueryThreshold(t *testing.T) {
+	testCases := []struct {
+		name      string
+		threshold time.Duration
+		delay     time.Duration
+		opts      []SlowQueryOption
+		wantLog   string
+	}{
+		{
+			name:      "slow",
+			threshold: 10 * time.Millisecond,
+			delay:     20 * time.Millisecond,
+			wantLog:   "SQL: SELECT `id` FROM `test_model` WHERE `first_name`=?; 参数: [Tom]",
+		},
+		{
+			name:      "slow with redacted args",
+			threshold: 10 * time.Millisecond,
+			delay:     20 * time.Millisecond,
+			opts:      []SlowQueryOption{SlowQueryWithRedactedArgs()},
+			wantLog:   "SQL: SELECT `id` FROM `test_model` WHERE `first_name`=?; 参数: [***]",
+		},
+		{
+			name:      "fast",
+			threshold: time.Minute,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			logger := &mockLogger{}
+			db, err := openDB("mysql", mockDB, DBWithSlowQueryThreshold(tc.threshold, logger, tc.opts...))
+			require.NoError(t, err)
+
+			mock.ExpectQuery("SELECT `id` FROM `test_model` WHERE `first_name`=?;").WithArgs("Tom").
+				WillDelayFor(tc.delay).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			_, err = NewSelector[TestModel](db).Select(C("Id")).Where(C("FirstName").EQ("Tom")).
+				GetMulti(context.Background())
+			require.NoError(t, err)
+
+			if tc.wantLog == "" {
+				assert.Empty(t, logger.logs)
+				return
+			}
+			require.Equal(t, 1, len(logger.logs))
+			assert.Contains(t, logger.logs[0], "eorm: 慢查询")
+			assert.Contains(t, logger.logs[0], tc.wantLog)
+		})
+	}
+}
+
+type mockLogger struct {
+	logs []string
+}
+
+func (m *mockLogger) Printf(format string, args ...any) {
+	m.logs = append(m.logs, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,181 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBWithStmtCache(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB, DBWithStmtCache(2))
+	require.NoError(t, err)
+
+	mock.ExpectPrepare("SELECT `id` FROM `a`;").WillBeClosed().
+		ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectPrepare("SELECT `id` FROM `b`;").WillBeClosed().
+		ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	// 命中缓存，不会再次预编译
+	mock.ExpectQuery("SELECT `id` FROM `a`;").WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	// 缓存已满，淘汰最久没有使用的 b
+	mock.ExpectPrepare("UPDATE `c` SET `id`=?;").WillBeClosed().
+		ExpectExec().WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("SELECT `id` FROM `b`;").WillBeClosed().
+		ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectClose()
+
+	testCases := []struct {
+		name    string
+		query   string
+		args    []any
+		exec    bool
+		wantLen int
+	}{
+		{name: "prepare a", query: "SELECT `id` FROM `a`;", args: []any{1}, wantLen: 1},
+		{name: "prepare b", query: "SELECT `id` FROM `b`;", wantLen: 2},
+		{name: "reuse a", query: "SELECT `id` FROM `a`;", args: []any{3}, wantLen: 2},
+		{name: "evict b", query: "UPDATE `c` SET `id`=?;", args: []any{4}, exec: true, wantLen: 2},
+		{name: "prepare b again", query: "SELECT `id` FROM `b`;", wantLen: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.exec {
+				_, err = db.execContext(context.Background(), tc.query, tc.args...)
+				require.NoError(t, err)
+			} else {
+				var rows *sql.Rows
+				rows, err = db.queryContext(context.Background(), tc.query, tc.args...)
+				require.NoError(t, err)
+				require.NoError(t, rows.Close())
+			}
+			assert.Equal(t, tc.wantLen, db.stmtCache.len())
+		})
+	}
+
+	require.NoError(t, db.Close())
+	assert.Equal(t, 0, db.stmtCache.len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBWithStmtCache_queryTag(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	db, err := openDB("mysql", mockDB, DBWithStmtCache(1))
+	require.NoError(t, err)
+
+	mock.ExpectPrepare("SELECT `id` FROM `a`;").WillBeClosed().
+		ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	// 带有标签的查询直接执行，不会预编译，也不会淘汰缓存中的语句
+	mock.ExpectQuery("/* req=1 */ SELECT `id` FROM `a`;").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec("/* req=2 */ UPDATE `a` SET `id`=?;").WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 没有标签的查询仍然命中缓存
+	mock.ExpectQuery("SELECT `id` FROM `a`;").WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectClose()
+
+	ctx := context.Background()
+	rows, err := db.queryContext(ctx, "SELECT `id` FROM `a`;", 1)
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	rows, err = db.queryContext(WithQueryTag(ctx, "req", "1"), "SELECT `id` FROM `a`;", 2)
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	_, err = db.execContext(WithQueryTag(ctx, "req", "2"), "UPDATE `a` SET `id`=?;", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.stmtCache.len())
+	rows, err = db.queryContext(ctx, "SELECT `id` FROM `a`;", 4)
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	require.NoError(t, db.Close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBWithStmtCache_concurrent(t *testing.T) {
+	db, err := Open("sqlite3", "file:stmtCacheConcurrent.db?cache=shared&mode=memory", DBWithStmtCache(2))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				// 四条 SQL 轮流使用容量为 2 的缓存，会不断触发淘汰
+				n := (i + j) % 4
+				val, err := RawQuery[int](db, fmt.Sprintf("SELECT %d + ?", n), j).Get(context.Background())
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, n+j, *val)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.True(t, db.stmtCache.len() <= 2)
+}
+
+// go test -bench=BenchmarkDBWithStmtCache -benchmem -benchtime=2000x
+// BenchmarkDBWithStmtCache/without_cache         	    2000	      9979 ns/op	    2256 B/op	      54 allocs/op
+// BenchmarkDBWithStmtCache/with_cache            	    2000	      7402 ns/op	    2144 B/op	      52 allocs/op
+func BenchmarkDBWithStmtCache(b *testing.B) {
+	newDB := func(name string, opts ...DBOption) *DB {
+		orm, err := Open("sqlite3", fmt.Sprintf("file:%s.db?cache=shared&mode=memory", name), opts...)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err = RawQuery[any](orm, TestModel{}.CreateSQL()).Exec(context.Background()).Err(); err != nil {
+			b.Fatal(err)
+		}
+		if err = NewInserter[TestModel](orm).Values(&TestModel{Id: 12, FirstName: "Deng", Age: 18, LastName: &sql.NullString{String: "Ming", Valid: true}}).
+			Exec(context.Background()).Err(); err != nil {
+			b.Fatal(err)
+		}
+		return orm
+	}
+
+	testCases := []struct {
+		name string
+		db   *DB
+	}{
+		{name: "without cache", db: newDB("benchmarkWithoutStmtCache")},
+		{name: "with cache", db: newDB("benchmarkWithStmtCache", DBWithStmtCache(16))},
+	}
+	for _, tc := range testCases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := NewSelector[TestModel](tc.db).Where(C("Id").EQ(12)).Get(context.Background())
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		_ = tc.db.Close()
+	}
+}
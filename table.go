@@ -1,5 +1,7 @@
 package eorm
 
+import "strings"
+
 type TableReference interface {
 	tableAlias() string
 }
@@ -7,6 +9,14 @@ type TableReference interface {
 type Table struct {
 	entity any
 	alias  string
+	// hint 使用指针，保证 Table 依旧是可以比较的
+	hint *indexHint
+}
+
+// indexHint 是 MySQL 的索引提示，例如 USE INDEX (`idx_age`)
+type indexHint struct {
+	typ     string
+	indexes []string
 }
 
 func TableOf(entity any) Table {
@@ -27,10 +37,27 @@ func (t Table) tableAlias() string {
 }
 
 func (t Table) As(alias string) Table {
-	return Table{
-		entity: t.entity,
-		alias:  alias,
-	}
+	t.alias = alias
+	return t
+}
+
+// UseIndex 建议 MySQL 使用 indexes 中的索引，对应 USE INDEX (...)
+// 索引提示属于表本身，所以在子查询中使用的时候，它会出现在子查询里面
+func (t Table) UseIndex(indexes ...string) Table {
+	t.hint = &indexHint{typ: "USE INDEX", indexes: indexes}
+	return t
+}
+
+// ForceIndex 强制 MySQL 使用 indexes 中的索引，对应 FORCE INDEX (...)
+func (t Table) ForceIndex(indexes ...string) Table {
+	t.hint = &indexHint{typ: "FORCE INDEX", indexes: indexes}
+	return t
+}
+
+// IgnoreIndex 让 MySQL 忽略 indexes 中的索引，对应 IGNORE INDEX (...)
+func (t Table) IgnoreIndex(indexes ...string) Table {
+	t.hint = &indexHint{typ: "IGNORE INDEX", indexes: indexes}
+	return t
 }
 
 func (t Table) Join(target TableReference) *JoinBuilder {
@@ -57,6 +84,24 @@ func (t Table) RightJoin(target TableReference) *JoinBuilder {
 	}
 }
 
+// FullJoin represents FULL OUTER JOIN，目前只有 PostgreSQL 支持
+func (t Table) FullJoin(target TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  t,
+		right: target,
+		typ:   "FULL OUTER JOIN",
+	}
+}
+
+// CrossJoin represents CROSS JOIN，它不能带有 ON 或者 USING
+func (t Table) CrossJoin(target TableReference) Join {
+	return Join{
+		left:  t,
+		right: target,
+		typ:   "CROSS JOIN",
+	}
+}
+
 type Subquery struct {
 	entity  TableReference
 	q       QueryBuilder
@@ -66,9 +111,49 @@ type Subquery struct {
 
 var _ TableReference = Subquery{}
 
+// FromRawQuery 将一个已经构造好的 Query 作为派生表，q 的参数会放在 FROM 对应的位置
+// 因为 eorm 无法知道 q 返回了哪些列，所以通过 C 引用的列会按照外层 Selector 的目标类型来解析
+func FromRawQuery(q *Query, alias string) Subquery {
+	return Subquery{
+		q:     rawQueryBuilder{q: q},
+		alias: alias,
+	}
+}
+
+// rawQueryBuilder 将 Query 包装为 QueryBuilder
+type rawQueryBuilder struct {
+	q *Query
+}
+
+func (r rawQueryBuilder) Build() (*Query, error) {
+	// 构造子查询的时候会去掉结尾的 ;
+	return &Query{
+		SQL:  strings.TrimSuffix(strings.TrimSpace(r.q.SQL), ";") + ";",
+		Args: r.q.Args,
+	}, nil
+}
+
 func (Subquery) expr() (string, error) {
 	panic("implement me")
 }
+
+// As 指定别名，作为标量子查询出现在 Select 中的时候，它就是列的别名
+func (s Subquery) As(alias string) Subquery {
+	s.alias = alias
+	return s
+}
+
+func (s Subquery) selectedAlias() string {
+	return s.alias
+}
+
+func (Subquery) selectedTable() TableReference {
+	return nil
+}
+
+func (Subquery) fieldName() string {
+	return ""
+}
 func (s Subquery) tableAlias() string {
 	return s.alias
 }
@@ -106,6 +191,183 @@ func (s Subquery) RightJoin(target TableReference) *JoinBuilder {
 	}
 }
 
+// FullJoin represents FULL OUTER JOIN，目前只有 PostgreSQL 支持
+func (s Subquery) FullJoin(target TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  s,
+		right: target,
+		typ:   "FULL OUTER JOIN",
+	}
+}
+
+// CrossJoin represents CROSS JOIN，它不能带有 ON 或者 USING
+func (s Subquery) CrossJoin(target TableReference) Join {
+	return Join{
+		left:  s,
+		right: target,
+		typ:   "CROSS JOIN",
+	}
+}
+
+// CTE 代表在 WITH 子句中定义的公共表表达式，
+// 可以作为 From 或者 Join 的目标
+type CTE struct {
+	name  string
+	alias string
+}
+
+var _ TableReference = CTE{}
+
+// CTEOf 引用名字为 name 的公共表表达式，name 需要通过 Selector.With 定义
+func CTEOf(name string) CTE {
+	return CTE{name: name}
+}
+
+func (c CTE) tableAlias() string {
+	if c.alias != "" {
+		return c.alias
+	}
+	return c.name
+}
+
+func (c CTE) As(alias string) CTE {
+	return CTE{
+		name:  c.name,
+		alias: alias,
+	}
+}
+
+func (c CTE) C(name string) Column {
+	return Column{
+		table: c,
+		name:  name,
+	}
+}
+
+func (c CTE) Join(target TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  c,
+		right: target,
+		typ:   "JOIN",
+	}
+}
+
+func (c CTE) LeftJoin(target TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  c,
+		right: target,
+		typ:   "LEFT JOIN",
+	}
+}
+
+func (c CTE) RightJoin(target TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  c,
+		right: target,
+		typ:   "RIGHT JOIN",
+	}
+}
+
+// FullJoin represents FULL OUTER JOIN，目前只有 PostgreSQL 支持
+func (c CTE) FullJoin(target TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  c,
+		right: target,
+		typ:   "FULL OUTER JOIN",
+	}
+}
+
+// CrossJoin represents CROSS JOIN，它不能带有 ON 或者 USING
+func (c CTE) CrossJoin(target TableReference) Join {
+	return Join{
+		left:  c,
+		right: target,
+		typ:   "CROSS JOIN",
+	}
+}
+
+// LeftJoinLateralTop1 对左边的每一行，按照 orderBy 排序之后取子查询的第一行进行关联，
+// 即 LEFT JOIN LATERAL (SELECT ... ORDER BY ... LIMIT 1) AS sub ON TRUE
+// 子查询可以通过 t.C(...) 引用左边的列，目前只有 PostgreSQL 支持
+func (t Table) LeftJoinLateralTop1(sub Subquery, orderBy []OrderBy) Join {
+	return leftJoinLateralTop1(t, sub, orderBy)
+}
+
+// LeftJoinLateralTop1 参考 Table.LeftJoinLateralTop1
+func (j Join) LeftJoinLateralTop1(sub Subquery, orderBy []OrderBy) Join {
+	return leftJoinLateralTop1(j, sub, orderBy)
+}
+
+// CrossApply 对左边的每一行执行子查询 sub，只保留 sub 有结果的行，即 SQL Server 的 CROSS APPLY
+// sub 可以通过 t.C(...) 引用左边的列。在 PostgreSQL 上会被翻译为 JOIN LATERAL (...) ON TRUE，
+// 其它方言需要实现 dialect.ApplySupporter 才能使用
+func (t Table) CrossApply(sub Subquery) Join {
+	return Join{
+		left:  t,
+		right: sub,
+		typ:   "CROSS APPLY",
+	}
+}
+
+// OuterApply 和 CrossApply 类似，但是会保留 sub 没有结果的行，即 SQL Server 的 OUTER APPLY
+// 在 PostgreSQL 上会被翻译为 LEFT JOIN LATERAL (...) ON TRUE
+func (t Table) OuterApply(sub Subquery) Join {
+	return Join{
+		left:  t,
+		right: sub,
+		typ:   "OUTER APPLY",
+	}
+}
+
+// CrossApply 参考 Table.CrossApply
+func (j Join) CrossApply(sub Subquery) Join {
+	return Join{
+		left:  j,
+		right: sub,
+		typ:   "CROSS APPLY",
+	}
+}
+
+// OuterApply 参考 Table.OuterApply
+func (j Join) OuterApply(sub Subquery) Join {
+	return Join{
+		left:  j,
+		right: sub,
+		typ:   "OUTER APPLY",
+	}
+}
+
+// topOneQuery 代表可以改写为"排序之后取第一行"的查询
+type topOneQuery interface {
+	topOne(orderBy []OrderBy) QueryBuilder
+}
+
+func leftJoinLateralTop1(left TableReference, sub Subquery, orderBy []OrderBy) Join {
+	if q, ok := sub.q.(topOneQuery); ok {
+		sub.q = q.topOne(orderBy)
+	}
+	return Join{
+		left:  left,
+		right: sub,
+		typ:   "LEFT JOIN LATERAL",
+		on:    []Predicate{Raw("TRUE").AsPredicate()},
+	}
+}
+
+// joinDepth 返回 JOIN 嵌套的深度，单独的表和子查询的深度为 0
+// 子查询内部的 JOIN 会在构造子查询的时候单独检查
+func joinDepth(table TableReference) int {
+	j, ok := table.(Join)
+	if !ok {
+		return 0
+	}
+	left, right := joinDepth(j.left), joinDepth(j.right)
+	if right > left {
+		left = right
+	}
+	return left + 1
+}
+
 type JoinBuilder struct {
 	left  TableReference
 	right TableReference
@@ -148,6 +410,24 @@ func (j Join) RightJoin(target TableReference) *JoinBuilder {
 	}
 }
 
+// FullJoin represents FULL OUTER JOIN，目前只有 PostgreSQL 支持
+func (j Join) FullJoin(target TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  j,
+		right: target,
+		typ:   "FULL OUTER JOIN",
+	}
+}
+
+// CrossJoin represents CROSS JOIN，它不能带有 ON 或者 USING
+func (j Join) CrossJoin(target TableReference) Join {
+	return Join{
+		left:  j,
+		right: target,
+		typ:   "CROSS JOIN",
+	}
+}
+
 func (j *JoinBuilder) On(ps ...Predicate) Join {
 	return Join{
 		left:  j.left,
@@ -165,3 +445,60 @@ func (j *JoinBuilder) Using(s ...string) Join {
 		using: s,
 	}
 }
+
+// JSONColumn 是 JSONTable 展开之后的列，typ 是列的 SQL 类型，path 是该列在数组元素中的路径
+type JSONColumn struct {
+	name string
+	typ  string
+	path string
+}
+
+// JSONCol 定义 JSONTable 中的一列，例如 JSONCol("sku", "VARCHAR(32)", "$.sku")
+func JSONCol(name string, typ string, path string) JSONColumn {
+	return JSONColumn{
+		name: name,
+		typ:  typ,
+		path: path,
+	}
+}
+
+// JSONTableRef 将 JSON 数组列展开为多行，可以作为 Join 的目标
+type JSONTableRef struct {
+	col     Column
+	path    string
+	columns []JSONColumn
+	alias   string
+}
+
+var _ TableReference = JSONTableRef{}
+
+// JSONTable 将 col 中 path 对应的 JSON 数组展开为一张表，cols 是展开之后的列
+// 在 MySQL 上会使用 JSON_TABLE，在 PostgreSQL 上会使用 jsonb_to_recordset，
+// 后者只能展开整个数组，并且按照列名读取元素中的键，所以 path 只能是 $[*]，列的路径只能是 $.列名
+// 展开的表必须通过 As 指定别名，例如：
+// o := TableOf(&Order{}).As("o")
+// NewSelector[Item](db).From(o.CrossJoin(JSONTable(o.C("Items"), "$[*]", JSONCol("sku", "VARCHAR(32)", "$.sku")).As("it")))
+func JSONTable(col Column, path string, cols ...JSONColumn) JSONTableRef {
+	return JSONTableRef{
+		col:     col,
+		path:    path,
+		columns: cols,
+	}
+}
+
+func (j JSONTableRef) tableAlias() string {
+	return j.alias
+}
+
+func (j JSONTableRef) As(alias string) JSONTableRef {
+	j.alias = alias
+	return j
+}
+
+// C 引用展开之后的列，name 是 JSONCol 中定义的列名
+func (j JSONTableRef) C(name string) Column {
+	return Column{
+		table: j,
+		name:  name,
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/gotomicro/eorm/internal/errs"
+)
+
+// Tracer 创建 Span，它的语义和 OpenTelemetry 的 trace.Tracer 保持一致，
+// 为了不引入依赖，eorm 没有直接使用 OpenTelemetry，用户可以用几行代码完成适配
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span 是链路追踪中的一个 span，一个查询对应一个 span
+type Span interface {
+	// SetAttribute 的 key 遵循 OpenTelemetry 数据库相关的语义约定，例如 db.statement
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+type tracerConfig struct {
+	sanitizer func(query string) string
+}
+
+// TracerOption 是 DBWithTracer 的选项
+type TracerOption func(cfg *tracerConfig)
+
+// TracerWithSanitizer 在记录 SQL 之前进行脱敏，返回空字符串的时候不记录 SQL
+// 默认情况下只记录带占位符的 SQL，不会记录参数
+func TracerWithSanitizer(fn func(query string) string) TracerOption {
+	return func(cfg *tracerConfig) {
+		cfg.sanitizer = fn
+	}
+}
+
+// DBWithTracer 为每一个查询创建一个 span，记录 SQL、表名、影响或者返回的行数以及错误
+// 它是通过 Middleware 实现的，所以需要放在 DBWithMiddleware 之后
+func DBWithTracer(tracer Tracer, opts ...TracerOption) DBOption {
+	cfg := &tracerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return func(db *DB) {
+		db.ms = append(db.ms, tracerMiddleware(db, tracer, cfg))
+	}
+}
+
+func tracerMiddleware(db *DB, tracer Tracer, cfg *tracerConfig) Middleware {
+	return func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, qc *QueryContext) *QueryResult {
+			var table string
+			if qc.meta != nil {
+				table = qc.meta.TableName
+			}
+			spanName := qc.Type
+			if table != "" {
+				spanName = spanName + " " + table
+			}
+			ctx, span := tracer.Start(ctx, spanName)
+			defer span.End()
+			// 在执行 Option 的时候方言还没有确定，所以在这里获取
+			span.SetAttribute("db.system", db.dialect.Name())
+			span.SetAttribute("db.operation", qc.Type)
+			if table != "" {
+				span.SetAttribute("db.sql.table", table)
+			}
+			stmt := qc.q.SQL
+			if cfg.sanitizer != nil {
+				stmt = cfg.sanitizer(stmt)
+			}
+			if stmt != "" {
+				span.SetAttribute("db.statement", stmt)
+			}
+
+			res := next(ctx, qc)
+			if res.Err != nil {
+				// 没有数据是正常的业务情况，不作为错误
+				if res.Err != errs.ErrNoRows {
+					span.RecordError(res.Err)
+				}
+				return res
+			}
+			if rows, ok := resultRows(res.Result); ok {
+				span.SetAttribute("db.rows", rows)
+			}
+			return res
+		}
+	}
+}
+
+// resultRows 返回查询返回的行数或者语句影响的行数
+func resultRows(result any) (int64, bool) {
+	switch r := result.(type) {
+	case nil:
+		return 0, false
+	case sql.Result:
+		n, err := r.RowsAffected()
+		return n, err == nil
+	case *sql.Rows:
+		// 流式读取的时候还不知道行数
+		return 0, false
+	}
+	val := reflect.ValueOf(result)
+	switch val.Kind() {
+	case reflect.Slice:
+		return int64(val.Len()), true
+	case reflect.Pointer:
+		return 1, true
+	}
+	return 0, false
+}
@@ -0,0 +1,178 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBWithTracer(t *testing.T) {
+	testCases := []struct {
+		name      string
+		opts      []TracerOption
+		mock      func(mock sqlmock.Sqlmock)
+		query     func(db *DB) error
+		wantSpans []*mockSpan
+	}{
+		{
+			name: "get multi",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id` FROM `test_model` WHERE `age`>?;").WithArgs(18).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+			},
+			query: func(db *DB) error {
+				_, err := NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).GetMulti(context.Background())
+				return err
+			},
+			wantSpans: []*mockSpan{
+				{
+					name: "SELECT test_model",
+					attrs: map[string]any{
+						"db.system":    "MySQL",
+						"db.operation": "SELECT",
+						"db.sql.table": "test_model",
+						"db.statement": "SELECT `id` FROM `test_model` WHERE `age`>?;",
+						"db.rows":      int64(2),
+					},
+					ended: true,
+				},
+			},
+		},
+		{
+			name: "exec with sanitizer",
+			opts: []TracerOption{TracerWithSanitizer(func(query string) string {
+				return strings.ToLower(query)
+			})},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE `test_model` SET `age`=?;").WithArgs(18).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			query: func(db *DB) error {
+				return NewUpdater[TestModel](db).Set(Assign("Age", 18)).Exec(context.Background()).Err()
+			},
+			wantSpans: []*mockSpan{
+				{
+					name: "UPDATE test_model",
+					attrs: map[string]any{
+						"db.system":    "MySQL",
+						"db.operation": "UPDATE",
+						"db.sql.table": "test_model",
+						"db.statement": "update `test_model` set `age`=?;",
+						"db.rows":      int64(3),
+					},
+					ended: true,
+				},
+			},
+		},
+		{
+			name: "error without statement",
+			opts: []TracerOption{TracerWithSanitizer(func(query string) string {
+				return ""
+			})},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` LIMIT ?;").
+					WithArgs(1).WillReturnError(errors.New("mock error"))
+			},
+			query: func(db *DB) error {
+				_, err := NewSelector[TestModel](db).Get(context.Background())
+				return err
+			},
+			wantSpans: []*mockSpan{
+				{
+					name: "SELECT test_model",
+					attrs: map[string]any{
+						"db.system":    "MySQL",
+						"db.operation": "SELECT",
+						"db.sql.table": "test_model",
+					},
+					errs:  []error{errors.New("mock error")},
+					ended: true,
+				},
+			},
+		},
+		{
+			name: "raw query without table",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT 1;").WillReturnRows(sqlmock.NewRows([]string{"1"}))
+			},
+			query: func(db *DB) error {
+				_, err := RawQuery[int](db, "SELECT 1;").Get(context.Background())
+				return err
+			},
+			wantSpans: []*mockSpan{
+				{
+					name: "RAW",
+					attrs: map[string]any{
+						"db.system":    "MySQL",
+						"db.operation": "RAW",
+						"db.statement": "SELECT 1;",
+					},
+					ended: true,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			tracer := &mockTracer{}
+			db, err := openDB("mysql", mockDB, DBWithTracer(tracer, tc.opts...))
+			require.NoError(t, err)
+			tc.mock(mock)
+			_ = tc.query(db)
+			assert.Equal(t, tc.wantSpans, tracer.spans)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+type mockTracer struct {
+	spans []*mockSpan
+}
+
+func (m *mockTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	span := &mockSpan{name: spanName, attrs: map[string]any{}}
+	m.spans = append(m.spans, span)
+	return ctx, span
+}
+
+type mockSpan struct {
+	name  string
+	attrs map[string]any
+	errs  []error
+	ended bool
+}
+
+func (m *mockSpan) SetAttribute(key string, value any) {
+	m.attrs[key] = value
+}
+
+func (m *mockSpan) RecordError(err error) {
+	m.errs = append(m.errs, err)
+}
+
+func (m *mockSpan) End() {
+	m.ended = true
+}
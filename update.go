@@ -20,6 +20,7 @@ import (
 	"reflect"
 
 	"github.com/gotomicro/eorm/internal/errs"
+	"github.com/gotomicro/eorm/internal/model"
 	"github.com/gotomicro/eorm/internal/valuer"
 
 	"github.com/valyala/bytebufferpool"
@@ -33,6 +34,18 @@ type Updater[T any] struct {
 	val     valuer.Value
 	where   []Predicate
 	assigns []Assignable
+	// returning 为 true 的时候会构造 RETURNING 部分
+	returning     bool
+	returningCols []string
+	// versioned 为 true 表示 Build 的时候加上了乐观锁的条件
+	versioned bool
+	// lockField 和 lockVersion 是通过 OptimisticLock 指定的版本号字段和当前的版本号
+	lockField   string
+	lockVersion any
+	// lockColumn 是 lockField 对应的列，在 Build 的时候解析
+	lockColumn *model.ColumnMeta
+	// changedAssigns 是通过 SetIfChanged 指定的赋值，会在 WHERE 中加上 col 和 val 不相等的条件
+	changedAssigns []Assignment
 }
 
 // NewUpdater 开始构建一个 UPDATE 查询
@@ -56,6 +69,8 @@ func (u *Updater[T]) Build() (*Query, error) {
 	defer bytebufferpool.Put(u.buffer)
 	var err error
 	t := new(T)
+	// 只有传入了实体才知道当前的版本号
+	hasEntity := u.table != nil
 	if u.table == nil {
 		u.table = t
 	}
@@ -63,30 +78,90 @@ func (u *Updater[T]) Build() (*Query, error) {
 	if err != nil {
 		return nil, err
 	}
+	if u.meta.IsView {
+		return nil, errs.NewReadOnlyViewError(u.meta.TableName)
+	}
+
+	if u.lockField != "" {
+		if u.lockColumn, err = fieldMeta(u.meta, u.lockField); err != nil {
+			return nil, err
+		}
+	}
 
 	u.val = u.valCreator.NewBasicTypeValue(u.table, u.meta)
 	u.args = make([]interface{}, 0, len(u.meta.Columns))
 
+	// 使用局部变量，Build 多次的时候不会重复追加 SetIfChanged 的赋值
+	assigns := u.assigns
+	if len(u.changedAssigns) > 0 {
+		assigns = make([]Assignable, 0, len(u.assigns)+len(u.changedAssigns))
+		assigns = append(assigns, u.assigns...)
+		for _, a := range u.changedAssigns {
+			assigns = append(assigns, a)
+		}
+	}
+
 	u.writeString("UPDATE ")
-	u.quote(u.meta.TableName)
+	u.quoteTable(u.meta.TableName)
 	u.writeString(" SET ")
-	if len(u.assigns) == 0 {
+	if len(assigns) == 0 {
 		err = u.buildDefaultColumns()
 	} else {
-		err = u.buildAssigns()
+		err = u.buildAssigns(assigns)
 	}
 	if err != nil {
 		return nil, err
 	}
+	if len(assigns) > 0 {
+		u.buildUpdatedAt(assigns)
+	}
 
-	if len(u.where) > 0 {
+	// OptimisticLock 指定的版本号优先
+	vc, cur := u.lockColumn, u.lockVersion
+	if vc == nil && u.meta.VersionColumn != nil && hasEntity {
+		vc = u.meta.VersionColumn
+		cur, _ = u.val.Field(vc.FieldName)
+	}
+	where := u.where
+	// 使用乐观锁的时候不加上 SetIfChanged 的条件，
+	// 否则值没有变化的时候也没有更新到数据，Exec 会误报 ErrOptimisticLock
+	if len(u.changedAssigns) > 0 && vc == nil {
+		where = make([]Predicate, 0, len(u.where)+len(u.changedAssigns))
+		where = append(where, u.where...)
+		for _, a := range u.changedAssigns {
+			p, err := u.changedPredicate(a)
+			if err != nil {
+				return nil, err
+			}
+			where = append(where, p)
+		}
+	}
+	if vc != nil {
+		// SET version=version+1 ... WHERE ... AND version=?
+		u.comma()
+		u.quote(vc.ColumnName)
+		u.writeByte('=')
+		u.quote(vc.ColumnName)
+		u.writeString("+1")
+		where = append(append(make([]Predicate, 0, len(where)+1), where...), C(vc.FieldName).EQ(cur))
+		u.versioned = true
+	}
+	if len(where) > 0 {
 		u.writeString(" WHERE ")
-		err = u.buildPredicates(u.where)
+		err = u.buildPredicates(where)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if u.returning {
+		if err = u.buildReturning(u.returningCols); err != nil {
+			return nil, err
+		}
+	}
+	if err = u.checkArgs(); err != nil {
+		return nil, err
+	}
 	u.end()
 	return &Query{
 		SQL:  u.buffer.String(),
@@ -94,30 +169,49 @@ func (u *Updater[T]) Build() (*Query, error) {
 	}, nil
 }
 
-func (u *Updater[T]) buildAssigns() error {
+// changedPredicate 构造 a 的列和值不相等的条件，
+// 使用 NULL 安全的比较，这样列原本是 NULL 或者更新为 NULL 的时候也能正确判断
+func (u *Updater[T]) changedPredicate(a Assignment) (Predicate, error) {
+	features := u.features()
+	if features.DistinctFrom != "" {
+		return Predicate{left: a.left, op: op{symbol: "IS DISTINCT FROM", text: features.DistinctFrom}, right: a.right}, nil
+	}
+	if features.NullSafeEqual != "" {
+		return Not(Predicate{left: a.left, op: op{symbol: "<=>", text: features.NullSafeEqual}, right: a.right}), nil
+	}
+	return Predicate{}, errs.NewUnsupportedFeatureError(u.dialect.Name(), "SetIfChanged")
+}
+
+func (u *Updater[T]) buildAssigns(assigns []Assignable) error {
 	has := false
-	for _, assign := range u.assigns {
+	for _, assign := range assigns {
 		if has {
 			u.comma()
 		}
 		switch a := assign.(type) {
 		case Column:
-			c, ok := u.meta.FieldMap[a.name]
-			if !ok {
-				return errs.NewInvalidFieldError(a.name)
+			c, err := u.assignableColumn(a.name)
+			if err != nil {
+				return err
 			}
 			val, _ := u.val.Field(a.name)
+			if val, err = columnValue(c, val); err != nil {
+				return err
+			}
 			u.quote(c.ColumnName)
 			_ = u.buffer.WriteByte('=')
 			u.parameter(val)
 			has = true
 		case columns:
 			for _, name := range a.cs {
-				c, ok := u.meta.FieldMap[name]
-				if !ok {
-					return errs.NewInvalidFieldError(name)
+				c, err := u.assignableColumn(name)
+				if err != nil {
+					return err
 				}
 				val, _ := u.val.Field(name)
+				if val, err = columnValue(c, val); err != nil {
+					return err
+				}
 				if has {
 					u.comma()
 				}
@@ -127,6 +221,18 @@ func (u *Updater[T]) buildAssigns() error {
 				has = true
 			}
 		case Assignment:
+			if col, ok := a.left.(Column); ok {
+				c, err := u.assignableColumn(col.name)
+				if err != nil {
+					return err
+				}
+				if v, ok := a.right.(valueExpr); ok && c.IsJSON {
+					if v.val, err = columnValue(c, v.val); err != nil {
+						return err
+					}
+					a.right = v
+				}
+			}
 			if err := u.buildExpr(binaryExpr(a)); err != nil {
 				return err
 			}
@@ -141,10 +247,74 @@ func (u *Updater[T]) buildAssigns() error {
 	return nil
 }
 
+// buildUpdatedAt 在 SET 后面加上更新时间，除非用户已经手动设置了
+func (u *Updater[T]) buildUpdatedAt(assigns []Assignable) {
+	for _, c := range u.meta.Columns {
+		if !c.IsUpdatedAt || isAssigned(assigns, c.FieldName) {
+			continue
+		}
+		u.comma()
+		u.quote(c.ColumnName)
+		u.writeByte('=')
+		u.parameter(timestampValue(c.Typ, u.clock()))
+	}
+}
+
+// isAssigned 判断用户是否通过 Set 或者 SetIfChanged 设置了 field
+func isAssigned(assigns []Assignable, field string) bool {
+	for _, assign := range assigns {
+		switch a := assign.(type) {
+		case Column:
+			if a.name == field {
+				return true
+			}
+		case columns:
+			for _, name := range a.cs {
+				if name == field {
+					return true
+				}
+			}
+		case Assignment:
+			if col, ok := a.left.(Column); ok && col.name == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// assignableColumn 返回可以通过 Set 更新的列，乐观锁的版本号由 eorm 维护
+func (u *Updater[T]) assignableColumn(field string) (*model.ColumnMeta, error) {
+	c, err := u.writableColumn(field)
+	if err != nil {
+		return nil, err
+	}
+	if u.isVersionColumn(c) {
+		return nil, errs.NewVersionColumnAssignedError(field)
+	}
+	return c, nil
+}
+
+// isVersionColumn 判断 c 是否是乐观锁的版本号，包括通过 OptimisticLock 指定的列
+func (u *Updater[T]) isVersionColumn(c *model.ColumnMeta) bool {
+	return c.IsVersion || c == u.lockColumn
+}
+
 func (u *Updater[T]) buildDefaultColumns() error {
 	has := false
 	for _, c := range u.meta.Columns {
+		// 版本号会在 Build 中单独处理
+		if c.IsGenerated || u.isVersionColumn(c) {
+			continue
+		}
 		val, _ := u.val.Field(c.FieldName)
+		if c.IsUpdatedAt {
+			val = timestampValue(c.Typ, u.clock())
+		}
+		val, err := columnValue(c, val)
+		if err != nil {
+			return err
+		}
 		if has {
 			_ = u.buffer.WriteByte(',')
 		}
@@ -165,12 +335,57 @@ func (u *Updater[T]) Set(assigns ...Assignable) *Updater[T] {
 	return u
 }
 
+// SetIfChanged 和 Set 一样指定更新的列，但是会为每一个赋值在 WHERE 中加上 col 和 val 不相等的条件，
+// 例如 PostgreSQL 中是 UPDATE t SET col=$1 WHERE ... AND (col IS DISTINCT FROM $2)，这样值没有变化的时候不会产生任何写入。
+// 比较是 NULL 安全的，MySQL 中是 NOT (col <=> ?)，SQLite 中是 col IS NOT ?。
+// 它生成的赋值会排在 Set 的后面，条件会排在 Where 的后面。
+// 使用乐观锁的时候不会加上这些条件，因为没有更新到数据会被当作版本号冲突，此时 SetIfChanged 和 Set 一样
+func (u *Updater[T]) SetIfChanged(assigns ...Assignment) *Updater[T] {
+	u.changedAssigns = assigns
+	return u
+}
+
+// OptimisticLock 使用 versionField 作为乐观锁的版本号，currentVersion 是读取数据时的版本号
+// 它会生成 SET ...,version=version+1 WHERE ... AND version=?，
+// Exec 没有更新到任何数据的时候会返回 ErrOptimisticLock。
+// versionField 不需要 eorm:"version" 标记，并且优先于该标记，它也不能通过 Set 更新
+func (u *Updater[T]) OptimisticLock(versionField string, currentVersion any) *Updater[T] {
+	u.lockField = versionField
+	u.lockVersion = currentVersion
+	return u
+}
+
+// TablePrefix 参考 Selector.TablePrefix
+func (u *Updater[T]) TablePrefix(prefix string) *Updater[T] {
+	u.tablePrefix = prefix
+	return u
+}
+
 // Where represents WHERE clause
 func (u *Updater[T]) Where(predicates ...Predicate) *Updater[T] {
 	u.where = predicates
 	return u
 }
 
+// Returning 指定 RETURNING 的列，不传入列的时候表示 RETURNING *
+// cs 必须是模型的字段名，只有支持 RETURNING 的方言才可以使用，
+// 例如 PostgreSQL 和 SQLite，其它方言会在 Build 的时候返回错误
+// 之后可以使用 GetMulti 读取返回的数据
+func (u *Updater[T]) Returning(cs ...string) *Updater[T] {
+	u.returning = true
+	u.returningCols = cs
+	return u
+}
+
+// GetMulti 执行带有 RETURNING 的更新语句，并且读取返回的数据
+func (u *Updater[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	query, err := u.Build()
+	if err != nil {
+		return nil, err
+	}
+	return newQuerier[T](u.session, query, u.meta, UPDATE).GetMulti(ctx)
+}
+
 // AssignNotNilColumns uses the non-nil value to construct the Assignable instances.
 func AssignNotNilColumns(entity interface{}) []Assignable {
 	return AssignColumns(entity, func(typ reflect.StructField, val reflect.Value) bool {
@@ -234,5 +449,13 @@ func (u *Updater[T]) Exec(ctx context.Context) Result {
 	if err != nil {
 		return Result{err: err}
 	}
-	return newQuerier[T](u.session, query, u.meta, UPDATE).Exec(ctx)
+	res := newQuerier[T](u.session, query, u.meta, UPDATE).Exec(ctx)
+	if res.err != nil || !u.versioned {
+		return res
+	}
+	// 乐观锁：没有更新到数据说明版本号已经变了
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return Result{err: errs.ErrOptimisticLock, res: res.res}
+	}
+	return res
 }
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	err "github.com/gotomicro/eorm/internal/errs"
@@ -122,6 +123,11 @@ func TestUpdater_Set(t *testing.T) {
 			wantSql:  "UPDATE `test_model` SET `id`=?;",
 			wantArgs: []interface{}{int64(13)},
 		},
+		{
+			name:    "view",
+			builder: NewUpdater[TestModelView](orm).Update(&TestModelView{Id: 12}).Set(C("FirstName")),
+			wantErr: err.NewReadOnlyViewError("test_model_view"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -138,6 +144,114 @@ func TestUpdater_Set(t *testing.T) {
 	}
 }
 
+func TestUpdater_SetIncrement(t *testing.T) {
+	type Stats struct {
+		Id     int64
+		Views  int64
+		Clicks int64
+	}
+	orm := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name: "two counters",
+			builder: NewUpdater[Stats](orm).Set(Assign("Views", C("Views").Add(1)),
+				Assign("Clicks", C("Clicks").Add(3))).Where(C("Id").EQ(12)),
+			wantSql:  "UPDATE `stats` SET `views`=(`views`+?),`clicks`=(`clicks`+?) WHERE `id`=?;",
+			wantArgs: []interface{}{1, 3, 12},
+		},
+		{
+			name: "postgres",
+			builder: NewUpdater[Stats](postgresDB()).Set(Assign("Views", C("Views").Add(1)),
+				Assign("Clicks", C("Clicks").Add(3))).Where(C("Id").EQ(12)),
+			wantSql:  `UPDATE "stats" SET "views"=("views"+$1),"clicks"=("clicks"+$2) WHERE "id"=$3;`,
+			wantArgs: []interface{}{1, 3, 12},
+		},
+		{
+			name: "invalid counter",
+			builder: NewUpdater[Stats](orm).Set(Assign("Views", C("Views").Add(1)),
+				Assign("Clicks", C("Invalid").Add(3))).Where(C("Id").EQ(12)),
+			wantErr: err.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestUpdater_Returning(t *testing.T) {
+	mysqlMock, _, e := sqlmock.New()
+	require.NoError(t, e)
+	mysqlDB, e := openDB("mysql", mysqlMock)
+	require.NoError(t, e)
+	pg := postgresDB()
+	testCases := []CommonTestCase{
+		{
+			name: "postgres returning all",
+			builder: NewUpdater[TestModel](pg).Set(Assign("Age", C("Age").Add(1))).
+				Where(C("Id").EQ(12)).Returning(),
+			wantSql:  `UPDATE "test_model" SET "age"=("age"+$1) WHERE "id"=$2 RETURNING *;`,
+			wantArgs: []interface{}{1, 12},
+		},
+		{
+			name: "postgres returning columns",
+			builder: NewUpdater[TestModel](pg).Set(Assign("Age", 18)).
+				Where(C("Id").EQ(12)).Returning("Id", "Age"),
+			wantSql:  `UPDATE "test_model" SET "age"=$1 WHERE "id"=$2 RETURNING "id","age";`,
+			wantArgs: []interface{}{18, 12},
+		},
+		{
+			name:    "invalid column",
+			builder: NewUpdater[TestModel](pg).Set(Assign("Age", 18)).Returning("Invalid"),
+			wantErr: err.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "mysql",
+			builder: NewUpdater[TestModel](mysqlDB).Set(Assign("Age", 18)).Returning(),
+			wantErr: err.NewUnsupportedFeatureError("MySQL", "RETURNING"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestUpdater_GetMulti(t *testing.T) {
+	mockDB, mock, e := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, e)
+	defer func() { _ = mockDB.Close() }()
+	db, e := openDB("postgres", mockDB)
+	require.NoError(t, e)
+
+	mock.ExpectQuery(`UPDATE "test_model" SET "age"=("age"+$1) WHERE "first_name"=$2 RETURNING "id","age";`).
+		WithArgs(1, "Tom").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "age"}).AddRow(1, 19).AddRow(3, 21))
+	res, e := NewUpdater[TestModel](db).Set(Assign("Age", C("Age").Add(1))).
+		Where(C("FirstName").EQ("Tom")).Returning("Id", "Age").GetMulti(context.Background())
+	require.NoError(t, e)
+	assert.Equal(t, []*TestModel{{Id: 1, Age: 19}, {Id: 3, Age: 21}}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpdater_SetForCombination(t *testing.T) {
 	type Person struct {
 		FirstName string
@@ -351,3 +465,441 @@ func ExampleAssignNotZeroColumns() {
 	// SQL: UPDATE `test_model` SET `id`=?;
 	// Args: []interface {}{13}
 }
+
+func TestUpdater_GeneratedColumn(t *testing.T) {
+	type Product struct {
+		Id       int64
+		Price    int64
+		Quantity int64
+		Total    int64 `eorm:"generated"`
+	}
+	mysqlMock, _, e := sqlmock.New()
+	require.NoError(t, e)
+	mysqlDB, e := openDB("mysql", mysqlMock)
+	require.NoError(t, e)
+	p := &Product{Id: 1, Price: 10, Quantity: 3, Total: 30}
+	testCases := []CommonTestCase{
+		{
+			name:     "default columns",
+			builder:  NewUpdater[Product](mysqlDB).Update(p).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `product` SET `id`=?,`price`=?,`quantity`=? WHERE `id`=?;",
+			wantArgs: []interface{}{int64(1), int64(10), int64(3), 1},
+		},
+		{
+			name:    "set generated column",
+			builder: NewUpdater[Product](mysqlDB).Update(p).Set(C("Total")),
+			wantErr: err.NewGeneratedColumnError("Total"),
+		},
+		{
+			name:    "set generated columns",
+			builder: NewUpdater[Product](mysqlDB).Update(p).Set(Columns("Price", "Total")),
+			wantErr: err.NewGeneratedColumnError("Total"),
+		},
+		{
+			name:    "assign generated column",
+			builder: NewUpdater[Product](mysqlDB).Set(Assign("Total", 100)),
+			wantErr: err.NewGeneratedColumnError("Total"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestUpdater_Version(t *testing.T) {
+	type Account struct {
+		Id      int64
+		Balance int64
+		Version int64 `eorm:"version"`
+	}
+	mysqlMock, _, e := sqlmock.New()
+	require.NoError(t, e)
+	mysqlDB, e := openDB("mysql", mysqlMock)
+	require.NoError(t, e)
+	acc := &Account{Id: 1, Balance: 100, Version: 3}
+	testCases := []CommonTestCase{
+		{
+			name:     "default columns",
+			builder:  NewUpdater[Account](mysqlDB).Update(acc).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `account` SET `id`=?,`balance`=?,`version`=`version`+1 WHERE (`id`=?) AND (`version`=?);",
+			wantArgs: []interface{}{int64(1), int64(100), 1, int64(3)},
+		},
+		{
+			name:     "set columns",
+			builder:  NewUpdater[Account](postgresDB()).Update(acc).Set(C("Balance")).Where(C("Id").EQ(1)),
+			wantSql:  `UPDATE "account" SET "balance"=$1,"version"="version"+1 WHERE ("id"=$2) AND ("version"=$3);`,
+			wantArgs: []interface{}{int64(100), 1, int64(3)},
+		},
+		{
+			name:     "without entity",
+			builder:  NewUpdater[Account](mysqlDB).Set(Assign("Balance", 0)).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `account` SET `balance`=? WHERE `id`=?;",
+			wantArgs: []interface{}{0, 1},
+		},
+		{
+			name:    "assign version",
+			builder: NewUpdater[Account](mysqlDB).Update(acc).Set(Columns("Balance", "Version")),
+			wantErr: err.NewVersionColumnAssignedError("Version"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestUpdater_VersionExec(t *testing.T) {
+	type Account struct {
+		Id      int64
+		Balance int64
+		Version int64 `eorm:"version"`
+	}
+	mockDB, mock, e := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, e)
+	defer func() { _ = mockDB.Close() }()
+	db, e := openDB("mysql", mockDB)
+	require.NoError(t, e)
+
+	query := "UPDATE `account` SET `balance`=?,`version`=`version`+1 WHERE (`id`=?) AND (`version`=?);"
+	testCases := []struct {
+		name         string
+		affected     int64
+		wantErr      error
+		wantAffected int64
+	}{
+		{
+			name:         "updated",
+			affected:     1,
+			wantAffected: 1,
+		},
+		{
+			name:    "conflict",
+			wantErr: ErrOptimisticLock,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock.ExpectExec(query).WithArgs(int64(200), 1, int64(3)).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+			res := NewUpdater[Account](db).Update(&Account{Id: 1, Balance: 200, Version: 3}).
+				Set(C("Balance")).Where(C("Id").EQ(1)).Exec(context.Background())
+			assert.Equal(t, tc.wantErr, res.Err())
+			if tc.wantErr != nil {
+				return
+			}
+			affected, e := res.RowsAffected()
+			require.NoError(t, e)
+			assert.Equal(t, tc.wantAffected, affected)
+		})
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdater_SetIfChanged(t *testing.T) {
+	db := memoryDB()
+	pg := postgresDB()
+	mockDB, _, e := sqlmock.New()
+	require.NoError(t, e)
+	defer func() { _ = mockDB.Close() }()
+	mysqlDB, e := openDB("mysql", mockDB)
+	require.NoError(t, e)
+	type Account struct {
+		Id      int64
+		Balance int64
+		Rev     int64
+	}
+	testCases := []CommonTestCase{
+		{
+			name:     "single",
+			builder:  NewUpdater[TestModel](db).SetIfChanged(Assign("Age", 18)).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `test_model` SET `age`=? WHERE (`id`=?) AND (`age` IS NOT ?);",
+			wantArgs: []interface{}{18, 1, 18},
+		},
+		{
+			name: "multiple",
+			builder: NewUpdater[TestModel](db).
+				SetIfChanged(Assign("Age", 18), Assign("FirstName", "Tom")).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `test_model` SET `age`=?,`first_name`=? WHERE ((`id`=?) AND (`age` IS NOT ?)) AND (`first_name` IS NOT ?);",
+			wantArgs: []interface{}{18, "Tom", 1, 18, "Tom"},
+		},
+		{
+			name: "with set",
+			builder: NewUpdater[TestModel](db).Set(Assign("LastName", "Jerry")).
+				SetIfChanged(Assign("Age", 18)).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `test_model` SET `last_name`=?,`age`=? WHERE (`id`=?) AND (`age` IS NOT ?);",
+			wantArgs: []interface{}{"Jerry", 18, 1, 18},
+		},
+		{
+			name:     "without where",
+			builder:  NewUpdater[TestModel](db).SetIfChanged(Assign("Age", 18)),
+			wantSql:  "UPDATE `test_model` SET `age`=? WHERE `age` IS NOT ?;",
+			wantArgs: []interface{}{18, 18},
+		},
+		{
+			name:     "null value",
+			builder:  NewUpdater[TestModel](db).SetIfChanged(Assign("LastName", nil)).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `test_model` SET `last_name`=? WHERE (`id`=?) AND (`last_name` IS NOT ?);",
+			wantArgs: []interface{}{nil, 1, nil},
+		},
+		{
+			name:     "mysql",
+			builder:  NewUpdater[TestModel](mysqlDB).SetIfChanged(Assign("Age", 18)).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `test_model` SET `age`=? WHERE (`id`=?) AND (NOT (`age` <=> ?));",
+			wantArgs: []interface{}{18, 1, 18},
+		},
+		{
+			name:     "postgres",
+			builder:  NewUpdater[TestModel](pg).SetIfChanged(Assign("Age", 18)).Where(C("Id").EQ(1)),
+			wantSql:  `UPDATE "test_model" SET "age"=$1 WHERE ("id"=$2) AND ("age" IS DISTINCT FROM $3);`,
+			wantArgs: []interface{}{18, 1, 18},
+		},
+		{
+			// 没有变化的时候不会更新到数据，会被误认为是版本号冲突，所以不加上 SetIfChanged 的条件
+			name: "optimistic lock",
+			builder: NewUpdater[Account](db).SetIfChanged(Assign("Balance", 200)).
+				Where(C("Id").EQ(1)).OptimisticLock("Rev", 3),
+			wantSql:  "UPDATE `account` SET `balance`=?,`rev`=`rev`+1 WHERE (`id`=?) AND (`rev`=?);",
+			wantArgs: []interface{}{200, 1, 3},
+		},
+		{
+			name:    "invalid column",
+			builder: NewUpdater[TestModel](db).SetIfChanged(Assign("Invalid", 18)),
+			wantErr: err.NewInvalidFieldError("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, e := c.builder.Build()
+			assert.Equal(t, c.wantErr, e)
+			if e != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestUpdater_SetIfChanged_keepAssigns(t *testing.T) {
+	u := NewUpdater[TestModel](memoryDB()).Set(Assign("LastName", "Jerry")).
+		SetIfChanged(Assign("Age", 18)).Where(C("Id").EQ(1))
+	_, e := u.Build()
+	require.NoError(t, e)
+	// Build 不会把 SetIfChanged 的赋值追加到 Set 的赋值里面
+	assert.Equal(t, []Assignable{Assign("LastName", "Jerry")}, u.assigns)
+}
+
+func TestUpdater_OptimisticLock(t *testing.T) {
+	type Account struct {
+		Id      int64
+		Balance int64
+		Rev     int64
+	}
+	mockDB, mock, e := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, e)
+	defer func() { _ = mockDB.Close() }()
+	db, e := openDB("mysql", mockDB)
+	require.NoError(t, e)
+	acc := &Account{Id: 1, Balance: 100, Rev: 3}
+
+	testCases := []CommonTestCase{
+		{
+			name:     "set columns",
+			builder:  NewUpdater[Account](db).Set(Assign("Balance", 200)).Where(C("Id").EQ(1)).OptimisticLock("Rev", 3),
+			wantSql:  "UPDATE `account` SET `balance`=?,`rev`=`rev`+1 WHERE (`id`=?) AND (`rev`=?);",
+			wantArgs: []interface{}{200, 1, 3},
+		},
+		{
+			name:     "default columns",
+			builder:  NewUpdater[Account](db).Update(acc).OptimisticLock("Rev", int64(3)),
+			wantSql:  "UPDATE `account` SET `id`=?,`balance`=?,`rev`=`rev`+1 WHERE `rev`=?;",
+			wantArgs: []interface{}{int64(1), int64(100), int64(3)},
+		},
+		{
+			name:    "assign version",
+			builder: NewUpdater[Account](db).Set(Assign("Rev", 4)).OptimisticLock("Rev", 3),
+			wantErr: err.NewVersionColumnAssignedError("Rev"),
+		},
+		{
+			name:    "invalid field",
+			builder: NewUpdater[Account](db).Set(Assign("Balance", 200)).OptimisticLock("Invalid", 3),
+			wantErr: err.NewInvalidFieldError("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, e := c.builder.Build()
+			assert.Equal(t, c.wantErr, e)
+			if e != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+
+	query := "UPDATE `account` SET `balance`=?,`rev`=`rev`+1 WHERE (`id`=?) AND (`rev`=?);"
+	mock.ExpectExec(query).WithArgs(200, 1, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	res := NewUpdater[Account](db).Set(Assign("Balance", 200)).Where(C("Id").EQ(1)).
+		OptimisticLock("Rev", 3).Exec(context.Background())
+	require.NoError(t, res.Err())
+
+	// 版本号已经变了，没有更新到数据
+	mock.ExpectExec(query).WithArgs(200, 1, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	res = NewUpdater[Account](db).Set(Assign("Balance", 200)).Where(C("Id").EQ(1)).
+		OptimisticLock("Rev", 3).Exec(context.Background())
+	assert.Equal(t, ErrOptimisticLock, res.Err())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdater_Timestamps(t *testing.T) {
+	type Article struct {
+		Id        int64
+		Title     string
+		CreatedAt time.Time `eorm:"created_at"`
+		UpdatedAt uint64    `eorm:"updated_at"`
+	}
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	mysqlMock, _, e := sqlmock.New()
+	require.NoError(t, e)
+	mysqlDB, e := openDB("mysql", mysqlMock, DBWithClock(func() time.Time { return now }))
+	require.NoError(t, e)
+	a := &Article{Id: 1, Title: "eorm", CreatedAt: created, UpdatedAt: 10}
+	testCases := []CommonTestCase{
+		{
+			name:     "default columns",
+			builder:  NewUpdater[Article](mysqlDB).Update(a).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `article` SET `id`=?,`title`=?,`created_at`=?,`updated_at`=? WHERE `id`=?;",
+			wantArgs: []interface{}{int64(1), "eorm", created, uint64(now.Unix()), 1},
+		},
+		{
+			name:     "set columns",
+			builder:  NewUpdater[Article](mysqlDB).Update(a).Set(C("Title")).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `article` SET `title`=?,`updated_at`=? WHERE `id`=?;",
+			wantArgs: []interface{}{"eorm", uint64(now.Unix()), 1},
+		},
+		{
+			name:     "assign updated_at",
+			builder:  NewUpdater[Article](mysqlDB).Set(Assign("Title", "orm"), Assign("UpdatedAt", 20)),
+			wantSql:  "UPDATE `article` SET `title`=?,`updated_at`=?;",
+			wantArgs: []interface{}{"orm", 20},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestUpdater_IgnoreTag(t *testing.T) {
+	type Order struct {
+		Id    int64
+		Price int64
+		Total int64 `eorm:"-"`
+	}
+	db := memoryDB()
+	o := &Order{Id: 1, Price: 10, Total: 30}
+	testCases := []CommonTestCase{
+		{
+			name:     "default columns",
+			builder:  NewUpdater[Order](db).Update(o).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `order` SET `id`=?,`price`=? WHERE `id`=?;",
+			wantArgs: []interface{}{int64(1), int64(10), 1},
+		},
+		{
+			name:    "set ignored field",
+			builder: NewUpdater[Order](db).Update(o).Set(C("Total")),
+			wantErr: err.NewInvalidFieldError("Total"),
+		},
+		{
+			name:    "where ignored field",
+			builder: NewUpdater[Order](db).Update(o).Set(C("Price")).Where(C("Total").EQ(30)),
+			wantErr: err.NewInvalidFieldError("Total"),
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func TestUpdater_JSON(t *testing.T) {
+	type Profile struct {
+		Id   int64
+		Meta map[string]any `eorm:"json"`
+		Tags []string       `eorm:"json"`
+	}
+	db := memoryDB()
+	p := &Profile{Id: 1, Meta: map[string]any{"age": 18}, Tags: []string{"a"}}
+	testCases := []CommonTestCase{
+		{
+			name:     "default columns",
+			builder:  NewUpdater[Profile](db).Update(p).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `profile` SET `id`=?,`meta`=?,`tags`=? WHERE `id`=?;",
+			wantArgs: []interface{}{int64(1), `{"age":18}`, `["a"]`, 1},
+		},
+		{
+			name:     "set columns",
+			builder:  NewUpdater[Profile](db).Update(p).Set(Columns("Meta", "Tags")),
+			wantSql:  "UPDATE `profile` SET `meta`=?,`tags`=?;",
+			wantArgs: []interface{}{`{"age":18}`, `["a"]`},
+		},
+		{
+			name:     "assign",
+			builder:  NewUpdater[Profile](db).Update(p).Set(C("Tags"), Assign("Meta", map[string]any{"age": 20})),
+			wantSql:  "UPDATE `profile` SET `tags`=?,`meta`=?;",
+			wantArgs: []interface{}{`["a"]`, `{"age":20}`},
+		},
+	}
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, e := c.builder.Build()
+			assert.Equal(t, c.wantErr, e)
+			if e != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
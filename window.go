@@ -0,0 +1,113 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+// WindowSpec 是 OVER 子句的组成部分，目前有 Partition 和 OrderBy
+type WindowSpec interface {
+	windowSpec()
+}
+
+// PartitionBy represents PARTITION BY
+type PartitionBy struct {
+	fields []string
+}
+
+// Partition 指定窗口的分区字段
+func Partition(fields ...string) PartitionBy {
+	return PartitionBy{fields: fields}
+}
+
+func (PartitionBy) windowSpec() {}
+
+func (OrderBy) windowSpec() {}
+
+// WindowFunc represents window function, e.g. ROW_NUMBER() OVER (PARTITION BY ... ORDER BY ...)
+type WindowFunc struct {
+	fn string
+	// arg 是聚合函数的参数，例如 SUM(`age`) OVER () 中的 Age
+	// ROW_NUMBER 之类的排序函数没有参数
+	arg string
+	// buckets 是 NTILE 的分组数量
+	buckets   int
+	partition []string
+	orderBy   []OrderBy
+	alias     string
+	// distinct 和 filter 来自于通过 Over 转化的聚合函数
+	distinct bool
+	filter   []Predicate
+}
+
+// RowNumber represents ROW_NUMBER()
+func RowNumber() WindowFunc {
+	return WindowFunc{fn: "ROW_NUMBER"}
+}
+
+// Rank represents RANK()
+func Rank() WindowFunc {
+	return WindowFunc{fn: "RANK"}
+}
+
+// DenseRank represents DENSE_RANK()
+func DenseRank() WindowFunc {
+	return WindowFunc{fn: "DENSE_RANK"}
+}
+
+// Ntile represents NTILE(n)，将数据按照窗口的顺序分为 n 组，例如四分位数
+// n 是整数，所以会直接作为字面量写入 SQL，而不是作为参数
+func Ntile(n int) WindowFunc {
+	return WindowFunc{fn: "NTILE", buckets: n}
+}
+
+// Over 将聚合函数作为窗口函数使用，例如 Sum("Age").Over() 对应 SUM(`age`) OVER ()
+// 聚合函数上的 Filter 会被保留，例如 COUNT(`id`) FILTER (WHERE ...) OVER ()；
+// 但是窗口函数不支持 DISTINCT，所以 CountDistinct 之类的聚合函数会在构造的时候返回错误
+func (a Aggregate) Over(specs ...WindowSpec) WindowFunc {
+	return WindowFunc{fn: a.fn, arg: a.arg, alias: a.alias, distinct: a.distinct, filter: a.filter}.Over(specs...)
+}
+
+// Over 指定窗口，例如 RowNumber().Over(Partition("Age"), ASC("Id"))
+func (w WindowFunc) Over(specs ...WindowSpec) WindowFunc {
+	for _, spec := range specs {
+		switch s := spec.(type) {
+		case PartitionBy:
+			w.partition = append(w.partition, s.fields...)
+		case OrderBy:
+			w.orderBy = append(w.orderBy, s)
+		}
+	}
+	return w
+}
+
+// As specifies the alias
+func (w WindowFunc) As(alias string) Selectable {
+	w.alias = alias
+	return w
+}
+
+func (w WindowFunc) selectedAlias() string {
+	return w.alias
+}
+
+func (WindowFunc) selectedTable() TableReference {
+	return nil
+}
+
+func (WindowFunc) fieldName() string {
+	return ""
+}
+
+func (WindowFunc) expr() (string, error) {
+	return "", nil
+}
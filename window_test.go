@@ -0,0 +1,188 @@
+// Copyright 2021 gotomicro
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gotomicro/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowFunc(t *testing.T) {
+	db := memoryDB()
+	mysqlMock, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mysqlDB, err := openDB("mysql", mysqlMock)
+	require.NoError(t, err)
+	sub := NewSelector[TestModel](db).
+		Select(C("Id"), RowNumber().Over(Partition("Age"), ASC("Id")).As("rn")).AsSubquery("sub")
+	testCases := []CommonTestCase{
+		{
+			name:    "row number",
+			builder: NewSelector[TestModel](db).Select(C("Id"), RowNumber().Over(Partition("Age"), ASC("Id")).As("rn")),
+			wantSql: "SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `age` ORDER BY `id` ASC) AS `rn` FROM `test_model`;",
+		},
+		{
+			name:    "rank",
+			builder: NewSelector[TestModel](db).Select(C("Id"), Rank().Over(DESC("Age")).As("rk")),
+			wantSql: "SELECT `id`,RANK() OVER (ORDER BY `age` DESC) AS `rk` FROM `test_model`;",
+		},
+		{
+			name:    "dense rank",
+			builder: NewSelector[TestModel](db).Select(C("Id"), DenseRank().Over(Partition("FirstName", "LastName"), DESC("Age"), ASC("Id"))),
+			wantSql: "SELECT `id`,DENSE_RANK() OVER (PARTITION BY `first_name`,`last_name` ORDER BY `age` DESC,`id` ASC) FROM `test_model`;",
+		},
+		{
+			name:    "empty over",
+			builder: NewSelector[TestModel](db).Select(C("Id"), RowNumber().Over().As("rn")),
+			wantSql: "SELECT `id`,ROW_NUMBER() OVER () AS `rn` FROM `test_model`;",
+		},
+		{
+			name:    "partition only",
+			builder: NewSelector[TestModel](db).Select(RowNumber().Over(Partition("Age"))),
+			wantSql: "SELECT ROW_NUMBER() OVER (PARTITION BY `age`) FROM `test_model`;",
+		},
+		{
+			name:    "postgres",
+			builder: NewSelector[TestModel](postgresDB()).Select(C("Id"), RowNumber().Over(Partition("Age"), ASC("Id")).As("rn")),
+			wantSql: `SELECT "id",ROW_NUMBER() OVER (PARTITION BY "age" ORDER BY "id" ASC) AS "rn" FROM "test_model";`,
+		},
+		{
+			name: "having window alias",
+			builder: NewSelector[TestModel](db).Select(C("Age"), RowNumber().Over(Partition("Age"), ASC("Id")).As("rn")).
+				GroupBy("Age").Having(C("rn").EQ(1)),
+			wantErr: errs.NewWindowAliasInHavingError("rn"),
+		},
+		{
+			name: "having window alias in and",
+			builder: NewSelector[TestModel](db).Select(C("Age"), Avg("Id").As("avg_id"), Rank().Over(DESC("Age")).As("rk")).
+				GroupBy("Age").Having(C("avg_id").GT(1).And(Not(C("rk").LT(3)))),
+			wantErr: errs.NewWindowAliasInHavingError("rk"),
+		},
+		{
+			name:     "filter window alias in outer query",
+			builder:  NewSelector[TestModel](db).From(sub).Select(C("Id")).Where(sub.C("rn").EQ(1)),
+			wantSql:  "SELECT `id` FROM (SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `age` ORDER BY `id` ASC) AS `rn` FROM `test_model`) AS `sub` WHERE `sub`.`rn`=?;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "aggregate window",
+			builder: NewSelector[TestModel](db).Select(C("Id"), Sum("Age").Over(Partition("FirstName")).As("total"), CountAll().Over()),
+			wantSql: "SELECT `id`,SUM(`age`) OVER (PARTITION BY `first_name`) AS `total`,COUNT(*) OVER () FROM `test_model`;",
+		},
+		{
+			name: "percentage of total",
+			builder: NewSelector[TestModel](db).
+				Select(C("Id"), C("Age").Multi(100.0).Div(Sum("Age").Over()).As("pct")),
+			wantSql:  "SELECT `id`,(`age`*?)/SUM(`age`) OVER () AS `pct` FROM `test_model`;",
+			wantArgs: []interface{}{100.0},
+		},
+		{
+			name: "postgres percentage of total",
+			builder: NewSelector[TestModel](postgresDB()).
+				Select(C("Id"), C("Age").Multi(100.0).Div(Sum("Age").Over(Partition("FirstName"))).As("pct")).
+				Where(C("Age").GT(18)),
+			wantSql:  `SELECT "id",("age"*$1)/SUM("age") OVER (PARTITION BY "first_name") AS "pct" FROM "test_model" WHERE "age">$2;`,
+			wantArgs: []interface{}{100.0, 18},
+		},
+		{
+			name: "having percentage alias",
+			builder: NewSelector[TestModel](db).Select(C("Age"), C("Age").Multi(100).Div(Sum("Age").Over()).As("pct")).
+				GroupBy("Age").Having(C("pct").GT(10)),
+			wantErr: errs.NewWindowAliasInHavingError("pct"),
+		},
+		{
+			name: "postgres aggregate filter window",
+			builder: NewSelector[TestModel](postgresDB()).
+				Select(C("Id"), Count("Id").Filter(C("Age").GT(18)).Over(Partition("FirstName")).As("adults")),
+			wantSql:  `SELECT "id",COUNT("id") FILTER (WHERE "age">$1) OVER (PARTITION BY "first_name") AS "adults" FROM "test_model";`,
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "mysql aggregate filter window",
+			builder: NewSelector[TestModel](mysqlDB).
+				Select(C("Id"), Count("Id").Filter(C("Age").GT(18)).Over().As("adults")),
+			wantSql:  "SELECT `id`,COUNT(CASE WHEN `age`>? THEN `id` END) OVER () AS `adults` FROM `test_model`;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:    "distinct aggregate window",
+			builder: NewSelector[TestModel](db).Select(CountDistinct("Age").Over(Partition("FirstName"))),
+			wantErr: errs.ErrWindowWithDistinct,
+		},
+		{
+			name:    "invalid aggregate window",
+			builder: NewSelector[TestModel](db).Select(Sum("Invalid").Over()),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "ntile",
+			builder: NewSelector[TestModel](db).Select(C("Id"), Ntile(4).Over(OrderBy(ASC("Age"))).As("quartile")),
+			wantSql: "SELECT `id`,NTILE(4) OVER (ORDER BY `age` ASC) AS `quartile` FROM `test_model`;",
+		},
+		{
+			name: "postgres ntile",
+			builder: NewSelector[TestModel](postgresDB()).
+				Select(C("Id"), Ntile(10).Over(Partition("FirstName"), DESC("Age")).As("decile")).Where(C("Age").GT(18)),
+			wantSql:  `SELECT "id",NTILE(10) OVER (PARTITION BY "first_name" ORDER BY "age" DESC) AS "decile" FROM "test_model" WHERE "age">$1;`,
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:    "ntile invalid buckets",
+			builder: NewSelector[TestModel](db).Select(Ntile(0).Over(ASC("Age"))),
+			wantErr: errs.ErrInvalidNtileBuckets,
+		},
+		{
+			name:    "ntile invalid order by",
+			builder: NewSelector[TestModel](db).Select(Ntile(4).Over(ASC("Invalid"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid partition",
+			builder: NewSelector[TestModel](db).Select(RowNumber().Over(Partition("Invalid"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid order by",
+			builder: NewSelector[TestModel](db).Select(RowNumber().Over(Partition("Age"), ASC("Invalid"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
+func ExampleRowNumber() {
+	db := memoryDB()
+	query, _ := NewSelector[TestModel](db).
+		Select(C("Id"), RowNumber().Over(Partition("Age"), ASC("Id")).As("rn")).Build()
+	fmt.Println(query.SQL)
+	// Output: SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `age` ORDER BY `id` ASC) AS `rn` FROM `test_model`;
+}